@@ -0,0 +1,180 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+// biggest archive accepted by the import
+const maxImportSize = 100 << 20
+
+type ArchiveHandler struct {
+	ArchiveUsecase domain.ArchiveUsecase
+}
+
+func NewArchiveHandler(r *chi.Mux, u domain.ArchiveUsecase) {
+	handler := &ArchiveHandler{
+		ArchiveUsecase: u,
+	}
+
+	// make group v1
+	r.Route("/archive", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(middleware.MyMiddleware)
+			r.Get("/export", helpers.RecoverWrap(handler.ExportWorkspace))
+			r.Post("/import", helpers.RecoverWrap(handler.ImportArchive))
+			r.Post("/import/enex", helpers.RecoverWrap(handler.ImportEnex))
+			r.Post("/import/obsidian", helpers.RecoverWrap(handler.ImportObsidian))
+		})
+	})
+
+}
+
+func (a ArchiveHandler) ExportWorkspace(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	filename := fmt.Sprintf("notes-%s.zip", time.Now().Format("20060102"))
+
+	// the zip is streamed straight to the client
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	// call usecase, the status is already sent so a failure can only cut the zip short
+	err = a.ArchiveUsecase.ExportWorkspace(r.Context(), credentials.ID, w)
+	if err != nil {
+		log.Printf("export of user %d failed: %v", credentials.ID, err)
+	}
+}
+
+func (a ArchiveHandler) ImportArchive(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get the zip from the multipart form, big uploads are kept on disk
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	// call usecase
+	report, err := a.ArchiveUsecase.ImportArchive(r.Context(), credentials.ID, file, header.Size)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "archive imported",
+		Data: map[string]interface{}{
+			"report": report,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (a ArchiveHandler) ImportEnex(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get the enex from the multipart form, big uploads are kept on disk
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	// the notebook is named after the file unless given
+	notebook := r.FormValue("notebook")
+	if notebook == "" {
+		notebook = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
+	}
+
+	// call usecase
+	report, err := a.ArchiveUsecase.ImportEnex(r.Context(), credentials.ID, notebook, file)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "enex imported",
+		Data: map[string]interface{}{
+			"report": report,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (a ArchiveHandler) ImportObsidian(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get the zipped vault from the multipart form, big uploads are kept on disk
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	// call usecase
+	report, err := a.ArchiveUsecase.ImportObsidian(r.Context(), credentials.ID, file, header.Size)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "vault imported",
+		Data: map[string]interface{}{
+			"report": report,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
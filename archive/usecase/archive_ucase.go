@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+)
+
+// notes are saved with this extension in the archive
+const noteExtension = ".md"
+
+// bigger files in an imported archive are reported as errors
+const maxImportNoteSize = 10 << 20
+
+type ArchiveUseCaseImpl struct {
+	FileRepo      domain.FileRepo
+	NoteRepo      domain.NoteRepo
+	FolderUsecase domain.FolderUsecase
+	NoteUsecase   domain.NoteUsecase
+	TagUsecase    domain.TagUsecase
+	// AttachmentUsecase keeps the images embedded in the imported notes
+	AttachmentUsecase domain.AttachmentUsecase
+}
+
+// ExportWorkspace implements domain.ArchiveUsecase
+func (a ArchiveUseCaseImpl) ExportWorkspace(ctx context.Context, userID int, w io.Writer) error {
+	// only the file rows are loaded up front, the content of every note
+	// is read right before it is written so big workspaces stay out of memory
+	files, err := a.FileRepo.GetFileTree(ctx, userID, "", math.MaxInt32)
+	if err != nil {
+		return err
+	}
+
+	// parents come before their children
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	zw := zip.NewWriter(w)
+
+	for _, file := range files {
+		if file.Type == domain.FileTypeFolder {
+			// keep empty folders in the archive
+			_, err = zw.CreateHeader(&zip.FileHeader{
+				Name:     archivePath(file.Path) + "/",
+				Method:   zip.Store,
+				Modified: file.UpdatedAt.ValueOrZero(),
+			})
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err = a.exportNote(ctx, zw, userID, file)
+		if err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func (a ArchiveUseCaseImpl) exportNote(ctx context.Context, zw *zip.Writer, userID int, file domain.File) error {
+	note, err := a.NoteRepo.FindNote(ctx, userID, file.ShaID)
+	if err != nil {
+		return err
+	}
+
+	createdAt, updatedAt := note.CreatedAt.UTC(), note.UpdatedAt.UTC()
+	content, err := helpers.WithFrontMatter(helpers.FrontMatter{
+		ShaID:     note.ShaID,
+		CreatedAt: &createdAt,
+		UpdatedAt: &updatedAt,
+	}, note.Note.ValueOrZero())
+	if err != nil {
+		return err
+	}
+
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     archivePath(note.Path) + noteExtension,
+		Method:   zip.Deflate,
+		Modified: note.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(fw, content)
+	return err
+}
+
+// ImportArchive implements domain.ArchiveUsecase
+func (a ArchiveUseCaseImpl) ImportArchive(ctx context.Context, userID int, r io.ReaderAt, size int64) (domain.ArchiveImportReport, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return domain.ArchiveImportReport{}, fmt.Errorf("%w: not a zip archive", domain.ErrBadParamInput)
+	}
+
+	report := domain.ArchiveImportReport{
+		Skipped: []string{},
+		Errors:  []domain.ArchiveImportError{},
+	}
+	imp := newImporter(a, userID, &report)
+
+	for _, entry := range sortedZipEntries(zr) {
+		filePath := zipEntryPath(entry)
+		if filePath == "/" {
+			continue
+		}
+
+		// metadata of the os that made the archive, not part of the notes
+		if isHiddenPath(filePath) {
+			imp.skip(filePath)
+			continue
+		}
+
+		if entry.FileInfo().IsDir() {
+			_, err = imp.ensureFolder(ctx, filePath)
+			if err != nil {
+				imp.fail(filePath, err)
+			}
+
+			continue
+		}
+
+		if !strings.EqualFold(path.Ext(filePath), noteExtension) {
+			imp.skip(filePath)
+			continue
+		}
+
+		err = a.importNote(ctx, imp, entry, filePath)
+		if err != nil {
+			imp.fail(filePath, err)
+		}
+	}
+
+	return report, nil
+}
+
+func (a ArchiveUseCaseImpl) importNote(ctx context.Context, imp *importer, entry *zip.File, filePath string) error {
+	data, err := readZipEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	fm, body, err := helpers.ParseFrontMatter(data)
+	if err != nil {
+		return err
+	}
+
+	notePath := strings.TrimSuffix(filePath, path.Ext(filePath))
+	_, err = imp.putNote(ctx, notePath, body, fm.CreatedAt, fm.UpdatedAt)
+	return err
+}
+
+// parents come before their children
+func sortedZipEntries(zr *zip.Reader) []*zip.File {
+	entries := append([]*zip.File{}, zr.File...)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
+
+// the entry as an absolute path, archives made on windows may use backslashes
+func zipEntryPath(entry *zip.File) string {
+	return path.Clean("/" + strings.ReplaceAll(entry.Name, "\\", "/"))
+}
+
+// read a note of the archive, refusing files too big to be a note
+func readZipEntry(entry *zip.File) (string, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxImportNoteSize+1))
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) > maxImportNoteSize {
+		return "", fmt.Errorf("%w: file is bigger than %d bytes", domain.ErrBadParamInput, maxImportNoteSize)
+	}
+
+	return string(data), nil
+}
+
+// zipEntryChecksum is the sha256 of the content of the entry, the way the
+// checksum of an attachment is made
+func zipEntryChecksum(entry *zip.File) (string, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	// a bigger file is refused by the upload, it is not read to the end
+	hash := sha256.New()
+	_, err = io.Copy(hash, io.LimitReader(rc, domain.MaxAttachmentSize+1))
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// files and folders starting with a dot, and the __MACOSX folder of finder
+func isHiddenPath(filePath string) bool {
+	for _, part := range strings.Split(strings.TrimPrefix(filePath, "/"), "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// zip entries are relative, files.path always starts with /
+func archivePath(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
+
+func NewArchiveUseCase(fr domain.FileRepo, nr domain.NoteRepo, fu domain.FolderUsecase, nu domain.NoteUsecase, tu domain.TagUsecase, au domain.AttachmentUsecase) domain.ArchiveUsecase {
+	return &ArchiveUseCaseImpl{
+		FileRepo:          fr,
+		NoteRepo:          nr,
+		FolderUsecase:     fu,
+		NoteUsecase:       nu,
+		TagUsecase:        tu,
+		AttachmentUsecase: au,
+	}
+}
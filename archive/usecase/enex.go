@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+)
+
+// layout of the created and updated dates of an enex note
+const enexTimeLayout = "20060102T150405Z"
+
+// notebook folder used when the caller does not name one
+const defaultEnexNotebook = "Evernote"
+
+type enexNote struct {
+	Title     string         `xml:"title"`
+	Content   string         `xml:"content"`
+	Created   string         `xml:"created"`
+	Updated   string         `xml:"updated"`
+	Tags      []string       `xml:"tag"`
+	Resources []enexResource `xml:"resource"`
+}
+
+// a resource is a file of the note, its en-media in the content has the md5 of its data
+type enexResource struct {
+	Mime string `xml:"mime"`
+	// Data is base64 encoded and wrapped on several lines
+	Data     string `xml:"data"`
+	FileName string `xml:"resource-attributes>file-name"`
+}
+
+// ImportEnex implements domain.ArchiveUsecase
+func (a ArchiveUseCaseImpl) ImportEnex(ctx context.Context, userID int, notebook string, r io.Reader) (domain.ArchiveImportReport, error) {
+	notebook = strings.TrimSpace(notebook)
+	if notebook == "" {
+		notebook = defaultEnexNotebook
+	}
+
+	report := domain.ArchiveImportReport{
+		Skipped: []string{},
+		Errors:  []domain.ArchiveImportError{},
+	}
+	imp := newImporter(a, userID, &report)
+
+	// an enex file is one notebook
+	notebookPath := helpers.JoinPath("", notebook)
+	_, err := imp.ensureFolder(ctx, notebookPath)
+	if err != nil {
+		return domain.ArchiveImportReport{}, err
+	}
+
+	// notes are decoded one by one so big exports are not read at once
+	d := xml.NewDecoder(r)
+	d.Entity = xml.HTMLEntity
+
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return report, fmt.Errorf("%w: invalid enex: %v", domain.ErrBadParamInput, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "note" {
+			continue
+		}
+
+		var note enexNote
+		err = d.DecodeElement(&note, &start)
+		if err != nil {
+			return report, fmt.Errorf("%w: invalid enex: %v", domain.ErrBadParamInput, err)
+		}
+
+		notePath := imp.uniqueNotePath(helpers.JoinPath(notebookPath, enexNoteName(note.Title)))
+
+		err = a.importEnexNote(ctx, imp, notePath, note)
+		if err != nil {
+			imp.fail(notePath, err)
+		}
+	}
+
+	return report, nil
+}
+
+func (a ArchiveUseCaseImpl) importEnexNote(ctx context.Context, imp *importer, notePath string, note enexNote) error {
+	// the note is found or created first, its resources are attached to it
+	content, err := enexMarkdown(note.Content, func(helpers.ENMLMedia) string { return "" })
+	if err != nil {
+		return err
+	}
+
+	created, err := parseEnexTime(note.Created)
+	if err != nil {
+		return err
+	}
+
+	updated, err := parseEnexTime(note.Updated)
+	if err != nil {
+		return err
+	}
+
+	// a note never updated only has its created date
+	if updated == nil {
+		updated = created
+	}
+
+	saved, found, err := imp.findNote(ctx, notePath)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		saved, err = imp.createNote(ctx, notePath, content, created, updated)
+		if err != nil {
+			return err
+		}
+	}
+
+	attachments, failed := a.attachEnexResources(ctx, imp, notePath, saved.ShaID, note.Resources)
+
+	// an en-media without its resource is reported and left out of the note
+	content, err = enexMarkdown(note.Content, func(media helpers.ENMLMedia) string {
+		hash := strings.ToLower(media.Hash)
+		if attachment, ok := attachments[hash]; ok {
+			return attachmentMarkdown(attachment)
+		}
+
+		if !failed[hash] {
+			imp.fail(notePath, fmt.Errorf("%w: no resource for the media %s", domain.ErrNotFound, media.Hash))
+		}
+
+		return ""
+	})
+	if err != nil {
+		return err
+	}
+
+	if found {
+		_, err = imp.updateNote(ctx, saved, content)
+	} else {
+		err = imp.saveCreatedNote(ctx, saved, content)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range note.Tags {
+		_, err = a.TagUsecase.AttachTag(ctx, imp.userID, saved.ShaID, tag)
+		if err != nil {
+			imp.fail(notePath, fmt.Errorf("tag %q: %w", tag, err))
+		}
+	}
+
+	return nil
+}
+
+// attachEnexResources uploads the resources of a note as its attachments, they
+// are returned by the md5 of their data with the md5 of the ones that failed
+func (a ArchiveUseCaseImpl) attachEnexResources(ctx context.Context, imp *importer, notePath string, noteShaID string, resources []enexResource) (map[string]domain.Attachment, map[string]bool) {
+	attachments := map[string]domain.Attachment{}
+	failed := map[string]bool{}
+
+	for i, resource := range resources {
+		name := strings.TrimSpace(resource.FileName)
+		if name == "" {
+			name = fmt.Sprintf("resource %d", i+1)
+			if exts, _ := mime.ExtensionsByType(resource.Mime); len(exts) > 0 {
+				name += exts[0]
+			}
+		}
+
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(resource.Data), ""))
+		if err != nil {
+			imp.fail(notePath+"/"+name, fmt.Errorf("%w: invalid resource data", domain.ErrBadParamInput))
+			continue
+		}
+
+		hash := md5.Sum(data)
+		checksum := sha256.Sum256(data)
+
+		attachment, err := imp.attach(ctx, noteShaID, name, hex.EncodeToString(checksum[:]), bytes.NewReader(data))
+		if err != nil {
+			failed[hex.EncodeToString(hash[:])] = true
+			imp.fail(notePath+"/"+name, err)
+			continue
+		}
+
+		attachments[hex.EncodeToString(hash[:])] = attachment
+	}
+
+	return attachments, failed
+}
+
+func enexMarkdown(enml string, media func(helpers.ENMLMedia) string) (string, error) {
+	content, err := helpers.ENMLToMarkdown(enml, media)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrBadParamInput, err)
+	}
+
+	return content, nil
+}
+
+func parseEnexTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(enexTimeLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date %q", domain.ErrBadParamInput, value)
+	}
+
+	return &t, nil
+}
+
+// the title becomes the file name, so it cannot have a slash or be too long
+func enexNoteName(title string) string {
+	name := strings.TrimSpace(strings.ReplaceAll(title, "/", "-"))
+	if name == "" || name == "." || name == ".." {
+		return "Untitled"
+	}
+
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+
+	return name
+}
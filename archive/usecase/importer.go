@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/guregu/null.v4"
+)
+
+// attachmentURL is where the content of an attachment is downloaded
+const attachmentURL = "/attachment/v1/"
+
+// the characters of a name that would end the text of a markdown link
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+// importer puts the files of one import into the folder tree of the user,
+// matching the folders and notes that already exist by their path
+type importer struct {
+	archive ArchiveUseCaseImpl
+	userID  int
+	report  *domain.ArchiveImportReport
+
+	// sha id of the folders already looked up, by path
+	folders map[string]string
+	// paths of the notes written by this import
+	notes map[string]bool
+	// attachments of the notes already looked up, by note sha id
+	attachments map[string][]domain.Attachment
+}
+
+func newImporter(a ArchiveUseCaseImpl, userID int, report *domain.ArchiveImportReport) *importer {
+	return &importer{
+		archive: a,
+		userID:  userID,
+		report:  report,
+		folders: map[string]string{"/": ""},
+		notes:   map[string]bool{},
+
+		attachments: map[string][]domain.Attachment{},
+	}
+}
+
+// ensureFolder returns the sha id of the folder at the path, creating
+// the missing folders on the way, the root is an empty sha id
+func (i *importer) ensureFolder(ctx context.Context, folderPath string) (string, error) {
+	if shaID, ok := i.folders[folderPath]; ok {
+		return shaID, nil
+	}
+
+	parentShaID, err := i.ensureFolder(ctx, path.Dir(folderPath))
+	if err != nil {
+		return "", err
+	}
+
+	file, err := i.archive.FileRepo.FindFileByPath(ctx, i.userID, folderPath)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+
+	if err == nil {
+		if file.Type != domain.FileTypeFolder {
+			return "", fmt.Errorf("%w: %s is a note, not a folder", domain.ErrConflict, folderPath)
+		}
+
+		i.folders[folderPath] = file.ShaID
+		return file.ShaID, nil
+	}
+
+	folder, err := i.archive.FolderUsecase.CreateFolder(ctx, i.userID, domain.Folder{
+		Name:        path.Base(folderPath),
+		ParentShaID: null.NewString(parentShaID, parentShaID != ""),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	i.report.FoldersCreated++
+	i.folders[folderPath] = folder.ShaID
+	return folder.ShaID, nil
+}
+
+// putNote creates the note at the path or updates the content of the note
+// already there, the timestamps are only used for new notes
+func (i *importer) putNote(ctx context.Context, notePath string, content string, createdAt, updatedAt *time.Time) (domain.Note, error) {
+	note, found, err := i.findNote(ctx, notePath)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	if !found {
+		return i.createNote(ctx, notePath, content, createdAt, updatedAt)
+	}
+
+	return i.updateNote(ctx, note, content)
+}
+
+// findNote looks up the note at the path, a folder at the path is a conflict
+func (i *importer) findNote(ctx context.Context, notePath string) (domain.Note, bool, error) {
+	file, err := i.archive.FileRepo.FindFileByPath(ctx, i.userID, notePath)
+	if err == sql.ErrNoRows {
+		return domain.Note{}, false, nil
+	}
+
+	if err != nil {
+		return domain.Note{}, false, err
+	}
+
+	if file.Type != domain.FileTypeNote {
+		return domain.Note{}, false, fmt.Errorf("%w: %s is a folder, not a note", domain.ErrConflict, notePath)
+	}
+
+	note, err := i.archive.NoteUsecase.FindNote(ctx, i.userID, file.ShaID)
+	if err != nil {
+		return domain.Note{}, false, err
+	}
+
+	return note, true, nil
+}
+
+func (i *importer) createNote(ctx context.Context, notePath string, content string, createdAt, updatedAt *time.Time) (domain.Note, error) {
+	folderShaID, err := i.ensureFolder(ctx, path.Dir(notePath))
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	note := domain.Note{
+		FolderShaID: null.NewString(folderShaID, folderShaID != ""),
+		Name:        path.Base(notePath),
+		Note:        null.StringFrom(content),
+	}
+
+	if createdAt != nil {
+		note.CreatedAt = *createdAt
+	}
+
+	if updatedAt != nil {
+		note.UpdatedAt = *updatedAt
+	}
+
+	note, err = i.archive.NoteUsecase.CreateNote(ctx, i.userID, note)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	i.report.NotesCreated++
+	return note, nil
+}
+
+// updateNote replaces the content of the note unless it is already the same
+func (i *importer) updateNote(ctx context.Context, note domain.Note, content string) (domain.Note, error) {
+	// nothing changed since the last import
+	if note.Note.ValueOrZero() == content {
+		i.report.NotesUnchanged++
+		return note, nil
+	}
+
+	// the version keeps an edit made during the import from being overwritten
+	note, err := i.archive.NoteUsecase.UpdateNote(ctx, i.userID, domain.Note{
+		ShaID: note.ShaID,
+		Note:  null.StringFrom(content),
+	}, helpers.ETag(note.UpdatedAt))
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	i.report.NotesUpdated++
+	return note, nil
+}
+
+// saveCreatedNote replaces the content of a note created by this import,
+// which is not counted as an update
+func (i *importer) saveCreatedNote(ctx context.Context, note domain.Note, content string) error {
+	if note.Note.ValueOrZero() == content {
+		return nil
+	}
+
+	_, err := i.archive.NoteUsecase.UpdateNote(ctx, i.userID, domain.Note{
+		ShaID: note.ShaID,
+		Note:  null.StringFrom(content),
+	}, helpers.ETag(note.UpdatedAt))
+	return err
+}
+
+// uniqueNotePath numbers the notes with the same path in one import,
+// the numbers are the same when the import is done again
+func (i *importer) uniqueNotePath(notePath string) string {
+	unique := notePath
+	for n := 2; i.notes[unique]; n++ {
+		unique = fmt.Sprintf("%s (%d)", notePath, n)
+	}
+
+	i.notes[unique] = true
+	return unique
+}
+
+// attach uploads the content as an attachment of the note, the attachment with
+// the same name and checksum made by an earlier import is used again
+func (i *importer) attach(ctx context.Context, noteShaID string, name string, checksum string, r io.Reader) (domain.Attachment, error) {
+	var err error
+	attachments, ok := i.attachments[noteShaID]
+	if !ok {
+		attachments, err = i.archive.AttachmentUsecase.GetNoteAttachments(ctx, i.userID, noteShaID)
+		if err != nil {
+			return domain.Attachment{}, err
+		}
+
+		i.attachments[noteShaID] = attachments
+	}
+
+	for _, v := range attachments {
+		if v.Name == name && v.Checksum == checksum {
+			return v, nil
+		}
+	}
+
+	attachment, err := i.archive.AttachmentUsecase.UploadAttachment(ctx, i.userID, noteShaID, name, r)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	i.report.AttachmentsCreated++
+	i.attachments[noteShaID] = append(attachments, attachment)
+	return attachment, nil
+}
+
+// attachmentMarkdown shows an image attachment in the note and links the other ones
+func attachmentMarkdown(attachment domain.Attachment) string {
+	link := "[" + markdownEscaper.Replace(attachment.Name) + "](" + attachmentURL + attachment.ShaID + ")"
+	if strings.HasPrefix(attachment.MimeType, "image/") {
+		return "!" + link
+	}
+
+	return link
+}
+
+func (i *importer) skip(filePath string) {
+	i.report.Skipped = append(i.report.Skipped, filePath)
+}
+
+func (i *importer) fail(filePath string, err error) {
+	i.report.Errors = append(i.report.Errors, domain.ArchiveImportError{
+		Path:  filePath,
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,407 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/yaml.v3"
+)
+
+// layouts tried for the created and updated properties
+var obsidianTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// obsidianProperties are the front matter properties the importer understands
+type obsidianProperties struct {
+	Tags    yamlStrings `yaml:"tags"`
+	Aliases yamlStrings `yaml:"aliases"`
+	Created string      `yaml:"created"`
+	Updated string      `yaml:"updated"`
+}
+
+// yamlStrings accepts a single value as well as a list
+type yamlStrings []string
+
+func (y *yamlStrings) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if value.Value != "" {
+			*y = yamlStrings{value.Value}
+		}
+
+		return nil
+	}
+
+	var list []string
+	err := value.Decode(&list)
+	if err != nil {
+		return err
+	}
+
+	*y = list
+	return nil
+}
+
+type obsidianNote struct {
+	entry *zip.File
+	// path of the note, without the extension
+	path    string
+	note    domain.Note
+	created bool
+	tags    []string
+	aliases []string
+}
+
+// obsidianVault resolves links the way obsidian does, by file name
+// unless the link has a folder in it
+type obsidianVault struct {
+	// notes by lowercased path without extension
+	notes map[string]*obsidianNote
+	// note paths by lowercased file name without extension
+	names   map[string][]string
+	aliases map[string]string
+	// files that are not notes, like images, by lowercased path
+	files map[string]*zip.File
+	// lowercased paths of the files embedded in a note
+	embedded map[string]bool
+}
+
+// ImportObsidian implements domain.ArchiveUsecase
+func (a ArchiveUseCaseImpl) ImportObsidian(ctx context.Context, userID int, r io.ReaderAt, size int64) (domain.ArchiveImportReport, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return domain.ArchiveImportReport{}, fmt.Errorf("%w: not a zip archive", domain.ErrBadParamInput)
+	}
+
+	report := domain.ArchiveImportReport{
+		Skipped:         []string{},
+		Errors:          []domain.ArchiveImportError{},
+		UnresolvedLinks: []domain.ArchiveUnresolvedLink{},
+	}
+	imp := newImporter(a, userID, &report)
+
+	vault := obsidianVault{
+		notes:    map[string]*obsidianNote{},
+		names:    map[string][]string{},
+		aliases:  map[string]string{},
+		files:    map[string]*zip.File{},
+		embedded: map[string]bool{},
+	}
+	notes := []*obsidianNote{}
+
+	// first every note is found or created so all the links have a sha id to point at
+	for _, entry := range sortedZipEntries(zr) {
+		filePath := zipEntryPath(entry)
+		if filePath == "/" || isHiddenPath(filePath) {
+			continue
+		}
+
+		if entry.FileInfo().IsDir() {
+			_, err = imp.ensureFolder(ctx, filePath)
+			if err != nil {
+				imp.fail(filePath, err)
+			}
+
+			continue
+		}
+
+		// the other files are uploaded with the notes that embed them
+		if !strings.EqualFold(path.Ext(filePath), noteExtension) {
+			vault.files[strings.ToLower(filePath)] = entry
+			continue
+		}
+
+		note, err := a.findOrCreateObsidianNote(ctx, imp, entry, filePath)
+		if err != nil {
+			imp.fail(filePath, err)
+			continue
+		}
+
+		notes = append(notes, note)
+		vault.add(note)
+	}
+
+	// then the links are rewritten and the content saved
+	for _, note := range notes {
+		err = a.saveObsidianNote(ctx, imp, vault, note)
+		if err != nil {
+			imp.fail(note.path+noteExtension, err)
+		}
+	}
+
+	// the files no note embeds are not imported
+	for _, entry := range sortedZipEntries(zr) {
+		key := strings.ToLower(zipEntryPath(entry))
+		if vault.files[key] == entry && !vault.embedded[key] {
+			imp.skip(zipEntryPath(entry))
+		}
+	}
+
+	return report, nil
+}
+
+func (a ArchiveUseCaseImpl) findOrCreateObsidianNote(ctx context.Context, imp *importer, entry *zip.File, filePath string) (*obsidianNote, error) {
+	body, props, err := readObsidianNote(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &obsidianNote{
+		entry:   entry,
+		path:    strings.TrimSuffix(filePath, path.Ext(filePath)),
+		tags:    props.Tags,
+		aliases: props.Aliases,
+	}
+
+	found, ok, err := imp.findNote(ctx, note.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		note.note = found
+		return note, nil
+	}
+
+	// a vault has no created date, the modified time of the file is the best guess
+	createdAt, updatedAt := entry.Modified, entry.Modified
+	if t, ok := parseObsidianTime(props.Created); ok {
+		createdAt, updatedAt = t, t
+	}
+
+	if t, ok := parseObsidianTime(props.Updated); ok {
+		updatedAt = t
+	}
+
+	note.note, err = imp.createNote(ctx, note.path, body, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	note.created = true
+	return note, nil
+}
+
+func (a ArchiveUseCaseImpl) saveObsidianNote(ctx context.Context, imp *importer, vault obsidianVault, note *obsidianNote) error {
+	body, _, err := readObsidianNote(note.entry)
+	if err != nil {
+		return err
+	}
+
+	// an embedded file that cannot be uploaded is reported and its embed left as it is
+	content, unresolved := vault.rewriteLinks(note.path, body, func(entry *zip.File) (domain.Attachment, bool) {
+		attachment, err := attachZipEntry(ctx, imp, note.note.ShaID, entry)
+		if err != nil {
+			imp.fail(zipEntryPath(entry), err)
+			return domain.Attachment{}, false
+		}
+
+		return attachment, true
+	})
+	for _, link := range unresolved {
+		imp.report.UnresolvedLinks = append(imp.report.UnresolvedLinks, domain.ArchiveUnresolvedLink{
+			Path: note.path + noteExtension,
+			Link: link,
+		})
+	}
+
+	if note.created {
+		err = imp.saveCreatedNote(ctx, note.note, content)
+	} else {
+		_, err = imp.updateNote(ctx, note.note, content)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range note.tags {
+		_, err = a.TagUsecase.AttachTag(ctx, imp.userID, note.note.ShaID, strings.TrimPrefix(tag, "#"))
+		if err != nil {
+			imp.fail(note.path+noteExtension, fmt.Errorf("tag %q: %w", tag, err))
+		}
+	}
+
+	return nil
+}
+
+// attachZipEntry uploads a file of the vault as an attachment of the note
+func attachZipEntry(ctx context.Context, imp *importer, noteShaID string, entry *zip.File) (domain.Attachment, error) {
+	checksum, err := zipEntryChecksum(entry)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	rc, err := entry.Open()
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	defer rc.Close()
+
+	return imp.attach(ctx, noteShaID, path.Base(zipEntryPath(entry)), checksum, rc)
+}
+
+// the markdown of the note without its properties, which are returned apart
+func readObsidianNote(entry *zip.File) (string, obsidianProperties, error) {
+	var props obsidianProperties
+
+	data, err := readZipEntry(entry)
+	if err != nil {
+		return "", props, err
+	}
+
+	body, err := helpers.UnmarshalFrontMatter(data, &props)
+	if err != nil {
+		return "", props, err
+	}
+
+	return body, props, nil
+}
+
+func parseObsidianTime(value string) (time.Time, bool) {
+	for _, layout := range obsidianTimeLayouts {
+		t, err := time.Parse(layout, strings.TrimSpace(value))
+		if err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (v obsidianVault) add(note *obsidianNote) {
+	key := strings.ToLower(note.path)
+	v.notes[key] = note
+
+	name := path.Base(key)
+	v.names[name] = append(v.names[name], key)
+
+	for _, alias := range note.aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if _, ok := v.aliases[alias]; !ok && alias != "" {
+			v.aliases[alias] = key
+		}
+	}
+}
+
+// resolve finds the note a link points at from the note at fromPath
+func (v obsidianVault) resolve(fromPath, target string) (*obsidianNote, bool) {
+	target = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(target), noteExtension))
+
+	// a link with a folder is matched against the end of the paths, the
+	// vault may be inside a folder of the archive
+	if strings.Contains(target, "/") {
+		target = "/" + strings.TrimPrefix(target, "/")
+
+		candidates := []string{}
+		for key := range v.notes {
+			if strings.HasSuffix(key, target) {
+				candidates = append(candidates, key)
+			}
+		}
+
+		return v.closest(fromPath, candidates)
+	}
+
+	if note, ok := v.closest(fromPath, v.names[target]); ok {
+		return note, true
+	}
+
+	if key, ok := v.aliases[target]; ok {
+		return v.notes[key], true
+	}
+
+	return nil, false
+}
+
+// closest prefers a note in the same folder, then the one with the shortest path
+func (v obsidianVault) closest(fromPath string, keys []string) (*obsidianNote, bool) {
+	if len(keys) == 0 {
+		return nil, false
+	}
+
+	sortClosest(fromPath, keys)
+	return v.notes[keys[0]], true
+}
+
+// sortClosest puts the paths in the folder of fromPath first, then the shortest ones
+func sortClosest(fromPath string, keys []string) {
+	dir := strings.ToLower(path.Dir(fromPath))
+	sort.Slice(keys, func(i, j int) bool {
+		iSame, jSame := path.Dir(keys[i]) == dir, path.Dir(keys[j]) == dir
+		if iSame != jSame {
+			return iSame
+		}
+
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+
+		return keys[i] < keys[j]
+	})
+}
+
+// findFile finds the file that is not a note an embed points at, files are
+// matched by the end of their path like the links with a folder
+func (v obsidianVault) findFile(fromPath, target string) (string, bool) {
+	target = "/" + strings.ToLower(strings.TrimPrefix(strings.TrimSpace(target), "/"))
+
+	keys := []string{}
+	for key := range v.files {
+		if strings.HasSuffix(key, target) {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return "", false
+	}
+
+	sortClosest(fromPath, keys)
+	return keys[0], true
+}
+
+// rewriteLinks points the wikilinks at the sha id of the notes, keeping the
+// text of the link as its alias, links in fenced code are left as they are.
+// The files embedded in the note are given to attach and the embed is
+// replaced by the markdown of the attachment
+func (v obsidianVault) rewriteLinks(fromPath, body string, attach func(entry *zip.File) (domain.Attachment, bool)) (string, []string) {
+	unresolved := []string{}
+
+	content := helpers.ReplaceWikiLinks(body, func(link helpers.WikiLink) (string, bool) {
+		note, ok := v.resolve(fromPath, link.Target)
+		if !ok {
+			key, isFile := v.findFile(fromPath, link.Target)
+			if !link.Embed || !isFile {
+				unresolved = append(unresolved, body[link.Start:link.End])
+				return "", false
+			}
+
+			v.embedded[key] = true
+			attachment, ok := attach(v.files[key])
+			if !ok {
+				return "", false
+			}
+
+			return attachmentMarkdown(attachment), true
+		}
+
+		if link.Alias == "" {
+			link.Alias = link.Target
+			if link.Heading != "" {
+				link.Alias += "#" + link.Heading
+			}
+		}
+
+		link.Target = note.note.ShaID
+		return link.String(), true
+	})
+
+	return content, unresolved
+}
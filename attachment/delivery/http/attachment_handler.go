@@ -0,0 +1,203 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+// room left in the upload for the rest of the multipart form
+const maxFormOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	AttachmentUsecase domain.AttachmentUsecase
+}
+
+func NewAttachmentHandler(r *chi.Mux, u domain.AttachmentUsecase) {
+	handler := &AttachmentHandler{
+		AttachmentUsecase: u,
+	}
+
+	// make group v1
+	r.Route("/attachment", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(middleware.MyMiddleware)
+			r.Get("/usage", helpers.RecoverWrap(handler.GetAttachmentUsage))
+			r.Get("/note/{sha_id}", helpers.RecoverWrap(handler.GetNoteAttachments))
+			r.Post("/note/{sha_id}", helpers.RecoverWrap(handler.UploadAttachment))
+			r.Get("/{sha_id}", helpers.RecoverWrap(handler.DownloadAttachment))
+			r.Delete("/{sha_id}", helpers.RecoverWrap(handler.DeleteAttachment))
+		})
+	})
+
+}
+
+func (a AttachmentHandler) GetAttachmentUsage(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	usage, err := a.AttachmentUsecase.GetAttachmentUsage(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "attachment usage found",
+		Data: map[string]interface{}{
+			"usage": usage,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (a AttachmentHandler) GetNoteAttachments(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	attachments, err := a.AttachmentUsecase.GetNoteAttachments(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "attachments found",
+		Data: map[string]interface{}{
+			"attachments": attachments,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (a AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get the file from the multipart form, big uploads are kept on disk
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxAttachmentSize+maxFormOverhead)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	// the attachment is named after the file unless given
+	name := r.FormValue("name")
+	if name == "" {
+		name = filepath.Base(header.Filename)
+	}
+
+	// call usecase
+	attachment, err := a.AttachmentUsecase.UploadAttachment(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), name, file)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "attachment uploaded",
+		Data: map[string]interface{}{
+			"attachment": attachment,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (a AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	attachment, content, err := a.AttachmentUsecase.OpenAttachment(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+	defer content.Close()
+
+	// the content of an attachment never changes, its checksum is the etag
+	etag := `"` + attachment.Checksum + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	if helpers.MatchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// the content is streamed straight to the client
+	w.Header().Set("Content-Type", attachment.MimeType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.Name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	// the status is already sent so a failure can only cut the content short
+	_, err = io.Copy(w, content)
+	if err != nil {
+		log.Printf("download of attachment %s failed: %v", attachment.ShaID, err)
+	}
+}
+
+func (a AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	err = a.AttachmentUsecase.DeleteAttachment(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "attachment deleted",
+		Data:    nil,
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,105 @@
+package attachment_repo_local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// localBlobStore keeps every blob in a file under Dir, the key is the path of the file
+type localBlobStore struct {
+	Dir string
+}
+
+// Put implements domain.BlobStore
+func (l localBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, mimeType string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(name), 0o755)
+	if err != nil {
+		return err
+	}
+
+	// the content is written next to the blob and renamed over it,
+	// so a reader never sees a half written blob
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err == nil && n != size {
+		err = fmt.Errorf("blob %s: wrote %d bytes, expected %d", key, n, size)
+	}
+
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+// Get implements domain.BlobStore
+func (l localBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: blob %s", domain.ErrNotFound, key)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Delete implements domain.BlobStore
+func (l localBlobStore) Delete(ctx context.Context, key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// path of the file of the key, the key cannot point outside of Dir
+func (l localBlobStore) path(key string) (string, error) {
+	if key == "" || key != path.Clean(key) || path.IsAbs(key) || key == ".." || strings.HasPrefix(key, "../") {
+		return "", fmt.Errorf("%w: invalid blob key %q", domain.ErrBadParamInput, key)
+	}
+
+	return filepath.Join(l.Dir, filepath.FromSlash(key)), nil
+}
+
+func NewLocalBlobStore(dir string) domain.BlobStore {
+	return &localBlobStore{dir}
+}
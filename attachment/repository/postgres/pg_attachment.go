@@ -0,0 +1,228 @@
+package attachment_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresAttachmentRepo struct {
+	DB     *sql.DB
+	Source sqlcpg.Querier
+}
+
+// CreateAttachment implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) CreateAttachment(ctx context.Context, attachment domain.Attachment, quota int64) (domain.Attachment, domain.Blob, error) {
+	var created domain.Attachment
+	var blob domain.Blob
+
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		// the user is locked so the uploads running together cannot go over the quota
+		err := q.LockUser(ctx, int32(attachment.UserID))
+		if err != nil {
+			return err
+		}
+
+		usage, err := q.GetAttachmentUsage(ctx, int32(attachment.UserID))
+		if err != nil {
+			return err
+		}
+
+		if usage.Size+attachment.Size > quota {
+			return fmt.Errorf("%w: %d of %d bytes of attachments used", domain.ErrQuotaExceeded, usage.Size, quota)
+		}
+
+		data, err := q.AddBlobReference(ctx, sqlcpg.AddBlobReferenceParams{
+			Checksum:  attachment.Checksum,
+			Size:      attachment.Size,
+			CreatedAt: attachment.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		blob = toDomainBlob(data)
+
+		attachmentData, err := q.CreateAttachment(ctx, sqlcpg.CreateAttachmentParams{
+			ShaID:     attachment.ShaID,
+			NoteShaID: attachment.NoteShaID,
+			UserID:    int32(attachment.UserID),
+			Name:      attachment.Name,
+			MimeType:  attachment.MimeType,
+			Size:      attachment.Size,
+			Checksum:  attachment.Checksum,
+			CreatedAt: attachment.CreatedAt,
+		})
+		if helpers.IsUniqueViolation(err, "attachments_sha_id") {
+			return domain.ErrDuplicateID
+		}
+
+		if err != nil {
+			return err
+		}
+
+		created = toDomainAttachment(attachmentData)
+		return nil
+	})
+	if err != nil {
+		return domain.Attachment{}, domain.Blob{}, err
+	}
+
+	return created, blob, nil
+}
+
+// MarkBlobStored implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) MarkBlobStored(ctx context.Context, checksum string) error {
+	return p.Source.MarkBlobStored(ctx, sqlcpg.MarkBlobStoredParams{
+		Checksum: checksum,
+		StoredAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+// FindAttachment implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) FindAttachment(ctx context.Context, shaID string) (domain.Attachment, error) {
+	data, err := p.Source.FindAttachment(ctx, shaID)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	return toDomainAttachment(data), nil
+}
+
+// GetNoteAttachments implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) GetNoteAttachments(ctx context.Context, noteShaID string) ([]domain.Attachment, error) {
+	data, err := p.Source.GetNoteAttachments(ctx, noteShaID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []domain.Attachment{}
+	for _, v := range data {
+		attachments = append(attachments, toDomainAttachment(v))
+	}
+
+	return attachments, nil
+}
+
+// DeleteAttachment implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) DeleteAttachment(ctx context.Context, shaID string) (string, error) {
+	var checksum string
+
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		var err error
+		checksum, err = q.DeleteAttachment(ctx, shaID)
+		if err != nil {
+			return err
+		}
+
+		return q.RemoveBlobReference(ctx, sqlcpg.RemoveBlobReferenceParams{
+			Checksum:  checksum,
+			UpdatedAt: time.Now(),
+		})
+	})
+
+	return checksum, err
+}
+
+// GetOrphanAttachments implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) GetOrphanAttachments(ctx context.Context, limit int) ([]domain.Attachment, error) {
+	data, err := p.Source.GetOrphanAttachments(ctx, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []domain.Attachment{}
+	for _, v := range data {
+		attachments = append(attachments, toDomainAttachment(v))
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentUsage implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) GetAttachmentUsage(ctx context.Context, userID int) (domain.AttachmentUsage, error) {
+	data, err := p.Source.GetAttachmentUsage(ctx, int32(userID))
+	if err != nil {
+		return domain.AttachmentUsage{}, err
+	}
+
+	return domain.AttachmentUsage{
+		Count: int(data.Count),
+		Size:  data.Size,
+	}, nil
+}
+
+// GetUnusedBlobs implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) GetUnusedBlobs(ctx context.Context, limit int) ([]domain.Blob, error) {
+	data, err := p.Source.GetUnusedBlobs(ctx, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := []domain.Blob{}
+	for _, v := range data {
+		blobs = append(blobs, toDomainBlob(v))
+	}
+
+	return blobs, nil
+}
+
+// DeleteUnusedBlob implements domain.AttachmentRepo
+func (p postgresAttachmentRepo) DeleteUnusedBlob(ctx context.Context, checksum string, deleteContent func() error) error {
+	return helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		// an upload of the same content waits on the lock, and stores
+		// the content again once the blob is gone
+		_, err := q.LockUnusedBlob(ctx, checksum)
+		if err != nil {
+			return err
+		}
+
+		err = deleteContent()
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteBlob(ctx, checksum)
+	})
+}
+
+func toDomainAttachment(data sqlcpg.Attachment) domain.Attachment {
+	return domain.Attachment{
+		ID:        int(data.ID),
+		ShaID:     data.ShaID,
+		NoteShaID: data.NoteShaID,
+		UserID:    int(data.UserID),
+		Name:      data.Name,
+		MimeType:  data.MimeType,
+		Size:      data.Size,
+		Checksum:  data.Checksum,
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func toDomainBlob(data sqlcpg.Blob) domain.Blob {
+	return domain.Blob{
+		Checksum:  data.Checksum,
+		Size:      data.Size,
+		RefCount:  int(data.RefCount),
+		StoredAt:  null.Time{NullTime: data.StoredAt},
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+}
+
+func NewPostgresAttachmentRepo(db *sql.DB, source sqlcpg.Querier) domain.AttachmentRepo {
+	return &postgresAttachmentRepo{db, source}
+}
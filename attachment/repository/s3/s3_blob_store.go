@@ -0,0 +1,248 @@
+package attachment_repo_s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+const (
+	// the content is not hashed when it is uploaded, https protects it on the way
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	emptyPayload    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	amzDateLayout   = "20060102T150405Z"
+	defaultRegion   = "us-east-1"
+)
+
+// S3Config is where the blobs are kept, Endpoint is the base url of the
+// service like https://s3.amazonaws.com or http://localhost:9000 for minio
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// s3BlobStore keeps every blob in an object of the bucket, the requests are
+// signed with aws signature version 4 and use path style urls so any s3
+// compatible service works
+type s3BlobStore struct {
+	Config S3Config
+	Client *http.Client
+}
+
+// Put implements domain.BlobStore
+func (s s3BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, mimeType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+
+	if mimeType != "" {
+		req.Header.Set("Content-Type", mimeType)
+	}
+
+	res, err := s.do(req, unsignedPayload)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return s3Error(req, res)
+	}
+
+	return nil
+}
+
+// Get implements domain.BlobStore
+func (s s3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.do(req, emptyPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, fmt.Errorf("%w: blob %s", domain.ErrNotFound, key)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, s3Error(req, res)
+	}
+
+	return res.Body, nil
+}
+
+// Delete implements domain.BlobStore
+func (s s3BlobStore) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(req, emptyPayload)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// deleting a missing object is not an error for s3, some stand-ins answer 404
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return s3Error(req, res)
+	}
+
+	return nil
+}
+
+func (s s3BlobStore) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	if key == "" {
+		return nil, fmt.Errorf("%w: blob key cannot be empty", domain.ErrBadParamInput)
+	}
+
+	// the path is escaped here the way the signature expects it
+	segments := strings.Split(s.Config.Bucket+"/"+key, "/")
+	for i, v := range segments {
+		segments[i] = uriEncode(v)
+	}
+
+	return http.NewRequestWithContext(ctx, method, strings.TrimRight(s.Config.Endpoint, "/")+"/"+strings.Join(segments, "/"), body)
+}
+
+// do signs the request and sends it
+func (s s3BlobStore) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	signRequest(req, s.Config, payloadHash, time.Now())
+
+	return s.Client.Do(req)
+}
+
+// signRequest adds the aws signature version 4 of the request, every header
+// already set on the request is signed with the host
+func signRequest(req *http.Request, config S3Config, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(amzDateLayout)
+	scope := now.Format("20060102") + "/" + config.Region + "/s3/aws4_request"
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+config.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", config.AccessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	params := []string{}
+	for k, values := range query {
+		for _, v := range values {
+			params = append(params, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
+
+// uriEncode escapes everything but the unreserved characters, as the signature requires
+func uriEncode(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// s3Error keeps the start of the xml error of the service
+func s3Error(req *http.Request, res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+
+	return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, res.Status, strings.TrimSpace(string(body)))
+}
+
+func NewS3BlobStore(config S3Config) domain.BlobStore {
+	if config.Region == "" {
+		config.Region = defaultRegion
+	}
+
+	return &s3BlobStore{
+		Config: config,
+		Client: &http.Client{},
+	}
+}
@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+)
+
+// number of attachments or blobs deleted at once by PurgeOrphanAttachments
+const purgeBatchSize = 100
+
+type AttachmentUseCaseImpl struct {
+	AttachmentRepo domain.AttachmentRepo
+	BlobStore      domain.BlobStore
+	ShareUsecase   domain.ShareUsecase
+	IDGenerator    helpers.IDGenerator
+	// Quota is the total size of the attachments of a user
+	Quota int64
+}
+
+// UploadAttachment implements domain.AttachmentUsecase
+func (a AttachmentUseCaseImpl) UploadAttachment(ctx context.Context, userID int, noteShaID string, name string, r io.Reader) (domain.Attachment, error) {
+	err := helpers.ValidateFileName(name)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	// an attachment belongs to the owner of the note
+	note, err := a.findNote(ctx, userID, noteShaID, domain.ShareRoleEditor)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	// the content is kept in a temporary file to know its size and checksum before storing it
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, domain.MaxAttachmentSize+1))
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	if size == 0 {
+		return domain.Attachment{}, fmt.Errorf("%w: attachment cannot be empty", domain.ErrBadParamInput)
+	}
+
+	if size > domain.MaxAttachmentSize {
+		return domain.Attachment{}, fmt.Errorf("%w: attachment cannot be bigger than %d MB", domain.ErrBadParamInput, domain.MaxAttachmentSize>>20)
+	}
+
+	// the type given by the client is not trusted, it is sniffed from the content
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return domain.Attachment{}, err
+	}
+
+	mimeType := http.DetectContentType(head[:n])
+	if !domain.AttachmentMimeTypes[mimeType] {
+		return domain.Attachment{}, fmt.Errorf("%w: only images and pdf can be attached, got %s", domain.ErrBadParamInput, mimeType)
+	}
+
+	attachment := domain.Attachment{
+		NoteShaID: note.ShaID,
+		UserID:    note.UserID,
+		Name:      name,
+		MimeType:  mimeType,
+		Size:      size,
+		Checksum:  hex.EncodeToString(hash.Sum(nil)),
+		CreatedAt: time.Now(),
+	}
+
+	// call repository, a new sha id is tried when the previous one is taken
+	var created domain.Attachment
+	var blob domain.Blob
+	err = a.IDGenerator.Generate(func(id string) error {
+		attachment.ShaID = id
+		created, blob, err = a.AttachmentRepo.CreateAttachment(ctx, attachment, a.Quota)
+		return err
+	})
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	// the same content is stored once, it is only uploaded until one upload succeeds
+	if blob.StoredAt.Valid {
+		return created, nil
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err == nil {
+		err = a.BlobStore.Put(ctx, blobKey(created.Checksum), tmp, size, mimeType)
+	}
+
+	if err == nil {
+		err = a.AttachmentRepo.MarkBlobStored(ctx, created.Checksum)
+	}
+
+	// the attachment is not kept without its content
+	if err != nil {
+		a.AttachmentRepo.DeleteAttachment(ctx, created.ShaID)
+		return domain.Attachment{}, err
+	}
+
+	return created, nil
+}
+
+// GetNoteAttachments implements domain.AttachmentUsecase
+func (a AttachmentUseCaseImpl) GetNoteAttachments(ctx context.Context, userID int, noteShaID string) ([]domain.Attachment, error) {
+	note, err := a.findNote(ctx, userID, noteShaID, domain.ShareRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// call repository
+	return a.AttachmentRepo.GetNoteAttachments(ctx, note.ShaID)
+}
+
+// OpenAttachment implements domain.AttachmentUsecase
+func (a AttachmentUseCaseImpl) OpenAttachment(ctx context.Context, userID int, shaID string) (domain.Attachment, io.ReadCloser, error) {
+	attachment, err := a.findAttachment(ctx, userID, shaID, domain.ShareRoleViewer)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	content, err := a.BlobStore.Get(ctx, blobKey(attachment.Checksum))
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteAttachment implements domain.AttachmentUsecase
+func (a AttachmentUseCaseImpl) DeleteAttachment(ctx context.Context, userID int, shaID string) error {
+	attachment, err := a.findAttachment(ctx, userID, shaID, domain.ShareRoleEditor)
+	if err != nil {
+		return err
+	}
+
+	// call repository
+	checksum, err := a.AttachmentRepo.DeleteAttachment(ctx, attachment.ShaID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: attachment not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the content is freed with its last attachment, when it fails
+	// the purge of the trash frees it later
+	a.deleteUnusedBlob(ctx, checksum)
+
+	return nil
+}
+
+// GetAttachmentUsage implements domain.AttachmentUsecase
+func (a AttachmentUseCaseImpl) GetAttachmentUsage(ctx context.Context, userID int) (domain.AttachmentUsage, error) {
+	// call repository
+	usage, err := a.AttachmentRepo.GetAttachmentUsage(ctx, userID)
+	if err != nil {
+		return domain.AttachmentUsage{}, err
+	}
+
+	usage.Quota = a.Quota
+	return usage, nil
+}
+
+// PurgeOrphanAttachments implements domain.AttachmentUsecase
+func (a AttachmentUseCaseImpl) PurgeOrphanAttachments(ctx context.Context) (int, error) {
+	count := 0
+
+	for {
+		attachments, err := a.AttachmentRepo.GetOrphanAttachments(ctx, purgeBatchSize)
+		if err != nil {
+			return count, err
+		}
+
+		for _, v := range attachments {
+			_, err = a.AttachmentRepo.DeleteAttachment(ctx, v.ShaID)
+			if err != nil && err != sql.ErrNoRows {
+				return count, err
+			}
+
+			count++
+		}
+
+		if len(attachments) < purgeBatchSize {
+			break
+		}
+	}
+
+	// then the content of the attachments deleted here or before
+	for {
+		blobs, err := a.AttachmentRepo.GetUnusedBlobs(ctx, purgeBatchSize)
+		if err != nil {
+			return count, err
+		}
+
+		deleted := 0
+		for _, v := range blobs {
+			err = a.deleteUnusedBlob(ctx, v.Checksum)
+			if err == sql.ErrNoRows {
+				continue
+			}
+
+			if err != nil {
+				return count, err
+			}
+
+			deleted++
+		}
+
+		// the blobs skipped would come back again and again
+		if len(blobs) < purgeBatchSize || deleted == 0 {
+			return count, nil
+		}
+	}
+}
+
+// deleteUnusedBlob deletes the content of the checksum when no attachment uses it
+func (a AttachmentUseCaseImpl) deleteUnusedBlob(ctx context.Context, checksum string) error {
+	return a.AttachmentRepo.DeleteUnusedBlob(ctx, checksum, func() error {
+		return a.BlobStore.Delete(ctx, blobKey(checksum))
+	})
+}
+
+// findNote checks the user has the role on the note
+func (a AttachmentUseCaseImpl) findNote(ctx context.Context, userID int, shaID string, role string) (domain.File, error) {
+	note, err := a.ShareUsecase.Authorize(ctx, userID, shaID, role)
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	if note.Type != domain.FileTypeNote {
+		return domain.File{}, fmt.Errorf("%w: only notes have attachments", domain.ErrBadParamInput)
+	}
+
+	return note, nil
+}
+
+// findAttachment checks the user has the role on the note of the attachment
+func (a AttachmentUseCaseImpl) findAttachment(ctx context.Context, userID int, shaID string, role string) (domain.Attachment, error) {
+	if shaID == "" {
+		return domain.Attachment{}, fmt.Errorf("%w: sha_id cannot be empty", domain.ErrBadParamInput)
+	}
+
+	// call repository
+	attachment, err := a.AttachmentRepo.FindAttachment(ctx, shaID)
+	if err == sql.ErrNoRows {
+		return domain.Attachment{}, fmt.Errorf("%w: attachment not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	_, err = a.findNote(ctx, userID, attachment.NoteShaID, role)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// blobKey is where the content of the checksum is stored, the first
+// characters make a folder so no folder gets too big on a file system
+func blobKey(checksum string) string {
+	return "sha256/" + checksum[:2] + "/" + checksum
+}
+
+func NewAttachmentUseCase(ar domain.AttachmentRepo, bs domain.BlobStore, su domain.ShareUsecase, ig helpers.IDGenerator, quota int64) domain.AttachmentUsecase {
+	return &AttachmentUseCaseImpl{
+		AttachmentRepo: ar,
+		BlobStore:      bs,
+		ShareUsecase:   su,
+		IDGenerator:    ig,
+		Quota:          quota,
+	}
+}
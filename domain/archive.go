@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+// ArchiveImportError is a file of the archive that could not be imported
+type ArchiveImportError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// ArchiveUnresolvedLink is a link of an imported note to a file that is not in the import
+type ArchiveUnresolvedLink struct {
+	Path string `json:"path"`
+	Link string `json:"link"`
+}
+
+type ArchiveImportReport struct {
+	FoldersCreated int `json:"folders_created"`
+	NotesCreated   int `json:"notes_created"`
+	NotesUpdated   int `json:"notes_updated"`
+	NotesUnchanged int `json:"notes_unchanged"`
+	// AttachmentsCreated are the files embedded in the notes uploaded as attachments
+	AttachmentsCreated int `json:"attachments_created"`
+	// Skipped are the files that are not imported, like hidden files or images no note embeds
+	Skipped []string             `json:"skipped"`
+	Errors  []ArchiveImportError `json:"errors"`
+	// UnresolvedLinks is only filled by the importers of linked notes, like obsidian
+	UnresolvedLinks []ArchiveUnresolvedLink `json:"unresolved_links,omitempty"`
+}
+
+type ArchiveUsecase interface {
+	// ExportWorkspace writes a zip of every folder and note of the user to w,
+	// notes are markdown files at their path with yaml front matter
+	ExportWorkspace(ctx context.Context, userID int, w io.Writer) error
+	// ImportArchive recreates the folders and markdown files of a zip, files
+	// already at the same path are updated so importing twice changes nothing
+	ImportArchive(ctx context.Context, userID int, r io.ReaderAt, size int64) (ArchiveImportReport, error)
+	// ImportEnex imports an evernote export into a folder named after the notebook,
+	// the enml of every note is converted to markdown and its resources are uploaded as attachments
+	ImportEnex(ctx context.Context, userID int, notebook string, r io.Reader) (ArchiveImportReport, error)
+	// ImportObsidian imports a zip of an obsidian vault, the wikilinks between
+	// the notes are rewritten to the sha id of the imported notes and the
+	// embedded files are uploaded as attachments of the notes that embed them
+	ImportObsidian(ctx context.Context, userID int, r io.ReaderAt, size int64) (ArchiveImportReport, error)
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	// MaxAttachmentSize is the biggest file that can be attached to a note
+	MaxAttachmentSize = 25 << 20
+	// DefaultAttachmentQuota is the total size of the attachments of a user
+	// when ATTACHMENT_QUOTA_MB is not set
+	DefaultAttachmentQuota = 1 << 30
+)
+
+// AttachmentMimeTypes are the types of the files that can be attached to a note
+var AttachmentMimeTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/bmp":       true,
+	"application/pdf": true,
+}
+
+// Attachment is a file like an image or a pdf attached to a note, the
+// content is kept in the blob of its checksum
+type Attachment struct {
+	ID        int    `json:"id"`
+	ShaID     string `json:"sha_id"`
+	NoteShaID string `json:"note_sha_id"`
+	// UserID is the owner of the note
+	UserID   int    `json:"user_id"`
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+	// Checksum is the hex sha-256 of the content
+	Checksum  string    `json:"checksum"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Blob is a content stored once for all the attachments with the same checksum
+type Blob struct {
+	Checksum string `json:"checksum"`
+	Size     int64  `json:"size"`
+	// RefCount is the number of attachments with the content, the
+	// content is deleted once it is 0
+	RefCount int `json:"ref_count"`
+	// StoredAt is null until the content is in the blob store
+	StoredAt  null.Time `json:"stored_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AttachmentUsage is what the attachments of a user count against the
+// quota, a content attached twice counts twice even if it is stored once
+type AttachmentUsage struct {
+	Count int   `json:"count"`
+	Size  int64 `json:"size"`
+	Quota int64 `json:"quota"`
+}
+
+// BlobStore keeps the content of the attachments, keys are slash separated paths
+type BlobStore interface {
+	// Put stores the size bytes of r under the key, replacing what was there
+	Put(ctx context.Context, key string, r io.Reader, size int64, mimeType string) error
+	// Get returns ErrNotFound when nothing is stored under the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete does not fail when nothing is stored under the key
+	Delete(ctx context.Context, key string) error
+}
+
+type AttachmentRepo interface {
+	// CreateAttachment adds a reference to the blob of the checksum and returns
+	// the blob, the content still has to be stored when its StoredAt is null.
+	// It returns ErrDuplicateID when the sha id is already used and
+	// ErrQuotaExceeded when the attachment does not fit in the quota of its user
+	CreateAttachment(ctx context.Context, attachment Attachment, quota int64) (Attachment, Blob, error)
+	MarkBlobStored(ctx context.Context, checksum string) error
+	FindAttachment(ctx context.Context, shaID string) (Attachment, error)
+	GetNoteAttachments(ctx context.Context, noteShaID string) ([]Attachment, error)
+	// DeleteAttachment removes the reference of the attachment to its blob
+	// and returns the checksum of the blob
+	DeleteAttachment(ctx context.Context, shaID string) (string, error)
+	// GetOrphanAttachments returns the attachments of the notes purged from the trash
+	GetOrphanAttachments(ctx context.Context, limit int) ([]Attachment, error)
+	GetAttachmentUsage(ctx context.Context, userID int) (AttachmentUsage, error)
+	// GetUnusedBlobs returns the blobs no attachment references anymore
+	GetUnusedBlobs(ctx context.Context, limit int) ([]Blob, error)
+	// DeleteUnusedBlob calls deleteContent and deletes the blob while it is locked,
+	// so it is not referenced again meanwhile. It returns sql.ErrNoRows when the
+	// blob is referenced again or already being deleted
+	DeleteUnusedBlob(ctx context.Context, checksum string, deleteContent func() error) error
+}
+
+type AttachmentUsecase interface {
+	// UploadAttachment attaches the content of r to the note, the mime type is sniffed from the content
+	UploadAttachment(ctx context.Context, userID int, noteShaID string, name string, r io.Reader) (Attachment, error)
+	GetNoteAttachments(ctx context.Context, userID int, noteShaID string) ([]Attachment, error)
+	// OpenAttachment returns the attachment with its content, the caller must close it
+	OpenAttachment(ctx context.Context, userID int, shaID string) (Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, userID int, shaID string) error
+	GetAttachmentUsage(ctx context.Context, userID int) (AttachmentUsage, error)
+	// PurgeOrphanAttachments deletes the attachments of the notes purged from the trash
+	// and the content no attachment uses anymore, it returns how many attachments were deleted
+	PurgeOrphanAttachments(ctx context.Context) (int, error)
+}
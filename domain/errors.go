@@ -0,0 +1,39 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrNotFound will be returned when the requested item does not exist
+	// or does not belong to the caller
+	ErrNotFound = errors.New("data not found")
+	// ErrBadParamInput will be returned when the given param is not valid
+	ErrBadParamInput = errors.New("given param is not valid")
+	// ErrConflict will be returned when the request conflicts with
+	// the current state of the data
+	ErrConflict = errors.New("conflict with the current data")
+	// ErrForbidden will be returned when the caller can see the data
+	// but is not allowed to do the requested action
+	ErrForbidden = errors.New("not allowed")
+	// ErrDuplicateID will be returned when a generated sha id is already
+	// used, the caller should retry with a new id
+	ErrDuplicateID = errors.New("sha id already used")
+	// ErrQuotaExceeded will be returned when the user has no room left
+	// for the data
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// VersionConflictError will be returned when a change is asked on a
+// version of the data that is not the current one anymore
+type VersionConflictError struct {
+	// Current is the etag of the current version
+	Current string
+}
+
+func (e *VersionConflictError) Error() string {
+	return "version is not the current one, current version is " + e.Current
+}
+
+// Is makes errors.Is(err, ErrConflict) true for a version conflict
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
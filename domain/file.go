@@ -1,6 +1,15 @@
 package domain
 
-import "gopkg.in/guregu/null.v4"
+import (
+	"context"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	FileTypeFolder = "folder"
+	FileTypeNote   = "note"
+)
 
 type File struct {
 	ID          int         `json:"id"`
@@ -12,4 +21,63 @@ type File struct {
 	Type        string      `json:"type"`
 	CreatedAt   null.Time   `json:"created_at"`
 	UpdatedAt   null.Time   `json:"updated_at"`
+	// DeletedAt is set while the file is in the trash
+	DeletedAt null.Time `json:"deleted_at"`
+}
+
+// FileTree is a file with its children nested inside it
+type FileTree struct {
+	File
+	Children []FileTree `json:"children"`
+}
+
+type FileRepo interface {
+	// Transaction runs fn with a repo bound to a single database transaction,
+	// called on such a repo it runs fn in the same transaction
+	Transaction(ctx context.Context, fn func(repo FileRepo) error) error
+	FindFile(ctx context.Context, userID int, shaID string) (File, error)
+	FindFileByPath(ctx context.Context, userID int, path string) (File, error)
+	// FindFileByShaID finds the file whoever it belongs to, access must be checked by the caller
+	FindFileByShaID(ctx context.Context, shaID string) (File, error)
+	// FindFileForUpdate finds the file and locks it until the transaction ends
+	FindFileForUpdate(ctx context.Context, userID int, shaID string) (File, error)
+	// LockFileTree locks the files of the user until the transaction ends, so
+	// the changes of the tree that cannot be checked on a single row, like
+	// the moves, are made one at a time
+	LockFileTree(ctx context.Context, userID int) error
+	CountFilesByName(ctx context.Context, userID int, folderShaID string, name string, excludeShaID string) (int, error)
+	// GetFileAncestors returns the sha id of the file and of every folder above it
+	GetFileAncestors(ctx context.Context, userID int, shaID string) ([]string, error)
+	// UpdateFilePath saves the folder, name and path of the file and rewrites
+	// the path of every descendant that starts with oldPath. It returns the
+	// file followed by its descendants with their new paths
+	UpdateFilePath(ctx context.Context, file File, oldPath string) ([]File, error)
+	// GetFileTree returns every file under the folder, at most maxDepth
+	// levels deep, ordered so that a parent comes before its children
+	GetFileTree(ctx context.Context, userID int, folderShaID string, maxDepth int) ([]File, error)
+	// GetLinkingNotes returns the notes with a link to any of the files and
+	// locks them until the transaction ends
+	GetLinkingNotes(ctx context.Context, shaIDs []string) ([]Note, error)
+	GetNoteLinks(ctx context.Context, shaID string) ([]NoteLink, error)
+	// RewriteNote saves the content of the note as a new revision and
+	// replaces its links, only the latest retention revisions are kept
+	RewriteNote(ctx context.Context, note Note, links []NoteLink, retention int) error
+	// FindNoteForUpdate finds the note and locks it until the transaction ends
+	FindNoteForUpdate(ctx context.Context, userID int, shaID string) (Note, error)
+	// SaveNote saves the content of the locked note like NoteRepo.UpdateNote,
+	// in the transaction of the repo
+	SaveNote(ctx context.Context, note Note, retention int) (Note, error)
+}
+
+type FileUsecase interface {
+	// Transaction runs fn with a file usecase and a file repo bound to a
+	// single database transaction, so their changes are saved together. The
+	// usecase given to fn cannot make a dry run
+	Transaction(ctx context.Context, fn func(files FileUsecase, repo FileRepo) error) error
+	GetFileTree(ctx context.Context, userID int, shaID string, maxDepth int) ([]FileTree, error)
+	// RenameFile and MoveFile return every file whose path has changed and
+	// the notes whose links to those files have been rewritten. With dryRun
+	// nothing is saved, the changes are only returned
+	RenameFile(ctx context.Context, userID int, shaID string, name string, dryRun bool) ([]File, []NoteLinkRewrite, error)
+	MoveFile(ctx context.Context, userID int, shaID string, folderShaID string, dryRun bool) ([]File, []NoteLinkRewrite, error)
 }
@@ -1 +1,45 @@
 package domain
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// Folder is a row of the folders table joined with the file it belongs to
+type Folder struct {
+	ID          int         `json:"id"`
+	ParentID    null.Int    `json:"parent_id"`
+	ShaID       string      `json:"sha_id"`
+	ParentShaID null.String `json:"parent_sha_id"`
+	UserID      int         `json:"user_id"`
+	Name        string      `json:"name"`
+	Path        string      `json:"path"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type FolderRepo interface {
+	CreateFolder(ctx context.Context, folder Folder) (Folder, error)
+	FindFolder(ctx context.Context, userID int, shaID string) (Folder, error)
+	GetFolderChildren(ctx context.Context, userID int, shaID string) ([]File, error)
+	// DeleteFolder moves the folder together with all of its descendants to
+	// the trash. It locks the folder and returns a VersionConflictError when
+	// version is not its etag, an empty version skips the check. Without
+	// recursive a folder that still has children is refused with ErrConflict
+	DeleteFolder(ctx context.Context, userID int, shaID string, recursive bool, version string) error
+}
+
+type FolderUsecase interface {
+	CreateFolder(ctx context.Context, userID int, folder Folder) (Folder, error)
+	FindFolder(ctx context.Context, userID int, shaID string) (Folder, error)
+	GetFolderChildren(ctx context.Context, userID int, shaID string) ([]File, error)
+	// RenameFolder and MoveFolder also return every file whose path has changed.
+	// They and DeleteFolder return a VersionConflictError when version, the
+	// etag the change was made on, is not the current one. An empty version
+	// skips the check
+	RenameFolder(ctx context.Context, userID int, shaID string, name string, version string) (Folder, []File, error)
+	MoveFolder(ctx context.Context, userID int, shaID string, parentShaID string, version string) (Folder, []File, error)
+	DeleteFolder(ctx context.Context, userID int, shaID string, recursive bool, version string) error
+}
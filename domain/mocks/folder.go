@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type FolderRepoMock struct {
+	mock.Mock
+}
+
+// CreateFolder implements domain.FolderRepo
+func (m *FolderRepoMock) CreateFolder(ctx context.Context, folder domain.Folder) (domain.Folder, error) {
+	args := m.Called(ctx, folder)
+	return args.Get(0).(domain.Folder), args.Error(1)
+}
+
+// FindFolder implements domain.FolderRepo
+func (m *FolderRepoMock) FindFolder(ctx context.Context, userID int, shaID string) (domain.Folder, error) {
+	args := m.Called(ctx, userID, shaID)
+	return args.Get(0).(domain.Folder), args.Error(1)
+}
+
+// GetFolderChildren implements domain.FolderRepo
+func (m *FolderRepoMock) GetFolderChildren(ctx context.Context, userID int, shaID string) ([]domain.File, error) {
+	args := m.Called(ctx, userID, shaID)
+	return args.Get(0).([]domain.File), args.Error(1)
+}
+
+// DeleteFolder implements domain.FolderRepo
+func (m *FolderRepoMock) DeleteFolder(ctx context.Context, userID int, shaID string, recursive bool, version string) error {
+	args := m.Called(ctx, userID, shaID, recursive, version)
+	return args.Error(0)
+}
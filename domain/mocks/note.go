@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type NoteRepoMock struct {
+	mock.Mock
+}
+
+// CreateNote implements domain.NoteRepo
+func (m *NoteRepoMock) CreateNote(ctx context.Context, note domain.Note, retention int) (domain.Note, error) {
+	args := m.Called(ctx, note, retention)
+	return args.Get(0).(domain.Note), args.Error(1)
+}
+
+// FindNote implements domain.NoteRepo
+func (m *NoteRepoMock) FindNote(ctx context.Context, userID int, shaID string) (domain.Note, error) {
+	args := m.Called(ctx, userID, shaID)
+	return args.Get(0).(domain.Note), args.Error(1)
+}
+
+// GetNotes implements domain.NoteRepo
+func (m *NoteRepoMock) GetNotes(ctx context.Context, userID int) ([]domain.Note, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Note), args.Error(1)
+}
+
+// GetNotesByFolder implements domain.NoteRepo
+func (m *NoteRepoMock) GetNotesByFolder(ctx context.Context, userID int, folderShaID string) ([]domain.Note, error) {
+	args := m.Called(ctx, userID, folderShaID)
+	return args.Get(0).([]domain.Note), args.Error(1)
+}
+
+// GetNotesByTags implements domain.NoteRepo
+func (m *NoteRepoMock) GetNotesByTags(ctx context.Context, userID int, params domain.NoteListParams) ([]domain.Note, error) {
+	args := m.Called(ctx, userID, params)
+	return args.Get(0).([]domain.Note), args.Error(1)
+}
+
+// GetNotesWithOpenTasks implements domain.NoteRepo
+func (m *NoteRepoMock) GetNotesWithOpenTasks(ctx context.Context, userID int) ([]domain.Note, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Note), args.Error(1)
+}
+
+// UpdateNote implements domain.NoteRepo
+func (m *NoteRepoMock) UpdateNote(ctx context.Context, note domain.Note, version string, retention int) (domain.Note, error) {
+	args := m.Called(ctx, note, version, retention)
+	return args.Get(0).(domain.Note), args.Error(1)
+}
+
+// DeleteNote implements domain.NoteRepo
+func (m *NoteRepoMock) DeleteNote(ctx context.Context, userID int, shaID string, version string) error {
+	args := m.Called(ctx, userID, shaID, version)
+	return args.Error(0)
+}
+
+// SearchNotes implements domain.NoteRepo
+func (m *NoteRepoMock) SearchNotes(ctx context.Context, userID int, params domain.NoteSearchParams) ([]domain.NoteSearchResult, error) {
+	args := m.Called(ctx, userID, params)
+	return args.Get(0).([]domain.NoteSearchResult), args.Error(1)
+}
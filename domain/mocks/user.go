@@ -34,3 +34,15 @@ func (m *UserRepoMock) FindUser(ctx context.Context, id int) (domain.User, error
 	args := m.Called(ctx, id)
 	return args.Get(0).(domain.User), args.Error(1)
 }
+
+// FindUserSettings implements domain.UserRepo
+func (m *UserRepoMock) FindUserSettings(ctx context.Context, userID int) (domain.UserSettings, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.UserSettings), args.Error(1)
+}
+
+// UpsertUserSettings implements domain.UserRepo
+func (m *UserRepoMock) UpsertUserSettings(ctx context.Context, settings domain.UserSettings) (domain.UserSettings, error) {
+	args := m.Called(ctx, settings)
+	return args.Get(0).(domain.UserSettings), args.Error(1)
+}
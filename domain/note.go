@@ -1 +1,82 @@
 package domain
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// Note is a row of the notes table joined with the file it belongs to
+type Note struct {
+	ID          int         `json:"id"`
+	ShaID       string      `json:"sha_id"`
+	FolderShaID null.String `json:"folder_sha_id"`
+	UserID      int         `json:"user_id"`
+	Name        string      `json:"name"`
+	Path        string      `json:"path"`
+	Note        null.String `json:"note"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// NoteSearchResult is a note matching a full text search, the highlights
+// are escaped html that wrap every matching word with <mark></mark>
+type NoteSearchResult struct {
+	ShaID         string      `json:"sha_id"`
+	FolderShaID   null.String `json:"folder_sha_id"`
+	Name          string      `json:"name"`
+	Path          string      `json:"path"`
+	NameHighlight string      `json:"name_highlight"`
+	Snippet       string      `json:"snippet"`
+	Rank          float32     `json:"rank"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+type NoteListParams struct {
+	FolderShaID string
+	// Tags only keeps the notes with any of the tags, or all of them when MatchAll is set
+	Tags     []string
+	MatchAll bool
+}
+
+type NoteSearchParams struct {
+	Query string
+	// FolderShaID limits the search to the sub tree of the folder
+	FolderShaID string
+	Limit       int
+	Offset      int
+}
+
+type NoteRepo interface {
+	// CreateNote and UpdateNote also save the content as a new revision and
+	// only keep the latest retention revisions, 0 keeps all
+	CreateNote(ctx context.Context, note Note, retention int) (Note, error)
+	FindNote(ctx context.Context, userID int, shaID string) (Note, error)
+	GetNotes(ctx context.Context, userID int) ([]Note, error)
+	GetNotesByFolder(ctx context.Context, userID int, folderShaID string) ([]Note, error)
+	GetNotesByTags(ctx context.Context, userID int, params NoteListParams) ([]Note, error)
+	// GetNotesWithOpenTasks returns the notes of the user with an unchecked box
+	// somewhere in the content, it can be in fenced code
+	GetNotesWithOpenTasks(ctx context.Context, userID int) ([]Note, error)
+	// UpdateNote only saves the content, renaming goes through FileUsecase.
+	// UpdateNote and DeleteNote lock the note and return a VersionConflictError
+	// when version is not its etag, an empty version skips the check
+	UpdateNote(ctx context.Context, note Note, version string, retention int) (Note, error)
+	// DeleteNote moves the note to the trash
+	DeleteNote(ctx context.Context, userID int, shaID string, version string) error
+	SearchNotes(ctx context.Context, userID int, params NoteSearchParams) ([]NoteSearchResult, error)
+}
+
+type NoteUsecase interface {
+	CreateNote(ctx context.Context, userID int, note Note) (Note, error)
+	FindNote(ctx context.Context, userID int, shaID string) (Note, error)
+	GetNotes(ctx context.Context, userID int, params NoteListParams) ([]Note, error)
+	// UpdateNote and DeleteNote return a VersionConflictError when version,
+	// the etag the change was made on, is not the current one. An empty
+	// version skips the check. UpdateNote renames the note when the name is
+	// set and keeps the content when the note is null
+	UpdateNote(ctx context.Context, userID int, note Note, version string) (Note, error)
+	DeleteNote(ctx context.Context, userID int, shaID string, version string) error
+	SearchNotes(ctx context.Context, userID int, params NoteSearchParams) ([]NoteSearchResult, error)
+}
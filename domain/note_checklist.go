@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ChecklistItem is a task of a note, a markdown list item starting with a
+// box such as - [ ] or - [x]
+type ChecklistItem struct {
+	// Anchor identifies the item in the changes of the checklist. It is made
+	// from the line and its content, so a change made on an older version of
+	// the note is refused
+	Anchor string `json:"anchor"`
+	// Line is the number of the line of the item, from 1
+	Line int `json:"line"`
+	// Depth is 0 for a top level item and one more for each level of nesting
+	Depth   int    `json:"depth"`
+	Checked bool   `json:"checked"`
+	Text    string `json:"text"`
+}
+
+// NoteChecklist is the checklist written in a note
+type NoteChecklist struct {
+	ShaID     string          `json:"sha_id"`
+	Name      string          `json:"name"`
+	Path      string          `json:"path"`
+	Items     []ChecklistItem `json:"items"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type NoteChecklistUsecase interface {
+	GetChecklist(ctx context.Context, userID int, shaID string) (NoteChecklist, error)
+	// ToggleChecklistItem, AddChecklistItem and ReorderChecklist rewrite the
+	// items in the markdown of the note and leave the other lines as they are.
+	// They return a VersionConflictError when version, the etag the change was
+	// made on, is not the current one. An empty version skips the check
+	ToggleChecklistItem(ctx context.Context, userID int, shaID string, anchor string, version string) (NoteChecklist, error)
+	// AddChecklistItem adds an unchecked item after the item of the anchor,
+	// or at the end of the last checklist when the anchor is empty
+	AddChecklistItem(ctx context.Context, userID int, shaID string, text string, after string, version string) (NoteChecklist, error)
+	// ReorderChecklist moves the items of the anchors, with the items nested
+	// in them, into the given order. The anchors are every item under the same parent
+	ReorderChecklist(ctx context.Context, userID int, shaID string, anchors []string, version string) (NoteChecklist, error)
+	// GetOpenTasks returns the notes of the user with their unchecked items
+	GetOpenTasks(ctx context.Context, userID int) ([]NoteChecklist, error)
+}
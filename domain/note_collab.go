@@ -0,0 +1,72 @@
+package domain
+
+import "context"
+
+const (
+	TextOpInsert = "insert"
+	TextOpDelete = "delete"
+)
+
+// types of the messages of a collaborative editing session
+const (
+	// CollabMessageInit is sent to an editor joining, with the text and its revision
+	CollabMessageInit = "init"
+	// CollabMessageOps is sent by an editor with the ops made on Revision, and
+	// by the server to the other editors with the ops that made Revision
+	CollabMessageOps = "ops"
+	// CollabMessageAck tells the editor its ops are applied and made Revision
+	CollabMessageAck = "ack"
+	// CollabMessageJoin and CollabMessageLeave tell the editors who is editing
+	CollabMessageJoin  = "join"
+	CollabMessageLeave = "leave"
+	CollabMessageError = "error"
+)
+
+// TextOp inserts Text at Pos or deletes Length characters from Pos,
+// positions and lengths count unicode code points
+type TextOp struct {
+	Type   string `json:"type"`
+	Pos    int    `json:"pos"`
+	Text   string `json:"text,omitempty"`
+	Length int    `json:"length,omitempty"`
+}
+
+type CollabMessage struct {
+	Type     string   `json:"type"`
+	Revision int      `json:"revision"`
+	Ops      []TextOp `json:"ops,omitempty"`
+	// Text is sent with the init message, and with the error closing a
+	// session so the editors keep the text it could not save
+	Text string `json:"text,omitempty"`
+	// UserID made the change, it is 0 for a change saved outside of the session
+	UserID   int    `json:"user_id,omitempty"`
+	ReadOnly bool   `json:"read_only,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CollabEditor is a connection to the session of a note. The session writes
+// the messages for it on Send, which must be buffered, and closes Send when
+// it drops the editor, an editor letting the buffer fill up is dropped
+type CollabEditor struct {
+	UserID int
+	Send   chan CollabMessage
+}
+
+type NoteCollabUsecase interface {
+	// JoinNote adds the editor to the session of the note, opening it from
+	// the saved note when nobody is editing. Users who can only read the
+	// note follow the changes without being able to make any
+	JoinNote(ctx context.Context, editor *CollabEditor, shaID string) error
+	// SubmitOps transforms the ops made on the revision against the ops
+	// applied since, applies them and sends them to the other editors. The
+	// role of the editor is checked again, an editor who lost the access is
+	// made read only or dropped
+	SubmitOps(ctx context.Context, editor *CollabEditor, shaID string, revision int, ops []TextOp) error
+	// LeaveNote removes the editor, the last editor leaving saves the note
+	LeaveNote(ctx context.Context, editor *CollabEditor, shaID string)
+	// SaveSnapshots saves the text of every session changed since its last
+	// snapshot. The changes saved outside of a session are merged into it
+	// first and sent to its editors as ops, a session that cannot take them
+	// in is closed with its unsaved text and its editors have to join again
+	SaveSnapshots(ctx context.Context) error
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// NoteLink is a [[link]] written in a note, the links are saved with the content of the note
+type NoteLink struct {
+	ID          int    `json:"id"`
+	SourceShaID string `json:"source_sha_id"`
+	// TargetShaID is null while no note of the owner matches Target
+	TargetShaID null.String `json:"target_sha_id"`
+	// Target is the note name, path or sha id written in the link
+	Target  string `json:"target"`
+	Heading string `json:"heading"`
+	Alias   string `json:"alias"`
+	Line    int    `json:"line"`
+	// Snippet is the text around the link
+	Snippet   string    `json:"snippet"`
+	CreatedAt time.Time `json:"created_at"`
+	// Note is the note at the other end of the link, the target of an
+	// outgoing link or the source of a backlink. It is nil when the link
+	// points at no note, or at one the user cannot see
+	Note *NoteNode `json:"note"`
+}
+
+// NoteNode is a note of the graph of the notes
+type NoteNode struct {
+	ShaID string `json:"sha_id"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+}
+
+// NoteEdge links two notes of the graph, Count is the number of links
+// from the source to the target
+type NoteEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Count  int    `json:"count"`
+}
+
+type NoteGraph struct {
+	Nodes []NoteNode `json:"nodes"`
+	Edges []NoteEdge `json:"edges"`
+}
+
+// NoteLinkRewrite is a note whose links have been rewritten because the
+// notes they point at were renamed or moved
+type NoteLinkRewrite struct {
+	ShaID   string           `json:"sha_id"`
+	Name    string           `json:"name"`
+	Path    string           `json:"path"`
+	Changes []NoteLinkChange `json:"changes"`
+}
+
+// NoteLinkChange is a link of the note before and after the rewrite
+type NoteLinkChange struct {
+	Line int    `json:"line"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+type NoteLinkRepo interface {
+	GetNoteLinks(ctx context.Context, shaID string) ([]NoteLink, error)
+	GetNoteBacklinks(ctx context.Context, shaID string) ([]NoteLink, error)
+	GetNoteGraph(ctx context.Context, userID int) (NoteGraph, error)
+}
+
+type NoteLinkUsecase interface {
+	// GetNoteLinks returns the links written in the note
+	GetNoteLinks(ctx context.Context, userID int, shaID string) ([]NoteLink, error)
+	// GetNoteBacklinks returns the links of the other notes to the note
+	GetNoteBacklinks(ctx context.Context, userID int, shaID string) ([]NoteLink, error)
+	// GetNoteGraph returns the notes of the user with the links between them
+	GetNoteGraph(ctx context.Context, userID int) (NoteGraph, error)
+}
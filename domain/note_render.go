@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// NoteRender is the sanitised html of a note at its updated_at
+type NoteRender struct {
+	ShaID     string    `json:"sha_id"`
+	HTML      string    `json:"html"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type NoteRenderUsecase interface {
+	// RenderNote turns the markdown of the note into html, reusing the
+	// previous render while the note is not updated
+	RenderNote(ctx context.Context, userID int, shaID string) (NoteRender, error)
+}
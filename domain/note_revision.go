@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// DefaultNoteRevisionRetention is used when the user has no settings yet
+const DefaultNoteRevisionRetention = 100
+
+// NoteRevision is the content of a note at the time it was saved
+type NoteRevision struct {
+	ID        int         `json:"id"`
+	FileShaID string      `json:"file_sha_id"`
+	Note      null.String `json:"note"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type NoteRevisionRepo interface {
+	GetNoteRevisions(ctx context.Context, fileShaID string) ([]NoteRevision, error)
+	FindNoteRevision(ctx context.Context, fileShaID string, id int) (NoteRevision, error)
+}
+
+type NoteRevisionUsecase interface {
+	GetNoteRevisions(ctx context.Context, userID int, shaID string) ([]NoteRevision, error)
+	FindNoteRevision(ctx context.Context, userID int, shaID string, id int) (NoteRevision, error)
+	// DiffNoteRevisions returns a line based unified diff between two
+	// revisions, revisions of more than 10000 lines are refused
+	DiffNoteRevisions(ctx context.Context, userID int, shaID string, fromID int, toID int) (string, error)
+	// RestoreNoteRevision saves the content of the revision as a new revision
+	RestoreNoteRevision(ctx context.Context, userID int, shaID string, id int) (Note, error)
+}
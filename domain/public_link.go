@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// PublicLink lets anybody without an account read a note or a folder
+type PublicLink struct {
+	ID int `json:"id"`
+	// Token is signed by the usecase before it is given out, it is the part of the url of the link
+	Token     string `json:"token"`
+	FileShaID string `json:"file_sha_id"`
+	UserID    int    `json:"user_id"`
+	// Password is the argon2id hash, empty when the link has no password
+	Password    string    `json:"-"`
+	HasPassword bool      `json:"has_password"`
+	ExpiresAt   null.Time `json:"expires_at"`
+	RevokedAt   null.Time `json:"revoked_at"`
+	ViewCount   int       `json:"view_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PublicLinkParams struct {
+	ShaID     string
+	ExpiresAt null.Time
+	// Password is the plain password, empty means no password
+	Password string
+}
+
+// PublicView is what the visitor of a public link sees, Children is only
+// set for a folder and Note with HTML only for a note
+type PublicView struct {
+	File      File        `json:"file"`
+	Children  []File      `json:"children,omitempty"`
+	Note      null.String `json:"note,omitempty"`
+	HTML      string      `json:"html,omitempty"`
+	ViewCount int         `json:"view_count"`
+}
+
+type PublicLinkRepo interface {
+	// CreatePublicLink returns ErrDuplicateID when the token is already used
+	CreatePublicLink(ctx context.Context, link PublicLink) (PublicLink, error)
+	FindPublicLink(ctx context.Context, token string) (PublicLink, error)
+	GetPublicLinks(ctx context.Context, userID int, shaID string) ([]PublicLink, error)
+	RevokePublicLink(ctx context.Context, userID int, id int) error
+	// IncrementPublicLinkViews returns the new view count
+	IncrementPublicLinkViews(ctx context.Context, id int) (int, error)
+}
+
+type PublicLinkUsecase interface {
+	CreatePublicLink(ctx context.Context, userID int, params PublicLinkParams) (PublicLink, error)
+	GetPublicLinks(ctx context.Context, userID int, shaID string) ([]PublicLink, error)
+	RevokePublicLink(ctx context.Context, userID int, id int) error
+	// ViewPublicLink opens the file of the link, shaID is empty for the file
+	// itself or the sha id of a note under the folder of the link
+	ViewPublicLink(ctx context.Context, token string, password string, shaID string) (PublicView, error)
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	// MaxReminderOffsets is the number of reminders a note can have for a user
+	MaxReminderOffsets = 10
+	// MaxReminderOffset is the earliest a reminder can be sent, in minutes before the due time
+	MaxReminderOffset = 365 * 24 * 60
+	// MaxReminderAttempts is the number of times a reminder is tried before giving up
+	MaxReminderAttempts = 5
+)
+
+// Reminder is sent to the user Offset minutes before the due time of the
+// note, a note has one reminder per offset for each user following it
+type Reminder struct {
+	ID        int       `json:"id"`
+	FileShaID string    `json:"file_sha_id"`
+	UserID    int       `json:"user_id"`
+	DueAt     time.Time `json:"due_at"`
+	Offset    int       `json:"offset"`
+	RemindAt  time.Time `json:"remind_at"`
+	SentAt    null.Time `json:"sent_at"`
+	Attempts  int       `json:"attempts"`
+	// LastError is why the last attempt to send the reminder failed
+	LastError string `json:"last_error"`
+	// DeliveredChannels are the names of the notifiers the reminder was sent
+	// through, an attempt after a failure only tries the other ones
+	DeliveredChannels []string  `json:"delivered_channels"`
+	CreatedAt         time.Time `json:"created_at"`
+	// Name and Path of the note are only filled when listing the reminders of the user
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// ReminderParams set the due time of a note for the user and when to be reminded of it
+type ReminderParams struct {
+	DueAt time.Time `json:"due_at"`
+	// Offsets are the minutes before DueAt the reminders are sent, a single
+	// reminder at DueAt when empty
+	Offsets []int `json:"offsets"`
+}
+
+// Notification is a reminder shown in the app
+type Notification struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	FileShaID string    `json:"file_sha_id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	ReadAt    null.Time `json:"read_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReminderNotice is a due reminder with what is needed to tell its user
+type ReminderNotice struct {
+	Reminder Reminder
+	User     User
+	Note     File
+}
+
+// Title is the subject of the message telling the reminder
+func (n ReminderNotice) Title() string {
+	return fmt.Sprintf("Reminder: %s", n.Note.Name)
+}
+
+// Body is the text of the message telling the reminder
+func (n ReminderNotice) Body() string {
+	due := n.Reminder.DueAt.UTC().Format("2006-01-02 15:04 MST")
+	if n.Reminder.Offset == 0 {
+		return fmt.Sprintf("%s is due now (%s).", n.Note.Path, due)
+	}
+
+	return fmt.Sprintf("%s is due on %s.", n.Note.Path, due)
+}
+
+// Notifier sends due reminders through a channel
+type Notifier interface {
+	// Name of the channel, used in the logs and the errors
+	Name() string
+	// Notify returns an error when the reminder must be tried again
+	Notify(ctx context.Context, notice ReminderNotice) error
+}
+
+type ReminderRepo interface {
+	// SetNoteReminders replaces the reminders of the user on the note
+	SetNoteReminders(ctx context.Context, shaID string, userID int, reminders []Reminder) ([]Reminder, error)
+	GetNoteReminders(ctx context.Context, shaID string, userID int) ([]Reminder, error)
+	// GetUserReminders returns the reminders of the user not sent yet
+	GetUserReminders(ctx context.Context, userID int) ([]Reminder, error)
+	DeleteNoteReminders(ctx context.Context, shaID string, userID int) error
+	// ClaimDueReminders takes at most count due reminders until the claim
+	// expires, a reminder claimed by another scheduler is skipped
+	ClaimDueReminders(ctx context.Context, now time.Time, claimedUntil time.Time, count int) ([]Reminder, error)
+	MarkReminderSent(ctx context.Context, id int, sentAt time.Time) error
+	// RetryReminder releases the claim at retryAt with the reason of the
+	// failure and the channels the reminder has been delivered through
+	RetryReminder(ctx context.Context, id int, retryAt time.Time, reason string, delivered []string) error
+}
+
+type NotificationRepo interface {
+	CreateNotification(ctx context.Context, notification Notification) (Notification, error)
+	GetNotifications(ctx context.Context, userID int, limit int) ([]Notification, error)
+	ReadNotification(ctx context.Context, userID int, id int) error
+}
+
+type ReminderUsecase interface {
+	SetNoteReminders(ctx context.Context, userID int, shaID string, params ReminderParams) ([]Reminder, error)
+	GetNoteReminders(ctx context.Context, userID int, shaID string) ([]Reminder, error)
+	GetUserReminders(ctx context.Context, userID int) ([]Reminder, error)
+	DeleteNoteReminders(ctx context.Context, userID int, shaID string) error
+	GetNotifications(ctx context.Context, userID int) ([]Notification, error)
+	ReadNotification(ctx context.Context, userID int, id int) error
+	// SendDueReminders sends the reminders due at now through every notifier
+	// and returns how many were sent. Several schedulers can run it at once
+	SendDueReminders(ctx context.Context, now time.Time) (int, error)
+}
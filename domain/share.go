@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// access levels, each one also allows what the ones before it allow
+const (
+	ShareRoleViewer    = "viewer"
+	ShareRoleCommenter = "commenter"
+	ShareRoleEditor    = "editor"
+	// ShareRoleOwner is never stored, it is the access the owner of the file has
+	ShareRoleOwner = "owner"
+)
+
+// Share grants a user access to a file, a folder share also covers
+// everything under the folder
+type Share struct {
+	ID        int    `json:"id"`
+	FileShaID string `json:"file_sha_id"`
+	OwnerID   int    `json:"owner_id"`
+	// UserID is the user the file is shared with
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SharedFile is a file shared with the user, Owner is the username of the owner
+type SharedFile struct {
+	File
+	Role  string `json:"role"`
+	Owner string `json:"owner"`
+}
+
+type ShareRepo interface {
+	// UpsertShare creates the share or changes its role when the file is already shared with the user
+	UpsertShare(ctx context.Context, share Share) (Share, error)
+	GetFileShares(ctx context.Context, shaID string) ([]Share, error)
+	// GetUserShares returns the shares of the user on any of the files
+	GetUserShares(ctx context.Context, userID int, shaIDs []string) ([]Share, error)
+	GetSharedFiles(ctx context.Context, userID int) ([]SharedFile, error)
+	DeleteShare(ctx context.Context, shaID string, userID int) error
+}
+
+type ShareUsecase interface {
+	// ShareFile shares the file of the owner with the user having the username
+	ShareFile(ctx context.Context, userID int, shaID string, username string, role string) (Share, error)
+	GetFileShares(ctx context.Context, userID int, shaID string) ([]Share, error)
+	// UnshareFile is allowed to the owner and to the user leaving the share
+	UnshareFile(ctx context.Context, userID int, shaID string, sharedUserID int) error
+	GetSharedFiles(ctx context.Context, userID int) ([]SharedFile, error)
+	// Authorize returns the file when the user has at least the role on it,
+	// either as the owner or through a share on the file or a folder above it.
+	// The returned file keeps the id of the owner in UserID
+	Authorize(ctx context.Context, userID int, shaID string, role string) (File, error)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type Tag struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+	// UsageCount is only filled when listing the tags of the user
+	UsageCount int       `json:"usage_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type TagRepo interface {
+	// UpsertTag returns the tag with the name, creating it when missing
+	UpsertTag(ctx context.Context, userID int, name string) (Tag, error)
+	FindTag(ctx context.Context, userID int, id int) (Tag, error)
+	GetTags(ctx context.Context, userID int) ([]Tag, error)
+	GetNoteTags(ctx context.Context, userID int, shaID string) ([]Tag, error)
+	AttachTag(ctx context.Context, shaID string, tagID int) error
+	DetachTag(ctx context.Context, shaID string, tagID int) error
+	RenameTag(ctx context.Context, tag Tag) (Tag, error)
+	// MergeTag moves every note of the from tag to the into tag and deletes the from tag
+	MergeTag(ctx context.Context, userID int, fromID int, intoID int) error
+	DeleteTag(ctx context.Context, userID int, id int) error
+}
+
+type TagUsecase interface {
+	GetTags(ctx context.Context, userID int) ([]Tag, error)
+	GetNoteTags(ctx context.Context, userID int, shaID string) ([]Tag, error)
+	AttachTag(ctx context.Context, userID int, shaID string, name string) (Tag, error)
+	DetachTag(ctx context.Context, userID int, shaID string, tagID int) error
+	RenameTag(ctx context.Context, userID int, id int, name string) (Tag, error)
+	MergeTag(ctx context.Context, userID int, fromID int, intoID int) (Tag, error)
+	DeleteTag(ctx context.Context, userID int, id int) error
+}
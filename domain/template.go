@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// Template is a note flagged as a template, new notes are created from its
+// content with the placeholders expanded. A template is shared like any
+// other note, the users it is shared with can create notes from it
+type Template struct {
+	ShaID       string      `json:"sha_id"`
+	FolderShaID null.String `json:"folder_sha_id"`
+	UserID      int         `json:"user_id"`
+	// Owner is the username of the owner of the note
+	Owner       string `json:"owner"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	// Note and Prompts are only filled when finding a template, Prompts are
+	// the placeholders the caller gives the value of
+	Note      null.String `json:"note"`
+	Prompts   []string    `json:"prompts"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TemplateParams are the values written in a template to create a note from it
+type TemplateParams struct {
+	FolderShaID string `json:"folder_sha_id"`
+	// Name of the new note, the name of the template when empty. The
+	// placeholders are expanded in the name too
+	Name string `json:"name"`
+	// Values of the prompts of the template, they also override the values
+	// of {{date}}, {{time}}, {{user.name}} and {{folder}}
+	Values map[string]string `json:"values"`
+	// TimeZone of {{date}} and {{time}}, UTC when empty
+	TimeZone string `json:"time_zone"`
+}
+
+type TemplateRepo interface {
+	// UpsertTemplate flags the note as a template or changes its description
+	UpsertTemplate(ctx context.Context, template Template) (Template, error)
+	FindTemplate(ctx context.Context, shaID string) (Template, error)
+	// GetTemplates returns the templates of the user and the ones shared with the user
+	GetTemplates(ctx context.Context, userID int) ([]Template, error)
+	DeleteTemplate(ctx context.Context, shaID string) error
+}
+
+type TemplateUsecase interface {
+	GetTemplates(ctx context.Context, userID int) ([]Template, error)
+	FindTemplate(ctx context.Context, userID int, shaID string) (Template, error)
+	// SetTemplate and UnsetTemplate are only allowed to the owner of the note
+	SetTemplate(ctx context.Context, userID int, shaID string, description string) (Template, error)
+	UnsetTemplate(ctx context.Context, userID int, shaID string) error
+	// CreateNoteFromTemplate creates a note with the content of the template,
+	// every placeholder must have a value
+	CreateNoteFromTemplate(ctx context.Context, userID int, shaID string, params TemplateParams) (Note, error)
+}
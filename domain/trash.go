@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTrashRetentionDays is how long deleted files stay in the trash
+// when TRASH_RETENTION_DAYS is not set
+const DefaultTrashRetentionDays = 30
+
+type TrashRepo interface {
+	// GetTrash returns the deleted files, without the descendants deleted together with them
+	GetTrash(ctx context.Context, userID int) ([]File, error)
+	FindTrashedFile(ctx context.Context, userID int, shaID string) (File, error)
+	// RestoreFile brings back the file and the descendants deleted together with it,
+	// at the folder and path of the file given
+	RestoreFile(ctx context.Context, file File) ([]File, error)
+	// EmptyTrash and PurgeTrash delete the files for good and return how many were deleted
+	EmptyTrash(ctx context.Context, userID int) (int, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+}
+
+type TrashUsecase interface {
+	GetTrash(ctx context.Context, userID int) ([]File, error)
+	// RestoreFile puts the file back at its original path,
+	// or in the root when its folder is not there anymore
+	RestoreFile(ctx context.Context, userID int, shaID string) ([]File, error)
+	EmptyTrash(ctx context.Context, userID int) (int, error)
+	// PurgeTrash deletes the files of every user that were trashed before the time
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+}
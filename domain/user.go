@@ -37,6 +37,13 @@ type LoginResponse struct {
 	User User `json:"user"`
 }
 
+type UserSettings struct {
+	UserID int `json:"user_id"`
+	// number of note revisions kept, 0 keeps all
+	NoteRevisionRetention int       `json:"note_revision_retention"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
 type UserRepo interface {
 	Register(ctx context.Context, user User) (User, error)
 	Login(ctx context.Context, username string) (User, error)
@@ -45,6 +52,8 @@ type UserRepo interface {
 	FindUserByEmail(ctx context.Context, email string) (User, error)
 	FindUserByPhoneNumber(ctx context.Context, phoneNumber string) (User, error)
 	FindUserByUsernameOrEmailOrPhoneNumber(ctx context.Context, username, email, phone_number string) (User, error)
+	FindUserSettings(ctx context.Context, userID int) (UserSettings, error)
+	UpsertUserSettings(ctx context.Context, settings UserSettings) (UserSettings, error)
 }
 
 type UserUsecase interface {
@@ -54,4 +63,6 @@ type UserUsecase interface {
 	CheckUniqueUserByUsername(ctx context.Context, username string) (bool, error)
 	CheckUniqueUserByEmail(ctx context.Context, email string) (bool, error)
 	CheckUniqueUserByPhoneNumber(ctx context.Context, phoneNumber string) (bool, error)
+	GetUserSettings(ctx context.Context, userID int) (UserSettings, error)
+	UpdateUserSettings(ctx context.Context, userID int, settings UserSettings) (UserSettings, error)
 }
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+type FileHandler struct {
+	FileUsecase domain.FileUsecase
+}
+
+func NewFileHandler(r *chi.Mux, u domain.FileUsecase) {
+	handler := &FileHandler{
+		FileUsecase: u,
+	}
+
+	// make group v1
+	r.Route("/file", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(middleware.MyMiddleware)
+			r.Get("/tree", helpers.RecoverWrap(handler.GetFileTree))
+			r.Put("/{sha_id}/rename", helpers.RecoverWrap(handler.RenameFile))
+			r.Put("/{sha_id}/move", helpers.RecoverWrap(handler.MoveFile))
+		})
+	})
+
+}
+
+func (f FileHandler) GetFileTree(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form query params
+	query := r.URL.Query()
+
+	depth := 0
+	if value := query.Get("depth"); value != "" {
+		depth, err = strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// call usecase
+	tree, err := f.FileUsecase.GetFileTree(r.Context(), credentials.ID, query.Get("sha_id"), depth)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "tree found",
+		Data: map[string]interface{}{
+			"tree": tree,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f FileHandler) RenameFile(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form body json
+	req := struct {
+		Name string `json:"name"`
+	}{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get request form query params, a dry run only previews the changes
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// call usecase
+	files, notes, err := f.FileUsecase.RenameFile(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), req.Name, dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "file renamed",
+		Data: map[string]interface{}{
+			"files":   files,
+			"notes":   notes,
+			"dry_run": dryRun,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f FileHandler) MoveFile(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form body json, empty folder moves the file to root
+	req := struct {
+		FolderShaID string `json:"folder_sha_id"`
+	}{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get request form query params, a dry run only previews the changes
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// call usecase
+	files, notes, err := f.FileUsecase.MoveFile(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), req.FolderShaID, dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "file moved",
+		Data: map[string]interface{}{
+			"files":   files,
+			"notes":   notes,
+			"dry_run": dryRun,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+type TrashHandler struct {
+	TrashUsecase domain.TrashUsecase
+}
+
+func NewTrashHandler(r *chi.Mux, u domain.TrashUsecase) {
+	handler := &TrashHandler{
+		TrashUsecase: u,
+	}
+
+	// make group v1
+	r.Route("/trash", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(middleware.MyMiddleware)
+			r.Get("/", helpers.RecoverWrap(handler.GetTrash))
+			r.Delete("/", helpers.RecoverWrap(handler.EmptyTrash))
+			r.Post("/{sha_id}/restore", helpers.RecoverWrap(handler.RestoreFile))
+		})
+	})
+
+}
+
+func (t TrashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	files, err := t.TrashUsecase.GetTrash(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "trash found",
+		Data: map[string]interface{}{
+			"files": files,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (t TrashHandler) RestoreFile(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	files, err := t.TrashUsecase.RestoreFile(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "file restored",
+		Data: map[string]interface{}{
+			"files": files,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (t TrashHandler) EmptyTrash(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	count, err := t.TrashUsecase.EmptyTrash(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "trash emptied",
+		Data: map[string]interface{}{
+			"deleted": count,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,416 @@
+package file_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	note_repo_pg "github.com/ihsanbudiman/notes_app/note/repository/postgres"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresFileRepo struct {
+	DB     *sql.DB
+	Source sqlcpg.Querier
+	// InTx is set on the repo bound to the transaction of Transaction
+	InTx bool
+}
+
+// Transaction implements domain.FileRepo
+func (p postgresFileRepo) Transaction(ctx context.Context, fn func(repo domain.FileRepo) error) error {
+	// a repo already bound to a transaction runs fn in it
+	if p.InTx {
+		return fn(p)
+	}
+
+	return helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		return fn(postgresFileRepo{
+			DB:     p.DB,
+			Source: sqlcpg.New(tx),
+			InTx:   true,
+		})
+	})
+}
+
+// FindFile implements domain.FileRepo
+func (p postgresFileRepo) FindFile(ctx context.Context, userID int, shaID string) (domain.File, error) {
+	data, err := p.Source.FindFile(ctx, sqlcpg.FindFileParams{
+		ShaID:  shaID,
+		UserID: int32(userID),
+	})
+
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	return toDomainFile(data), nil
+}
+
+// FindFileByShaID implements domain.FileRepo
+func (p postgresFileRepo) FindFileByShaID(ctx context.Context, shaID string) (domain.File, error) {
+	data, err := p.Source.FindFileByShaID(ctx, shaID)
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	return toDomainFile(data), nil
+}
+
+// FindFileByPath implements domain.FileRepo
+func (p postgresFileRepo) FindFileByPath(ctx context.Context, userID int, path string) (domain.File, error) {
+	data, err := p.Source.FindFileByPath(ctx, sqlcpg.FindFileByPathParams{
+		UserID: int32(userID),
+		Path:   path,
+	})
+
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	return toDomainFile(data), nil
+}
+
+// FindFileForUpdate implements domain.FileRepo
+func (p postgresFileRepo) FindFileForUpdate(ctx context.Context, userID int, shaID string) (domain.File, error) {
+	data, err := p.Source.FindFileForUpdate(ctx, sqlcpg.FindFileForUpdateParams{
+		ShaID:  shaID,
+		UserID: int32(userID),
+	})
+
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	return toDomainFile(data), nil
+}
+
+// LockFileTree implements domain.FileRepo
+func (p postgresFileRepo) LockFileTree(ctx context.Context, userID int) error {
+	// the row of the user stands for the whole tree
+	return p.Source.LockUser(ctx, int32(userID))
+}
+
+// CountFilesByName implements domain.FileRepo
+func (p postgresFileRepo) CountFilesByName(ctx context.Context, userID int, folderShaID string, name string, excludeShaID string) (int, error) {
+	count, err := p.Source.CountFilesByName(ctx, sqlcpg.CountFilesByNameParams{
+		UserID:      int32(userID),
+		FolderShaID: folderShaID,
+		Name:        name,
+		ShaID:       excludeShaID,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetFileAncestors implements domain.FileRepo
+func (p postgresFileRepo) GetFileAncestors(ctx context.Context, userID int, shaID string) ([]string, error) {
+	return p.Source.GetFileAncestors(ctx, sqlcpg.GetFileAncestorsParams{
+		ShaID:  shaID,
+		UserID: int32(userID),
+	})
+}
+
+// UpdateFilePath implements domain.FileRepo
+func (p postgresFileRepo) UpdateFilePath(ctx context.Context, file domain.File, oldPath string) ([]domain.File, error) {
+	files, err := updateFilePath(ctx, p.Source, file, oldPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the links waiting for a note of the new paths now point at it
+	for _, v := range files {
+		if v.Type != domain.FileTypeNote {
+			continue
+		}
+
+		err = p.Source.ResolveNoteLinks(ctx, sqlcpg.ResolveNoteLinksParams{
+			TargetShaID: sql.NullString{String: v.ShaID, Valid: true},
+			UserID:      int32(v.UserID),
+			Path:        v.Path,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// updateFilePath is shared with the trash, which moves restored files the same way
+func updateFilePath(ctx context.Context, q sqlcpg.Querier, file domain.File, oldPath string) ([]domain.File, error) {
+	now := time.Now()
+
+	data, err := q.UpdateFile(ctx, sqlcpg.UpdateFileParams{
+		ShaID:       file.ShaID,
+		UserID:      int32(file.UserID),
+		FolderShaID: file.FolderShaID.String,
+		Name:        file.Name,
+		Path:        file.Path,
+		UpdatedAt:   now,
+	})
+	if helpers.IsUniqueViolation(err, "files_user_id_folder_sha_id_name") {
+		return nil, fmt.Errorf("%w: name already used in the folder", domain.ErrConflict)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := []domain.File{toDomainFile(data)}
+
+	// the etag of a note is made from notes.updated_at, a renamed or moved
+	// note gets a new one so a change made on the old name is refused
+	if data.Type == domain.FileTypeNote {
+		err = q.TouchNote(ctx, sqlcpg.TouchNoteParams{
+			FileShaID: data.ShaID,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.Type != domain.FileTypeFolder {
+		return files, nil
+	}
+
+	err = q.UpdateFolderParentBySha(ctx, sqlcpg.UpdateFolderParentByShaParams{
+		ParentShaID: data.FolderShaID,
+		UpdatedAt:   now,
+		ShaID:       data.ShaID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := q.GetFileDescendants(ctx, sqlcpg.GetFileDescendantsParams{
+		FolderShaID: data.ShaID,
+		UserID:      data.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(descendants) == 0 || oldPath == data.Path {
+		return files, nil
+	}
+
+	shaIDs := []string{}
+	for _, v := range descendants {
+		shaIDs = append(shaIDs, v.ShaID)
+	}
+
+	err = q.UpdateFilePathPrefix(ctx, sqlcpg.UpdateFilePathPrefixParams{
+		NewPath:   data.Path,
+		OldPath:   oldPath,
+		UpdatedAt: now,
+		UserID:    data.UserID,
+		ShaIds:    shaIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the prefix of every descendant path has been replaced in the database
+	for _, v := range descendants {
+		file := toDomainFile(sqlcpg.File(v))
+		file.Path = data.Path + file.Path[len(oldPath):]
+		file.UpdatedAt = null.TimeFrom(now)
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// GetFileTree implements domain.FileRepo
+func (p postgresFileRepo) GetFileTree(ctx context.Context, userID int, folderShaID string, maxDepth int) ([]domain.File, error) {
+	data, err := p.Source.GetFileTree(ctx, sqlcpg.GetFileTreeParams{
+		FolderShaID: folderShaID,
+		UserID:      int32(userID),
+		MaxDepth:    int32(maxDepth),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := []domain.File{}
+	for _, v := range data {
+		files = append(files, toDomainFile(sqlcpg.File{
+			ID:          v.ID,
+			FolderShaID: v.FolderShaID,
+			Name:        v.Name,
+			Type:        v.Type,
+			CreatedAt:   v.CreatedAt,
+			UpdatedAt:   v.UpdatedAt,
+			ShaID:       v.ShaID,
+			Path:        v.Path,
+			UserID:      v.UserID,
+		}))
+	}
+
+	return files, nil
+}
+
+// GetLinkingNotes implements domain.FileRepo
+func (p postgresFileRepo) GetLinkingNotes(ctx context.Context, shaIDs []string) ([]domain.Note, error) {
+	data, err := p.Source.GetLinkingNotes(ctx, shaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []domain.Note{}
+	for _, v := range data {
+		notes = append(notes, domain.Note{
+			ID:          int(v.ID),
+			ShaID:       v.ShaID,
+			FolderShaID: null.NewString(v.FolderShaID, v.FolderShaID != ""),
+			UserID:      int(v.UserID),
+			Name:        v.Name,
+			Path:        v.Path,
+			Note:        null.String{NullString: v.Note},
+			CreatedAt:   v.CreatedAt,
+			UpdatedAt:   v.UpdatedAt,
+		})
+	}
+
+	return notes, nil
+}
+
+// GetNoteLinks implements domain.FileRepo
+func (p postgresFileRepo) GetNoteLinks(ctx context.Context, shaID string) ([]domain.NoteLink, error) {
+	data, err := p.Source.GetNoteLinks(ctx, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	links := []domain.NoteLink{}
+	for _, v := range data {
+		links = append(links, domain.NoteLink{
+			ID:          int(v.ID),
+			SourceShaID: v.SourceShaID,
+			TargetShaID: null.String{NullString: v.TargetShaID},
+			Target:      v.Target,
+			Heading:     v.Heading,
+			Alias:       v.Alias,
+			Line:        int(v.Line),
+			Snippet:     v.Snippet,
+			CreatedAt:   v.CreatedAt,
+		})
+	}
+
+	return links, nil
+}
+
+// RewriteNote implements domain.FileRepo
+func (p postgresFileRepo) RewriteNote(ctx context.Context, note domain.Note, links []domain.NoteLink, retention int) error {
+	updated, err := p.Source.UpdateNote(ctx, sqlcpg.UpdateNoteParams{
+		FileShaID: note.ShaID,
+		Note:      note.Note.NullString,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.Source.CreateNoteRevision(ctx, sqlcpg.CreateNoteRevisionParams{
+		FileShaID: updated.FileShaID,
+		Note:      updated.Note,
+		CreatedAt: updated.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if retention > 0 {
+		err = p.Source.PruneNoteRevisions(ctx, sqlcpg.PruneNoteRevisionsParams{
+			FileShaID: updated.FileShaID,
+			Keep:      int32(retention),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = p.Source.DeleteNoteLinks(ctx, note.ShaID)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range links {
+		err = p.Source.CreateNoteLink(ctx, sqlcpg.CreateNoteLinkParams{
+			SourceShaID: note.ShaID,
+			TargetShaID: v.TargetShaID.NullString,
+			UserID:      int32(note.UserID),
+			Target:      v.Target,
+			Heading:     v.Heading,
+			Alias:       v.Alias,
+			Line:        int32(v.Line),
+			Snippet:     v.Snippet,
+			CreatedAt:   updated.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindNoteForUpdate implements domain.FileRepo
+func (p postgresFileRepo) FindNoteForUpdate(ctx context.Context, userID int, shaID string) (domain.Note, error) {
+	data, err := p.Source.FindNoteForUpdate(ctx, sqlcpg.FindNoteForUpdateParams{
+		ShaID:  shaID,
+		UserID: int32(userID),
+	})
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return domain.Note{
+		ID:          int(data.ID),
+		ShaID:       data.ShaID,
+		FolderShaID: null.NewString(data.FolderShaID, data.FolderShaID != ""),
+		UserID:      int(data.UserID),
+		Name:        data.Name,
+		Path:        data.Path,
+		Note:        null.String{NullString: data.Note},
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}, nil
+}
+
+// SaveNote implements domain.FileRepo
+func (p postgresFileRepo) SaveNote(ctx context.Context, note domain.Note, retention int) (domain.Note, error) {
+	return note_repo_pg.SaveNote(ctx, p.Source, note, retention)
+}
+
+func toDomainFile(data sqlcpg.File) domain.File {
+	return domain.File{
+		ID:          int(data.ID),
+		FolderShaID: null.NewString(data.FolderShaID, data.FolderShaID != ""),
+		ShaID:       data.ShaID,
+		UserID:      int(data.UserID),
+		Path:        data.Path,
+		Name:        data.Name,
+		Type:        data.Type,
+		CreatedAt:   null.TimeFrom(data.CreatedAt),
+		UpdatedAt:   null.TimeFrom(data.UpdatedAt),
+		DeletedAt:   null.Time{NullTime: data.DeletedAt},
+	}
+}
+
+func NewPostgresFileRepo(db *sql.DB, source sqlcpg.Querier) domain.FileRepo {
+	return &postgresFileRepo{
+		DB:     db,
+		Source: source,
+	}
+}
@@ -0,0 +1,246 @@
+package file_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresTrashRepo struct {
+	DB     *sql.DB
+	Source sqlcpg.Querier
+}
+
+// GetTrash implements domain.TrashRepo
+func (p postgresTrashRepo) GetTrash(ctx context.Context, userID int) ([]domain.File, error) {
+	data, err := p.Source.GetTrash(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []domain.File{}
+	for _, v := range data {
+		files = append(files, toDomainFile(v))
+	}
+
+	return files, nil
+}
+
+// FindTrashedFile implements domain.TrashRepo
+func (p postgresTrashRepo) FindTrashedFile(ctx context.Context, userID int, shaID string) (domain.File, error) {
+	data, err := p.Source.FindTrashedFile(ctx, sqlcpg.FindTrashedFileParams{
+		ShaID:  shaID,
+		UserID: int32(userID),
+	})
+
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	return toDomainFile(data), nil
+}
+
+// RestoreFile implements domain.TrashRepo
+func (p postgresTrashRepo) RestoreFile(ctx context.Context, file domain.File) ([]domain.File, error) {
+	files := []domain.File{}
+
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		trashed, err := q.FindTrashedFile(ctx, sqlcpg.FindTrashedFileParams{
+			ShaID:  file.ShaID,
+			UserID: int32(file.UserID),
+		})
+		if err != nil {
+			return err
+		}
+
+		// only the descendants deleted together with the file come back,
+		// the ones deleted before stay in the trash
+		restored := []sqlcpg.File{trashed}
+		if trashed.Type == domain.FileTypeFolder {
+			descendants, err := q.GetFileDescendants(ctx, sqlcpg.GetFileDescendantsParams{
+				FolderShaID: trashed.ShaID,
+				UserID:      trashed.UserID,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, v := range descendants {
+				if v.DeletedAt.Valid && v.DeletedAt.Time.Equal(trashed.DeletedAt.Time) {
+					restored = append(restored, sqlcpg.File(v))
+				}
+			}
+		}
+
+		// the path is updated while the file is still deleted so only
+		// restoring it can clash with a file of the same name
+		moved, err := updateFilePath(ctx, q, file, trashed.Path)
+		if err != nil {
+			return err
+		}
+
+		paths := map[string]string{}
+		for _, v := range moved {
+			paths[v.ShaID] = v.Path
+		}
+
+		shaIDs := []string{}
+		for _, v := range restored {
+			shaIDs = append(shaIDs, v.ShaID)
+
+			restoredFile := toDomainFile(v)
+			restoredFile.DeletedAt = null.Time{}
+			if path, ok := paths[v.ShaID]; ok {
+				restoredFile.Path = path
+			}
+
+			files = append(files, restoredFile)
+		}
+		files[0].FolderShaID = file.FolderShaID
+
+		err = q.RestoreFiles(ctx, sqlcpg.RestoreFilesParams{
+			UserID: trashed.UserID,
+			ShaIds: shaIDs,
+		})
+		if helpers.IsUniqueViolation(err, "files_user_id_folder_sha_id_name") {
+			return fmt.Errorf("%w: name already used in the folder, rename the other file first", domain.ErrConflict)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = q.RestoreFolders(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		return q.RestoreNotes(ctx, shaIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// EmptyTrash implements domain.TrashRepo
+func (p postgresTrashRepo) EmptyTrash(ctx context.Context, userID int) (int, error) {
+	var count int
+
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		data, err := q.GetDeletedFiles(ctx, int32(userID))
+		if err != nil {
+			return err
+		}
+
+		count = len(data)
+		return purgeFiles(ctx, q, data)
+	})
+
+	return count, err
+}
+
+// PurgeTrash implements domain.TrashRepo
+func (p postgresTrashRepo) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	var count int
+
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		data, err := q.GetExpiredFiles(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		count = len(data)
+		return purgeFiles(ctx, q, data)
+	})
+
+	return count, err
+}
+
+// purgeFiles deletes the files with their folders, notes, revisions, tags, links, reminders, templates, shares and public links
+func purgeFiles(ctx context.Context, q sqlcpg.Querier, files []sqlcpg.File) error {
+	shaIDsByUser := map[int32][]string{}
+	for _, v := range files {
+		shaIDsByUser[v.UserID] = append(shaIDsByUser[v.UserID], v.ShaID)
+	}
+
+	for userID, shaIDs := range shaIDsByUser {
+		err := q.DeleteNoteRevisions(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteNoteTags(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		// the links to the purged notes wait for a new note with the same name
+		err = q.DeleteNotesLinks(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.UnresolveNoteLinks(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteReminders(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteTemplates(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteFileShares(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeletePublicLinks(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteNotes(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteFolders(ctx, shaIDs)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteFiles(ctx, sqlcpg.DeleteFilesParams{
+			UserID: userID,
+			ShaIds: shaIDs,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NewPostgresTrashRepo(db *sql.DB, source sqlcpg.Querier) domain.TrashRepo {
+	return &postgresTrashRepo{db, source}
+}
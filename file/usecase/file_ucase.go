@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/guregu/null.v4"
+)
+
+// errDryRun rolls back the transaction of a dry run once the changes are known
+var errDryRun = errors.New("dry run")
+
+type FileUseCaseImpl struct {
+	FileRepo    domain.FileRepo
+	UserUsecase domain.UserUsecase
+}
+
+// Transaction implements domain.FileUsecase
+func (f FileUseCaseImpl) Transaction(ctx context.Context, fn func(files domain.FileUsecase, repo domain.FileRepo) error) error {
+	return f.FileRepo.Transaction(ctx, func(repo domain.FileRepo) error {
+		return fn(FileUseCaseImpl{
+			FileRepo:    repo,
+			UserUsecase: f.UserUsecase,
+		}, repo)
+	})
+}
+
+// GetFileTree implements domain.FileUsecase
+func (f FileUseCaseImpl) GetFileTree(ctx context.Context, userID int, shaID string, maxDepth int) ([]domain.FileTree, error) {
+	if maxDepth < 0 {
+		return nil, fmt.Errorf("%w: depth cannot be negative", domain.ErrBadParamInput)
+	}
+
+	// zero depth means no limit
+	if maxDepth == 0 {
+		maxDepth = math.MaxInt32
+	}
+
+	// empty sha id means the whole workspace of the user
+	if shaID == "" {
+		files, err := f.FileRepo.GetFileTree(ctx, userID, "", maxDepth)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildFileTree(files, ""), nil
+	}
+
+	root, err := f.FileRepo.FindFile(ctx, userID, shaID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: file not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// a note has no children
+	node := domain.FileTree{File: root, Children: []domain.FileTree{}}
+	if root.Type == domain.FileTypeFolder {
+		files, err := f.FileRepo.GetFileTree(ctx, userID, root.ShaID, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Children = buildFileTree(files, root.ShaID)
+	}
+
+	return []domain.FileTree{node}, nil
+}
+
+// RenameFile implements domain.FileUsecase
+func (f FileUseCaseImpl) RenameFile(ctx context.Context, userID int, shaID string, name string, dryRun bool) ([]domain.File, []domain.NoteLinkRewrite, error) {
+	err := helpers.ValidateFileName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []domain.File
+	var rewrites []domain.NoteLinkRewrite
+
+	// the checks and the path rewrite must see the same data
+	err = f.FileRepo.Transaction(ctx, func(repo domain.FileRepo) error {
+		file, err := findFileForUpdate(ctx, repo, userID, shaID)
+		if err != nil {
+			return err
+		}
+
+		file.Name = name
+		files, err = updateFilePath(ctx, repo, file, helpers.JoinPath(path.Dir(file.Path), name))
+		if err != nil {
+			return err
+		}
+
+		rewrites, err = f.rewriteNoteLinks(ctx, repo, userID, files)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, nil, err
+	}
+
+	return files, rewrites, nil
+}
+
+// MoveFile implements domain.FileUsecase
+func (f FileUseCaseImpl) MoveFile(ctx context.Context, userID int, shaID string, folderShaID string, dryRun bool) ([]domain.File, []domain.NoteLinkRewrite, error) {
+	if folderShaID == shaID {
+		return nil, nil, fmt.Errorf("%w: file cannot be moved into itself", domain.ErrBadParamInput)
+	}
+
+	var files []domain.File
+	var rewrites []domain.NoteLinkRewrite
+
+	// the checks and the path rewrite must see the same data
+	err := f.FileRepo.Transaction(ctx, func(repo domain.FileRepo) error {
+		// the ancestors of the new folder are not locked, two moves at the
+		// same time could each pass the cycle check and make a cycle together
+		err := repo.LockFileTree(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		file, err := findFileForUpdate(ctx, repo, userID, shaID)
+		if err != nil {
+			return err
+		}
+
+		// empty folder means moving the file to the root
+		parentPath := ""
+		if folderShaID != "" {
+			parent, err := repo.FindFile(ctx, userID, folderShaID)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: folder not found", domain.ErrNotFound)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if parent.Type != domain.FileTypeFolder {
+				return fmt.Errorf("%w: folder_sha_id is not a folder", domain.ErrBadParamInput)
+			}
+
+			// the file cannot be an ancestor of its new folder
+			ancestors, err := repo.GetFileAncestors(ctx, userID, parent.ShaID)
+			if err != nil {
+				return err
+			}
+
+			for _, ancestor := range ancestors {
+				if ancestor == file.ShaID {
+					return fmt.Errorf("%w: folder cannot be moved into its own descendant", domain.ErrBadParamInput)
+				}
+			}
+
+			parentPath = parent.Path
+		}
+
+		file.FolderShaID = null.NewString(folderShaID, folderShaID != "")
+		files, err = updateFilePath(ctx, repo, file, helpers.JoinPath(parentPath, file.Name))
+		if err != nil {
+			return err
+		}
+
+		rewrites, err = f.rewriteNoteLinks(ctx, repo, userID, files)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, nil, err
+	}
+
+	return files, rewrites, nil
+}
+
+func findFileForUpdate(ctx context.Context, repo domain.FileRepo, userID int, shaID string) (domain.File, error) {
+	// check if sha id is not empty
+	if shaID == "" {
+		return domain.File{}, fmt.Errorf("%w: sha_id cannot be empty", domain.ErrBadParamInput)
+	}
+
+	file, err := repo.FindFileForUpdate(ctx, userID, shaID)
+	if err == sql.ErrNoRows {
+		return domain.File{}, fmt.Errorf("%w: file not found", domain.ErrNotFound)
+	}
+
+	return file, err
+}
+
+// check the new name against the siblings and save the new path
+func updateFilePath(ctx context.Context, repo domain.FileRepo, file domain.File, newPath string) ([]domain.File, error) {
+	count, err := repo.CountFilesByName(ctx, file.UserID, file.FolderShaID.String, file.Name, file.ShaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, fmt.Errorf("%w: name already used in the folder", domain.ErrConflict)
+	}
+
+	oldPath := file.Path
+	file.Path = newPath
+
+	files, err := repo.UpdateFilePath(ctx, file, oldPath)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: file not found", domain.ErrNotFound)
+	}
+
+	return files, err
+}
+
+// rewriteNoteLinks writes the new names of the notes among files in the
+// links pointing at them, the notes of the links are saved in the transaction
+// of the rename
+func (f FileUseCaseImpl) rewriteNoteLinks(ctx context.Context, repo domain.FileRepo, userID int, files []domain.File) ([]domain.NoteLinkRewrite, error) {
+	rewrites := []domain.NoteLinkRewrite{}
+
+	renamed := map[string]domain.File{}
+	shaIDs := []string{}
+	for _, file := range files {
+		if file.Type == domain.FileTypeNote {
+			renamed[file.ShaID] = file
+			shaIDs = append(shaIDs, file.ShaID)
+		}
+	}
+
+	if len(shaIDs) == 0 {
+		return rewrites, nil
+	}
+
+	notes, err := repo.GetLinkingNotes(ctx, shaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(notes) == 0 {
+		return rewrites, nil
+	}
+
+	settings, err := f.UserUsecase.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range notes {
+		links, err := repo.GetNoteLinks(ctx, note.ShaID)
+		if err != nil {
+			return nil, err
+		}
+
+		// the note every target written in the note points at
+		targets := map[string]null.String{}
+		for _, link := range links {
+			targets[link.Target] = link.TargetShaID
+		}
+
+		content := note.Note.String
+		changes := []domain.NoteLinkChange{}
+		written := map[string]string{}
+
+		content = helpers.ReplaceWikiLinks(content, func(link helpers.WikiLink) (string, bool) {
+			file, ok := renamed[targets[link.Target].String]
+			if !ok {
+				return "", false
+			}
+
+			// the new target cannot be one already pointing at another note
+			target := renamedLinkTarget(link.Target, file)
+			if other, ok := targets[target]; ok && other.String != file.ShaID {
+				target = strings.TrimPrefix(file.Path, "/")
+			}
+
+			if target == link.Target {
+				return "", false
+			}
+
+			old := note.Note.String[link.Start:link.End]
+			link.Target = target
+			written[target] = file.ShaID
+
+			changes = append(changes, domain.NoteLinkChange{
+				Line: link.Line,
+				Old:  old,
+				New:  link.String(),
+			})
+
+			return link.String(), true
+		})
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		for target, shaID := range written {
+			targets[target] = null.StringFrom(shaID)
+		}
+
+		// the links of the new content point at the same notes as before
+		links = []domain.NoteLink{}
+		for _, link := range helpers.ParseWikiLinks(content) {
+			target := targets[link.Target]
+
+			// an embed that is not a note is most likely an image
+			if link.Embed && !target.Valid {
+				continue
+			}
+
+			links = append(links, domain.NoteLink{
+				SourceShaID: note.ShaID,
+				TargetShaID: target,
+				Target:      link.Target,
+				Heading:     link.Heading,
+				Alias:       link.Alias,
+				Line:        link.Line,
+				Snippet:     link.Snippet,
+			})
+		}
+
+		note.Note = null.StringFrom(content)
+		err = repo.RewriteNote(ctx, note, links, settings.NoteRevisionRetention)
+		if err != nil {
+			return nil, err
+		}
+
+		rewrites = append(rewrites, domain.NoteLinkRewrite{
+			ShaID:   note.ShaID,
+			Name:    note.Name,
+			Path:    note.Path,
+			Changes: changes,
+		})
+	}
+
+	return rewrites, nil
+}
+
+// renamedLinkTarget writes the target of a link with the new path of the
+// file. A name stays a name and a path keeps as many folders as it had
+func renamedLinkTarget(target string, file domain.File) string {
+	// a link by sha id never breaks
+	name := strings.Trim(target, "/")
+	if name == file.ShaID {
+		return target
+	}
+
+	prefix := ""
+	if strings.HasPrefix(target, "/") {
+		prefix = "/"
+	}
+
+	extension := ""
+	if strings.HasSuffix(strings.ToLower(name), helpers.WikiLinkNoteExtension) {
+		extension = name[len(name)-len(helpers.WikiLinkNoteExtension):]
+		name = name[:len(name)-len(helpers.WikiLinkNoteExtension)]
+	}
+
+	segments := strings.Split(strings.Trim(file.Path, "/"), "/")
+	if count := strings.Count(name, "/") + 1; count < len(segments) {
+		segments = segments[len(segments)-count:]
+	}
+
+	return prefix + strings.Join(segments, "/") + extension
+}
+
+// nest the flat list of files under their folders starting from rootShaID
+func buildFileTree(files []domain.File, rootShaID string) []domain.FileTree {
+	children := map[string][]domain.File{}
+	for _, file := range files {
+		parent := file.FolderShaID.ValueOrZero()
+		children[parent] = append(children[parent], file)
+	}
+
+	var build func(parent string) []domain.FileTree
+	build = func(parent string) []domain.FileTree {
+		nodes := []domain.FileTree{}
+		for _, file := range children[parent] {
+			nodes = append(nodes, domain.FileTree{
+				File:     file,
+				Children: build(file.ShaID),
+			})
+		}
+
+		return nodes
+	}
+
+	return build(rootShaID)
+}
+
+func NewFileUseCase(fr domain.FileRepo, uu domain.UserUsecase) domain.FileUsecase {
+	return &FileUseCaseImpl{
+		FileRepo:    fr,
+		UserUsecase: uu,
+	}
+}
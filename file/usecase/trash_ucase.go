@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/guregu/null.v4"
+)
+
+type TrashUseCaseImpl struct {
+	TrashRepo domain.TrashRepo
+	FileRepo  domain.FileRepo
+}
+
+// GetTrash implements domain.TrashUsecase
+func (t TrashUseCaseImpl) GetTrash(ctx context.Context, userID int) ([]domain.File, error) {
+	// call repository
+	return t.TrashRepo.GetTrash(ctx, userID)
+}
+
+// RestoreFile implements domain.TrashUsecase
+func (t TrashUseCaseImpl) RestoreFile(ctx context.Context, userID int, shaID string) ([]domain.File, error) {
+	if shaID == "" {
+		return nil, fmt.Errorf("%w: sha_id cannot be empty", domain.ErrBadParamInput)
+	}
+
+	file, err := t.TrashRepo.FindTrashedFile(ctx, userID, shaID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: file not found in the trash", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the folder may have been deleted or purged since, then the file goes to the root
+	parentPath := ""
+	if file.FolderShaID.ValueOrZero() != "" {
+		parent, err := t.FileRepo.FindFile(ctx, userID, file.FolderShaID.String)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		if err == sql.ErrNoRows {
+			file.FolderShaID = null.String{}
+		} else {
+			parentPath = parent.Path
+		}
+	}
+
+	file.Path = helpers.JoinPath(parentPath, file.Name)
+
+	// call repository
+	files, err := t.TrashRepo.RestoreFile(ctx, file)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: file not found in the trash", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// EmptyTrash implements domain.TrashUsecase
+func (t TrashUseCaseImpl) EmptyTrash(ctx context.Context, userID int) (int, error) {
+	// call repository
+	return t.TrashRepo.EmptyTrash(ctx, userID)
+}
+
+// PurgeTrash implements domain.TrashUsecase
+func (t TrashUseCaseImpl) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	// call repository
+	return t.TrashRepo.PurgeTrash(ctx, before)
+}
+
+func NewTrashUseCase(tr domain.TrashRepo, fr domain.FileRepo) domain.TrashUsecase {
+	return &TrashUseCaseImpl{
+		TrashRepo: tr,
+		FileRepo:  fr,
+	}
+}
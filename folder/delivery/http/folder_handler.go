@@ -0,0 +1,252 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+type FolderHandler struct {
+	FolderUsecase domain.FolderUsecase
+}
+
+func NewFolderHandler(r *chi.Mux, u domain.FolderUsecase) {
+	handler := &FolderHandler{
+		FolderUsecase: u,
+	}
+
+	// make group v1
+	r.Route("/folder", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(middleware.MyMiddleware)
+			r.Post("/", helpers.RecoverWrap(handler.CreateFolder))
+			r.Get("/children", helpers.RecoverWrap(handler.GetFolderChildren))
+			r.Get("/{sha_id}", helpers.RecoverWrap(handler.FindFolder))
+			r.Get("/{sha_id}/children", helpers.RecoverWrap(handler.GetFolderChildren))
+			r.Put("/{sha_id}/rename", helpers.RecoverWrap(handler.RenameFolder))
+			r.Put("/{sha_id}/move", helpers.RecoverWrap(handler.MoveFolder))
+			r.Delete("/{sha_id}", helpers.RecoverWrap(handler.DeleteFolder))
+		})
+	})
+
+}
+
+func (f FolderHandler) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request body
+	var folder domain.Folder
+
+	err = json.NewDecoder(r.Body).Decode(&folder)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	folder, err = f.FolderUsecase.CreateFolder(r.Context(), credentials.ID, folder)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "folder created",
+		Data: map[string]interface{}{
+			"folder": folder,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f FolderHandler) FindFolder(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	folder, err := f.FolderUsecase.FindFolder(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	// the client already has this version
+	etag := helpers.ETag(folder.UpdatedAt)
+	if helpers.MatchETag(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "folder found",
+		Data: map[string]interface{}{
+			"folder": folder,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f FolderHandler) GetFolderChildren(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase, empty sha id lists the root
+	files, err := f.FolderUsecase.GetFolderChildren(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "children found",
+		Data: map[string]interface{}{
+			"files": files,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f FolderHandler) RenameFolder(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form body json
+	req := struct {
+		Name string `json:"name"`
+	}{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase, If-Match holds the etag of the version the change was made on
+	folder, files, err := f.FolderUsecase.RenameFolder(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), req.Name, r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "folder renamed",
+		Data: map[string]interface{}{
+			"folder": folder,
+			"files":  files,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", helpers.ETag(folder.UpdatedAt))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f FolderHandler) MoveFolder(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form body json, empty parent moves the folder to root
+	req := struct {
+		ParentShaID string `json:"parent_sha_id"`
+	}{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase, If-Match holds the etag of the version the change was made on
+	folder, files, err := f.FolderUsecase.MoveFolder(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), req.ParentShaID, r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "folder moved",
+		Data: map[string]interface{}{
+			"folder": folder,
+			"files":  files,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", helpers.ETag(folder.UpdatedAt))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f FolderHandler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form query params
+	recursive := false
+	if value := r.URL.Query().Get("recursive"); value != "" {
+		recursive, err = strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// call usecase, If-Match holds the etag of the version the change was made on
+	err = f.FolderUsecase.DeleteFolder(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), recursive, r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "folder moved to trash",
+		Data:    nil,
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
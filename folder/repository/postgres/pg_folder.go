@@ -0,0 +1,260 @@
+package folder_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresFolderRepo struct {
+	DB     *sql.DB
+	Source sqlcpg.Querier
+}
+
+// CreateFolder implements domain.FolderRepo
+func (p postgresFolderRepo) CreateFolder(ctx context.Context, folder domain.Folder) (domain.Folder, error) {
+	var result domain.Folder
+	now := time.Now()
+
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		// the parent is locked so it is not deleted while the folder is created in it
+		if folder.ParentShaID.String != "" {
+			_, err := q.LockFolder(ctx, sqlcpg.LockFolderParams{
+				ShaID:  folder.ParentShaID.String,
+				UserID: int32(folder.UserID),
+			})
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: parent folder not found", domain.ErrNotFound)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		file, err := q.CreateFile(ctx, sqlcpg.CreateFileParams{
+			FolderShaID: folder.ParentShaID.String,
+			Name:        folder.Name,
+			Type:        domain.FileTypeFolder,
+			ShaID:       folder.ShaID,
+			Path:        folder.Path,
+			UserID:      int32(folder.UserID),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if helpers.IsUniqueViolation(err, "files_user_id_folder_sha_id_name") {
+			return fmt.Errorf("%w: name already used in the folder", domain.ErrConflict)
+		}
+
+		if helpers.IsUniqueViolation(err, "files_sha_id") {
+			return domain.ErrDuplicateID
+		}
+
+		if err != nil {
+			return err
+		}
+
+		data, err := q.CreateFolder(ctx, sqlcpg.CreateFolderParams{
+			ShaID: file.ShaID,
+			ParentID: sql.NullInt32{
+				Int32: int32(folder.ParentID.Int64),
+				Valid: folder.ParentID.Valid,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if helpers.IsUniqueViolation(err, "folders_sha_id") {
+			return domain.ErrDuplicateID
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result = toDomainFolder(sqlcpg.FindFolderRow{
+			ID:          data.ID,
+			ParentID:    data.ParentID,
+			ShaID:       file.ShaID,
+			FolderShaID: file.FolderShaID,
+			UserID:      file.UserID,
+			Name:        file.Name,
+			Path:        file.Path,
+			CreatedAt:   file.CreatedAt,
+			UpdatedAt:   file.UpdatedAt,
+		})
+		return nil
+	})
+
+	if err != nil {
+		return domain.Folder{}, err
+	}
+
+	return result, nil
+}
+
+// FindFolder implements domain.FolderRepo
+func (p postgresFolderRepo) FindFolder(ctx context.Context, userID int, shaID string) (domain.Folder, error) {
+	data, err := p.Source.FindFolder(ctx, sqlcpg.FindFolderParams{
+		ShaID:  shaID,
+		UserID: int32(userID),
+	})
+
+	if err != nil {
+		return domain.Folder{}, err
+	}
+
+	return toDomainFolder(data), nil
+}
+
+// GetFolderChildren implements domain.FolderRepo
+func (p postgresFolderRepo) GetFolderChildren(ctx context.Context, userID int, shaID string) ([]domain.File, error) {
+	data, err := p.Source.GetFolderChildren(ctx, sqlcpg.GetFolderChildrenParams{
+		UserID:      int32(userID),
+		FolderShaID: shaID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := []domain.File{}
+	for _, v := range data {
+		files = append(files, toDomainFile(v))
+	}
+
+	return files, nil
+}
+
+// DeleteFolder implements domain.FolderRepo
+func (p postgresFolderRepo) DeleteFolder(ctx context.Context, userID int, shaID string, recursive bool, version string) error {
+	return helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		// the tree is locked before the folder as the moves do, so no file
+		// is moved into the folder between the count of its children and the delete
+		err := q.LockUser(ctx, int32(userID))
+		if err != nil {
+			return err
+		}
+
+		// lock the folder so no rename, move or new child lands between the
+		// version check and the delete
+		_, err = q.FindFileForUpdate(ctx, sqlcpg.FindFileForUpdateParams{
+			ShaID:  shaID,
+			UserID: int32(userID),
+		})
+		if err != nil {
+			return err
+		}
+
+		// make sure the folder belongs to the user before touching folders table
+		folder, err := q.FindFolder(ctx, sqlcpg.FindFolderParams{
+			ShaID:  shaID,
+			UserID: int32(userID),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = helpers.CheckVersion(version, folder.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		// refuse to delete a folder that still has children unless asked to
+		if !recursive {
+			count, err := q.CountFolderChildren(ctx, sqlcpg.CountFolderChildrenParams{
+				UserID:      int32(userID),
+				FolderShaID: shaID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if count > 0 {
+				return fmt.Errorf("%w: folder is not empty", domain.ErrConflict)
+			}
+		}
+
+		descendants, err := q.GetFileDescendants(ctx, sqlcpg.GetFileDescendantsParams{
+			FolderShaID: shaID,
+			UserID:      int32(userID),
+		})
+		if err != nil {
+			return err
+		}
+
+		// descendants already in the trash keep their own deleted_at
+		shaIDs := []string{shaID}
+		for _, v := range descendants {
+			if !v.DeletedAt.Valid {
+				shaIDs = append(shaIDs, v.ShaID)
+			}
+		}
+
+		// the whole subtree is trashed at the same time so it can be restored together
+		now := time.Now()
+		err = q.TrashFiles(ctx, sqlcpg.TrashFilesParams{
+			DeletedAt: now,
+			UserID:    int32(userID),
+			ShaIds:    shaIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.TrashFolders(ctx, sqlcpg.TrashFoldersParams{
+			DeletedAt: now,
+			ShaIds:    shaIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.TrashNotes(ctx, sqlcpg.TrashNotesParams{
+			DeletedAt: now,
+			ShaIds:    shaIDs,
+		})
+	})
+}
+
+func toDomainFolder(data sqlcpg.FindFolderRow) domain.Folder {
+	return domain.Folder{
+		ID:          int(data.ID),
+		ParentID:    null.NewInt(int64(data.ParentID.Int32), data.ParentID.Valid),
+		ShaID:       data.ShaID,
+		ParentShaID: null.NewString(data.FolderShaID, data.FolderShaID != ""),
+		UserID:      int(data.UserID),
+		Name:        data.Name,
+		Path:        data.Path,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func toDomainFile(data sqlcpg.File) domain.File {
+	return domain.File{
+		ID:          int(data.ID),
+		FolderShaID: null.NewString(data.FolderShaID, data.FolderShaID != ""),
+		ShaID:       data.ShaID,
+		UserID:      int(data.UserID),
+		Path:        data.Path,
+		Name:        data.Name,
+		Type:        data.Type,
+		CreatedAt:   null.TimeFrom(data.CreatedAt),
+		UpdatedAt:   null.TimeFrom(data.UpdatedAt),
+		DeletedAt:   null.Time{NullTime: data.DeletedAt},
+	}
+}
+
+func NewPostgresFolderRepo(db *sql.DB, source sqlcpg.Querier) domain.FolderRepo {
+	return &postgresFolderRepo{db, source}
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/guregu/null.v4"
+)
+
+type FolderUseCaseImpl struct {
+	FolderRepo   domain.FolderRepo
+	FileUsecase  domain.FileUsecase
+	ShareUsecase domain.ShareUsecase
+	IDGenerator  helpers.IDGenerator
+}
+
+// CreateFolder implements domain.FolderUsecase
+func (f FolderUseCaseImpl) CreateFolder(ctx context.Context, userID int, folder domain.Folder) (domain.Folder, error) {
+	err := helpers.ValidateFileName(folder.Name)
+	if err != nil {
+		return domain.Folder{}, err
+	}
+
+	// find the parent folder, empty parent means root.
+	// a folder created in a shared folder belongs to the owner of the parent
+	parentPath := ""
+	ownerID := userID
+	folder.ParentID = null.Int{}
+	if folder.ParentShaID.ValueOrZero() != "" {
+		parent, err := f.findFolder(ctx, userID, folder.ParentShaID.String, domain.ShareRoleEditor)
+		if err != nil {
+			return domain.Folder{}, err
+		}
+
+		parentPath = parent.Path
+		ownerID = parent.UserID
+		folder.ParentID = null.IntFrom(int64(parent.ID))
+	}
+
+	folder.UserID = ownerID
+	folder.Path = helpers.JoinPath(parentPath, folder.Name)
+
+	// call repository, a new sha id is tried when the previous one is taken
+	var created domain.Folder
+	err = f.IDGenerator.Generate(func(shaID string) error {
+		folder.ShaID = shaID
+		created, err = f.FolderRepo.CreateFolder(ctx, folder)
+		return err
+	})
+	if err != nil {
+		return domain.Folder{}, err
+	}
+
+	return created, nil
+}
+
+// FindFolder implements domain.FolderUsecase
+func (f FolderUseCaseImpl) FindFolder(ctx context.Context, userID int, shaID string) (domain.Folder, error) {
+	return f.findFolder(ctx, userID, shaID, domain.ShareRoleViewer)
+}
+
+// findFolder returns the folder when the user has at least the role on it
+func (f FolderUseCaseImpl) findFolder(ctx context.Context, userID int, shaID string, role string) (domain.Folder, error) {
+	file, err := f.ShareUsecase.Authorize(ctx, userID, shaID, role)
+	if err != nil {
+		return domain.Folder{}, err
+	}
+
+	if file.Type != domain.FileTypeFolder {
+		return domain.Folder{}, fmt.Errorf("%w: folder not found", domain.ErrNotFound)
+	}
+
+	// call repository with the owner of the folder
+	folder, err := f.FolderRepo.FindFolder(ctx, file.UserID, shaID)
+	if err == sql.ErrNoRows {
+		return domain.Folder{}, fmt.Errorf("%w: folder not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.Folder{}, err
+	}
+
+	return folder, nil
+}
+
+// GetFolderChildren implements domain.FolderUsecase
+func (f FolderUseCaseImpl) GetFolderChildren(ctx context.Context, userID int, shaID string) ([]domain.File, error) {
+	// empty sha id means the root of the user
+	ownerID := userID
+	if shaID != "" {
+		folder, err := f.FindFolder(ctx, userID, shaID)
+		if err != nil {
+			return nil, err
+		}
+
+		ownerID = folder.UserID
+	}
+
+	// call repository
+	return f.FolderRepo.GetFolderChildren(ctx, ownerID, shaID)
+}
+
+// RenameFolder implements domain.FolderUsecase
+func (f FolderUseCaseImpl) RenameFolder(ctx context.Context, userID int, shaID string, name string, version string) (domain.Folder, []domain.File, error) {
+	folder, err := f.findFolder(ctx, userID, shaID, domain.ShareRoleEditor)
+	if err != nil {
+		return domain.Folder{}, nil, err
+	}
+
+	// the file usecase rewrites the path of every descendant, in the
+	// transaction the version is checked in
+	var files []domain.File
+	err = f.FileUsecase.Transaction(ctx, func(fu domain.FileUsecase, repo domain.FileRepo) error {
+		err := checkVersion(ctx, repo, folder.UserID, shaID, version)
+		if err != nil {
+			return err
+		}
+
+		files, _, err = fu.RenameFile(ctx, folder.UserID, shaID, name, false)
+		return err
+	})
+	if err != nil {
+		return domain.Folder{}, nil, err
+	}
+
+	folder, err = f.FindFolder(ctx, userID, shaID)
+	if err != nil {
+		return domain.Folder{}, nil, err
+	}
+
+	return folder, files, nil
+}
+
+// MoveFolder implements domain.FolderUsecase
+func (f FolderUseCaseImpl) MoveFolder(ctx context.Context, userID int, shaID string, parentShaID string, version string) (domain.Folder, []domain.File, error) {
+	folder, err := f.findFolder(ctx, userID, shaID, domain.ShareRoleEditor)
+	if err != nil {
+		return domain.Folder{}, nil, err
+	}
+
+	// the folder has to stay in the files of its owner
+	if parentShaID == "" && folder.UserID != userID {
+		return domain.Folder{}, nil, fmt.Errorf("%w: only the owner can move the folder to the root", domain.ErrForbidden)
+	}
+
+	if parentShaID != "" {
+		parent, err := f.findFolder(ctx, userID, parentShaID, domain.ShareRoleEditor)
+		if err != nil {
+			return domain.Folder{}, nil, err
+		}
+
+		if parent.UserID != folder.UserID {
+			return domain.Folder{}, nil, fmt.Errorf("%w: cannot move the folder to a folder of another user", domain.ErrBadParamInput)
+		}
+	}
+
+	// the file usecase checks for cycles and rewrites the path of every
+	// descendant, in the transaction the version is checked in
+	var files []domain.File
+	err = f.FileUsecase.Transaction(ctx, func(fu domain.FileUsecase, repo domain.FileRepo) error {
+		// the tree is locked before the folder as the move does
+		err := repo.LockFileTree(ctx, folder.UserID)
+		if err != nil {
+			return err
+		}
+
+		err = checkVersion(ctx, repo, folder.UserID, shaID, version)
+		if err != nil {
+			return err
+		}
+
+		files, _, err = fu.MoveFile(ctx, folder.UserID, shaID, parentShaID, false)
+		return err
+	})
+	if err != nil {
+		return domain.Folder{}, nil, err
+	}
+
+	folder, err = f.FindFolder(ctx, userID, shaID)
+	if err != nil {
+		return domain.Folder{}, nil, err
+	}
+
+	return folder, files, nil
+}
+
+// DeleteFolder implements domain.FolderUsecase
+func (f FolderUseCaseImpl) DeleteFolder(ctx context.Context, userID int, shaID string, recursive bool, version string) error {
+	folder, err := f.findFolder(ctx, userID, shaID, domain.ShareRoleEditor)
+	if err != nil {
+		return err
+	}
+
+	// call repository, the folder goes to the trash of its owner
+	err = f.FolderRepo.DeleteFolder(ctx, folder.UserID, shaID, recursive, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: folder not found", domain.ErrNotFound)
+	}
+
+	return err
+}
+
+// checkVersion locks the folder until the transaction of repo ends and
+// checks it is still at the version the change was made on
+func checkVersion(ctx context.Context, repo domain.FileRepo, userID int, shaID string, version string) error {
+	file, err := repo.FindFileForUpdate(ctx, userID, shaID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: folder not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return helpers.CheckVersion(version, file.UpdatedAt.Time)
+}
+
+func NewFolderUseCase(fr domain.FolderRepo, fu domain.FileUsecase, su domain.ShareUsecase, ig helpers.IDGenerator) domain.FolderUsecase {
+	return &FolderUseCaseImpl{
+		FolderRepo:   fr,
+		FileUsecase:  fu,
+		ShareUsecase: su,
+		IDGenerator:  ig,
+	}
+}
@@ -1,20 +1,27 @@
 module github.com/ihsanbudiman/notes_app
 
-go 1.19
+go 1.21
 
-require golang.org/x/crypto v0.0.0-20220817201139-bc19a97f63c8
+require (
+	github.com/go-chi/chi/v5 v5.0.7
+	github.com/golang-jwt/jwt/v4 v4.4.2
+	github.com/gorilla/websocket v1.5.3
+	github.com/joho/godotenv v1.4.0
+	github.com/lib/pq v1.10.6
+	github.com/microcosm-cc/bluemonday v1.0.26
+	github.com/stretchr/testify v1.8.0
+	github.com/yuin/goldmark v1.5.6
+	golang.org/x/crypto v0.14.0
+	golang.org/x/net v0.17.0
+	gopkg.in/guregu/null.v4 v4.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
+	github.com/aymerick/douceur v0.2.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/go-chi/chi/v5 v5.0.7 // indirect
-	github.com/golang-jwt/jwt/v4 v4.4.2 // indirect
-	github.com/guregu/null v4.0.0+incompatible // indirect
-	github.com/joho/godotenv v1.4.0 // indirect
-	github.com/lib/pq v1.10.6 // indirect
+	github.com/gorilla/css v1.0.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.4.0 // indirect
-	github.com/stretchr/testify v1.8.0 // indirect
-	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
-	gopkg.in/guregu/null.v4 v4.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 )
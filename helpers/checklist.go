@@ -0,0 +1,299 @@
+package helpers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// checklistItemPattern matches a task: indent, list marker, box and text
+var checklistItemPattern = regexp.MustCompile(`^([ \t]*)([-*+]|\d+[.)])[ \t]+\[([ xX])\](?:[ \t]+(.*))?$`)
+
+// checklistLine is an item of the checklist with where it is in the content
+type checklistLine struct {
+	item domain.ChecklistItem
+	// index of the line in the content, from 0
+	index int
+	// index of the last line of the item with the lines and items nested in it
+	end int
+	// index of the first line of the run of items the item is in
+	block int
+	// index of the line of the item this one is nested in, -1 at the top level
+	parent int
+	// byte offset of the box in the line
+	box    int
+	indent string
+	marker string
+}
+
+// ParseChecklist returns the items of the checklists of the markdown, the
+// ones in fenced code are left out
+func ParseChecklist(content string) []domain.ChecklistItem {
+	items := []domain.ChecklistItem{}
+	for _, v := range parseChecklistLines(strings.Split(content, "\n")) {
+		items = append(items, v.item)
+	}
+
+	return items
+}
+
+// ToggleChecklistItem checks the item of the anchor or unchecks it, only the box changes
+func ToggleChecklistItem(content string, anchor string) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	item, err := findChecklistLine(lines, anchor)
+	if err != nil {
+		return "", err
+	}
+
+	box := "x"
+	if item.item.Checked {
+		box = " "
+	}
+
+	line := lines[item.index]
+	lines[item.index] = line[:item.box] + box + line[item.box+1:]
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// AddChecklistItem writes an unchecked item after the item of the anchor and
+// the lines nested in it. Without anchor the item goes at the end of the last
+// checklist, or of the content when it has none
+func AddChecklistItem(content string, text string, after string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("%w: text cannot be empty", domain.ErrBadParamInput)
+	}
+
+	if strings.ContainsAny(text, "\r\n") {
+		return "", fmt.Errorf("%w: text cannot have line breaks", domain.ErrBadParamInput)
+	}
+
+	lines := strings.Split(content, "\n")
+
+	var sibling checklistLine
+	if after != "" {
+		item, err := findChecklistLine(lines, after)
+		if err != nil {
+			return "", err
+		}
+
+		sibling = item
+	} else {
+		parsed := parseChecklistLines(lines)
+
+		// no checklist yet, the item starts one at the end of the content
+		if len(parsed) == 0 {
+			if content != "" && !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+
+			return content + "- [ ] " + text, nil
+		}
+
+		// the last top level item of the last checklist
+		last := parsed[len(parsed)-1]
+		for _, v := range parsed {
+			if v.block == last.block && v.parent == -1 {
+				sibling = v
+			}
+		}
+	}
+
+	// the new line looks like its sibling, down to the line ending
+	line := sibling.indent + sibling.marker + " [ ] " + text
+	if strings.HasSuffix(lines[sibling.index], "\r") {
+		line += "\r"
+	}
+
+	result := append([]string{}, lines[:sibling.end+1]...)
+	result = append(result, line)
+	result = append(result, lines[sibling.end+1:]...)
+
+	return strings.Join(result, "\n"), nil
+}
+
+// ReorderChecklist puts the items of the anchors, with the lines nested in
+// them, in the given order. The anchors must be every item under the same parent
+func ReorderChecklist(content string, anchors []string) (string, error) {
+	if len(anchors) == 0 {
+		return "", fmt.Errorf("%w: anchors cannot be empty", domain.ErrBadParamInput)
+	}
+
+	lines := strings.Split(content, "\n")
+
+	ordered := []checklistLine{}
+	seen := map[int]bool{}
+	for _, anchor := range anchors {
+		item, err := findChecklistLine(lines, anchor)
+		if err != nil {
+			return "", err
+		}
+
+		if seen[item.index] {
+			return "", fmt.Errorf("%w: anchor %s is given twice", domain.ErrBadParamInput, anchor)
+		}
+		seen[item.index] = true
+
+		ordered = append(ordered, item)
+	}
+
+	// the siblings of the first item in the order of the content
+	first := ordered[0]
+	siblings := []checklistLine{}
+	for _, v := range parseChecklistLines(lines) {
+		if v.block == first.block && v.parent == first.parent {
+			siblings = append(siblings, v)
+		}
+	}
+
+	if len(siblings) != len(ordered) {
+		return "", fmt.Errorf("%w: anchors must be every item under the same parent", domain.ErrBadParamInput)
+	}
+
+	for _, v := range siblings {
+		if !seen[v.index] {
+			return "", fmt.Errorf("%w: anchors must be every item under the same parent", domain.ErrBadParamInput)
+		}
+	}
+
+	// the siblings follow each other, their lines are written again in the new order
+	start, end := siblings[0].index, siblings[len(siblings)-1].end
+
+	result := append([]string{}, lines[:start]...)
+	for _, v := range ordered {
+		result = append(result, lines[v.index:v.end+1]...)
+	}
+	result = append(result, lines[end+1:]...)
+
+	return strings.Join(result, "\n"), nil
+}
+
+// findChecklistLine finds the item of the anchor, the anchor of an item
+// whose line has changed since is refused
+func findChecklistLine(lines []string, anchor string) (checklistLine, error) {
+	var line int
+	_, err := fmt.Sscanf(anchor, "%d-", &line)
+	if err != nil || line < 1 {
+		return checklistLine{}, fmt.Errorf("%w: invalid anchor %s", domain.ErrBadParamInput, anchor)
+	}
+
+	for _, v := range parseChecklistLines(lines) {
+		if v.item.Line == line && v.item.Anchor == anchor {
+			return v, nil
+		}
+	}
+
+	return checklistLine{}, fmt.Errorf("%w: checklist item %s has changed", domain.ErrConflict, anchor)
+}
+
+func parseChecklistLines(lines []string) []checklistLine {
+	items := []checklistLine{}
+	fence := codeFence{}
+
+	// the indents and positions in items of the items the next line can be nested in
+	var indents []int
+	var open []int
+	block := -1
+	// a blank line only stays in a checklist when an indented line follows it
+	blank := false
+
+	for i, raw := range lines {
+		line := strings.TrimSuffix(raw, "\r")
+		inCode := fence.inCode(line)
+
+		if block != -1 && strings.TrimSpace(line) == "" {
+			blank = true
+			continue
+		}
+
+		match := checklistItemPattern.FindStringSubmatchIndex(line)
+		isItem := !inCode && match != nil
+		indent := indentWidth(line[:len(line)-len(strings.TrimLeft(line, " \t"))])
+
+		for len(indents) > 0 && indents[len(indents)-1] >= indent {
+			indents = indents[:len(indents)-1]
+			open = open[:len(open)-1]
+		}
+
+		// a line indented under an item is part of it, like the text going on
+		// below the item, a list that is not a checklist or fenced code
+		if !isItem && len(open) > 0 {
+			for _, p := range open {
+				items[p].end = i
+			}
+
+			blank = false
+			continue
+		}
+
+		// anything else ends the run of items, a new run starts a new tree
+		if !isItem || blank {
+			block = -1
+			blank = false
+			indents, open = nil, nil
+		}
+
+		if !isItem {
+			continue
+		}
+
+		if block == -1 {
+			block = i
+		}
+
+		parent := -1
+		if len(open) > 0 {
+			parent = items[open[len(open)-1]].index
+		}
+
+		// every item this one is nested in ends at this line for now
+		for _, p := range open {
+			items[p].end = i
+		}
+
+		text := ""
+		if match[8] >= 0 {
+			text = strings.TrimSpace(line[match[8]:match[9]])
+		}
+
+		items = append(items, checklistLine{
+			item: domain.ChecklistItem{
+				Anchor:  checklistAnchor(i+1, line),
+				Line:    i + 1,
+				Depth:   len(indents),
+				Checked: line[match[6]] != ' ',
+				Text:    text,
+			},
+			index:  i,
+			end:    i,
+			block:  block,
+			parent: parent,
+			box:    match[6],
+			indent: line[match[2]:match[3]],
+			marker: line[match[4]:match[5]],
+		})
+
+		indents = append(indents, indent)
+		open = append(open, len(items)-1)
+	}
+
+	return items
+}
+
+// checklistAnchor is the number of the line with a hash of its content
+func checklistAnchor(line int, content string) string {
+	h := fnv.New32a()
+	h.Write([]byte(content))
+
+	return fmt.Sprintf("%d-%08x", line, h.Sum32())
+}
+
+// indentWidth counts a tab as four spaces
+func indentWidth(indent string) int {
+	return len(strings.ReplaceAll(indent, "\t", "    "))
+}
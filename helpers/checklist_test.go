@@ -0,0 +1,247 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// anchorOf finds the anchor of the item with the text
+func anchorOf(t *testing.T, content string, text string) string {
+	for _, item := range ParseChecklist(content) {
+		if item.Text == text {
+			return item.Anchor
+		}
+	}
+
+	t.Fatalf("no checklist item %q", text)
+	return ""
+}
+
+func TestParseChecklist(t *testing.T) {
+	content := strings.Join([]string{
+		"- [ ] a",
+		"  goes on",
+		"  - plain",
+		"  - [x] b",
+		"- [ ] c",
+		"",
+		"text",
+		"```",
+		"- [ ] in code",
+		"```",
+	}, "\n")
+
+	items := ParseChecklist(content)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, "a", items[0].Text)
+	assert.Equal(t, 0, items[0].Depth)
+	assert.Equal(t, "b", items[1].Text)
+	assert.Equal(t, 1, items[1].Depth)
+	assert.Equal(t, 4, items[1].Line)
+	assert.True(t, items[1].Checked)
+	assert.Equal(t, "c", items[2].Text)
+}
+
+func TestToggleChecklistItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		item    string
+		want    string
+	}{
+		{
+			name:    "check",
+			content: "- [ ] a\n- [ ] b",
+			item:    "b",
+			want:    "- [ ] a\n- [x] b",
+		},
+		{
+			name:    "uncheck",
+			content: "- [X] a\n- [ ] b",
+			item:    "a",
+			want:    "- [ ] a\n- [ ] b",
+		},
+		{
+			name:    "nested item under a continuation",
+			content: "* [ ] a\n  goes on\n  * [ ] b",
+			item:    "b",
+			want:    "* [ ] a\n  goes on\n  * [x] b",
+		},
+		{
+			name:    "crlf line endings",
+			content: "1. [ ] a\r\n2. [ ] b\r\n",
+			item:    "a",
+			want:    "1. [x] a\r\n2. [ ] b\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToggleChecklistItem(tt.content, anchorOf(t, tt.content, tt.item))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToggleChecklistItemChanged(t *testing.T) {
+	anchor := anchorOf(t, "- [ ] a", "a")
+
+	_, err := ToggleChecklistItem("- [ ] b", anchor)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	_, err = ToggleChecklistItem("- [ ] a", "x")
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func TestAddChecklistItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		after   string
+		want    string
+	}{
+		{
+			name:    "no checklist yet",
+			content: "text",
+			want:    "text\n- [ ] new",
+		},
+		{
+			name:    "end of the last checklist",
+			content: "- [ ] a\n  - [ ] b\n\ntext",
+			want:    "- [ ] a\n  - [ ] b\n- [ ] new\n\ntext",
+		},
+		{
+			name:    "after an item and its nested items",
+			content: "- [ ] a\n  - [ ] b\n- [ ] c",
+			after:   "a",
+			want:    "- [ ] a\n  - [ ] b\n- [ ] new\n- [ ] c",
+		},
+		{
+			name:    "after the continuation of the item",
+			content: "- [ ] a\n  goes on\n- [ ] c",
+			after:   "a",
+			want:    "- [ ] a\n  goes on\n- [ ] new\n- [ ] c",
+		},
+		{
+			name:    "after the plain sub bullets of the item",
+			content: "- [ ] a\n  - plain\n    more\n- [ ] c",
+			after:   "a",
+			want:    "- [ ] a\n  - plain\n    more\n- [ ] new\n- [ ] c",
+		},
+		{
+			name:    "after a continuation past a blank line",
+			content: "- [ ] a\n\n  goes on\n\n- [ ] c",
+			after:   "a",
+			want:    "- [ ] a\n\n  goes on\n- [ ] new\n\n- [ ] c",
+		},
+		{
+			name:    "after a nested item",
+			content: "- [ ] a\n  + [ ] b\n    goes on\n- [ ] c",
+			after:   "b",
+			want:    "- [ ] a\n  + [ ] b\n    goes on\n  + [ ] new\n- [ ] c",
+		},
+		{
+			name:    "text after the checklist is not part of it",
+			content: "- [ ] a\ntext",
+			after:   "a",
+			want:    "- [ ] a\n- [ ] new\ntext",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := ""
+			if tt.after != "" {
+				after = anchorOf(t, tt.content, tt.after)
+			}
+
+			got, err := AddChecklistItem(tt.content, "new", after)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReorderChecklist(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		order   []string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "top level items",
+			content: "- [ ] a\n- [ ] b\n- [ ] c",
+			order:   []string{"c", "a", "b"},
+			want:    "- [ ] c\n- [ ] a\n- [ ] b",
+		},
+		{
+			name:    "items move with their nested items",
+			content: "- [ ] a\n  - [ ] a1\n- [ ] b",
+			order:   []string{"b", "a"},
+			want:    "- [ ] b\n- [ ] a\n  - [ ] a1",
+		},
+		{
+			name:    "items move with their continuation and plain sub bullets",
+			content: "- [ ] a\n  goes on\n  - plain\n- [ ] b\n\n  goes on too\ntext",
+			order:   []string{"b", "a"},
+			want:    "- [ ] b\n\n  goes on too\n- [ ] a\n  goes on\n  - plain\ntext",
+		},
+		{
+			name:    "nested items",
+			content: "- [ ] a\n  - [ ] a1\n    goes on\n  - [ ] a2\n- [ ] b",
+			order:   []string{"a2", "a1"},
+			want:    "- [ ] a\n  - [ ] a2\n  - [ ] a1\n    goes on\n- [ ] b",
+		},
+		{
+			name:    "not every sibling",
+			content: "- [ ] a\n- [ ] b\n- [ ] c",
+			order:   []string{"b", "a"},
+			wantErr: domain.ErrBadParamInput,
+		},
+		{
+			name:    "items of another checklist",
+			content: "- [ ] a\n\n- [ ] b",
+			order:   []string{"b", "a"},
+			wantErr: domain.ErrBadParamInput,
+		},
+		{
+			name:    "items under another parent",
+			content: "- [ ] a\n  - [ ] a1\n- [ ] b",
+			order:   []string{"a1", "b"},
+			wantErr: domain.ErrBadParamInput,
+		},
+		{
+			name:    "item given twice",
+			content: "- [ ] a\n- [ ] b",
+			order:   []string{"a", "a"},
+			wantErr: domain.ErrBadParamInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anchors := []string{}
+			for _, text := range tt.order {
+				anchors = append(anchors, anchorOf(t, tt.content, text))
+			}
+
+			got, err := ReorderChecklist(tt.content, anchors)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
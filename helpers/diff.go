@@ -0,0 +1,241 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+const (
+	// number of unchanged lines shown around every change
+	diffContext = 3
+	// MaxDiffLines is the most lines each text of a diff can have
+	MaxDiffLines = 10000
+)
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// make a line based unified diff from a to b, empty when they are equal
+func UnifiedDiff(fromName, toName, a, b string) (string, error) {
+	aLines, bLines := splitLines(a), splitLines(b)
+
+	// the time of the diff grows with the square of the lines that differ
+	if len(aLines) > MaxDiffLines || len(bLines) > MaxDiffLines {
+		return "", fmt.Errorf("%w: cannot diff texts of more than %d lines", domain.ErrBadParamInput, MaxDiffLines)
+	}
+
+	ops := diffLines(aLines, bLines)
+
+	// position of every op in a and b
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if op.kind != '+' {
+			aPos[i+1]++
+		}
+		if op.kind != '-' {
+			bPos[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// extend the hunk while the next change is close enough
+		last := i
+		for j := i + 1; j < len(ops) && j-last <= 2*diffContext; j++ {
+			if ops[j].kind != ' ' {
+				last = j
+			}
+		}
+
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := last + diffContext + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		aCount, bCount := aPos[end]-aPos[start], bPos[end]-bPos[start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aPos[start], aCount), hunkRange(bPos[start], bCount))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+
+		i = end
+	}
+
+	return sb.String(), nil
+}
+
+func hunkRange(start, count int) string {
+	// an empty range points at the line before it
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// find the shortest edit script from a to b with the linear space variant
+// of the myers algorithm, the common start and end are left out and the
+// rest is split at the middle of its edit script
+func diffLines(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	return appendDiff(ops, a, b)
+}
+
+func appendDiff(ops []diffOp, a, b []string) []diffOp {
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		ops = append(ops, diffOp{' ', a[start]})
+		start++
+	}
+	a, b = a[start:], b[start:]
+
+	end := 0
+	for end < len(a) && end < len(b) && a[len(a)-1-end] == b[len(b)-1-end] {
+		end++
+	}
+	common := a[len(a)-end:]
+	a, b = a[:len(a)-end], b[:len(b)-end]
+
+	x, y, ok := middleSnake(a, b)
+	if ok {
+		ops = appendDiff(ops, a[:x], b[:y])
+		ops = appendDiff(ops, a[x:], b[y:])
+	} else {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+	}
+
+	for _, line := range common {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	return ops
+}
+
+// middleSnake searches the shortest edit script from both ends at once and
+// returns where the two searches meet, false when a and b have nothing in
+// common. Only the furthest point of every diagonal is kept, not every round
+func middleSnake(a, b []string) (int, int, bool) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	// with an odd delta the searches meet on a forward step
+	odd := delta%2 != 0
+
+	// diagonals left out once they run off the edit graph
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				i := offset + delta - k
+				if i >= 0 && i < len(backward) && backward[i] != -1 && x >= n-backward[i] {
+					return x, y, true
+				}
+			}
+		}
+
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+
+			// x and y count from the end of a and b
+			y := x - k
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				i := offset + delta - k
+				if i >= 0 && i < len(forward) && forward[i] != -1 {
+					fx := forward[i]
+					fy := fx - (i - offset)
+					if fx >= n-x {
+						return fx, fy, true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
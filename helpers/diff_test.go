@@ -0,0 +1,148 @@
+package helpers
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{
+			name: "same text",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "far changes make two hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "a\nb\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "to empty",
+			a:    "a\n",
+			b:    "",
+			want: "--- a\n+++ b\n@@ -1 +0,0 @@\n-a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnifiedDiff("a", "b", tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnifiedDiffTooLong(t *testing.T) {
+	long := strings.Repeat("line\n", MaxDiffLines+1)
+
+	_, err := UnifiedDiff("a", "b", long, "")
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+
+	_, err = UnifiedDiff("a", "b", "", long)
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+	}{
+		{name: "both empty"},
+		{name: "nothing in common", a: []string{"a", "b", "c"}, b: []string{"x", "y"}},
+		{name: "insert in the middle", a: []string{"a", "c"}, b: []string{"a", "b", "c"}},
+		{name: "moved line", a: []string{"a", "b", "c", "d"}, b: []string{"b", "c", "d", "a"}},
+		{name: "repeated lines", a: []string{"a", "b", "a", "b", "a"}, b: []string{"b", "a", "b", "a", "b"}},
+	}
+
+	// random texts made of few distinct lines have long common runs
+	random := rand.New(rand.NewSource(1))
+	lines := func() []string {
+		s := make([]string, random.Intn(40))
+		for i := range s {
+			s[i] = string(rune('a' + random.Intn(4)))
+		}
+		return s
+	}
+	for i := 0; i < 200; i++ {
+		tests = append(tests, struct {
+			name string
+			a    []string
+			b    []string
+		}{name: "random", a: lines(), b: lines()})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := diffLines(tt.a, tt.b)
+
+			// the ops give back both texts
+			a, b := []string{}, []string{}
+			edits := 0
+			for _, op := range ops {
+				if op.kind != '+' {
+					a = append(a, op.line)
+				}
+				if op.kind != '-' {
+					b = append(b, op.line)
+				}
+				if op.kind != ' ' {
+					edits++
+				}
+			}
+			assert.Equal(t, append([]string{}, tt.a...), a)
+			assert.Equal(t, append([]string{}, tt.b...), b)
+
+			// and the script is the shortest one
+			assert.Equal(t, len(tt.a)+len(tt.b)-2*longestCommon(tt.a, tt.b), edits)
+		})
+	}
+}
+
+// longestCommon is the length of the longest common subsequence
+func longestCommon(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,348 @@
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// hard line break, only turned into markdown once the paragraph is known
+	enmlBreak    = "\x00"
+	enmlTodo     = "[ ] "
+	enmlTodoDone = "[x] "
+)
+
+var (
+	enmlSpaces    = regexp.MustCompile(`[ \t\r\n]+`)
+	enmlSelfClose = regexp.MustCompile(`<(en-todo|en-media|en-crypt)([^>]*?)\s*/>`)
+	enmlSpecials  = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`)
+)
+
+// ENMLMedia is an en-media of a note, the resource it points at is
+// matched by its hash
+type ENMLMedia struct {
+	Hash string
+	Type string
+}
+
+// turn the enml of an evernote note into markdown, the en-media elements
+// are replaced by what media returns so the caller can handle the resources
+func ENMLToMarkdown(enml string, media func(ENMLMedia) string) (string, error) {
+	// enml is xhtml, an html parser does not close <en-todo/> on its own
+	enml = enmlSelfClose.ReplaceAllString(enml, "<$1$2></$1>")
+
+	doc, err := html.Parse(strings.NewReader(enml))
+	if err != nil {
+		return "", fmt.Errorf("invalid enml: %w", err)
+	}
+
+	c := &enmlConverter{media: media}
+	blocks := c.blocks(doc)
+
+	md := strings.Join(blocks, "\n\n")
+	if md != "" {
+		md += "\n"
+	}
+
+	return md, nil
+}
+
+type enmlConverter struct {
+	media func(ENMLMedia) string
+}
+
+func isENMLBlock(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+
+	switch n.Data {
+	case "p", "div", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "li",
+		"blockquote", "pre", "hr", "table", "en-note", "html", "head", "body", "center":
+		return true
+	}
+
+	return false
+}
+
+// blocks renders the children of n, runs of inline content become paragraphs
+func (c *enmlConverter) blocks(n *html.Node) []string {
+	blocks := []string{}
+	var inline strings.Builder
+
+	flush := func() {
+		// breaks at the edges of a paragraph, like the <div><br/></div> of empty lines, are dropped
+		text := strings.Trim(inline.String(), " \n"+enmlBreak)
+		text = strings.ReplaceAll(text, enmlBreak, "\\\n")
+
+		// a line starting with an en-todo is a task list item
+		if strings.HasPrefix(text, enmlTodo) || strings.HasPrefix(text, enmlTodoDone) {
+			text = "- " + text
+		}
+
+		if text != "" {
+			blocks = append(blocks, text)
+		}
+		inline.Reset()
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if !isENMLBlock(child) {
+			inline.WriteString(c.inline(child))
+			continue
+		}
+
+		flush()
+		blocks = append(blocks, c.block(child)...)
+	}
+
+	flush()
+	return blocks
+}
+
+func (c *enmlConverter) block(n *html.Node) []string {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := strings.TrimSpace(strings.ReplaceAll(c.inlineChildren(n), enmlBreak, " "))
+		if text == "" {
+			return nil
+		}
+
+		return []string{strings.Repeat("#", int(n.Data[1]-'0')) + " " + text}
+	case "ul", "ol":
+		return []string{c.list(n)}
+	case "blockquote":
+		inner := strings.Join(c.blocks(n), "\n\n")
+		if inner == "" {
+			return nil
+		}
+
+		return []string{prefixLines(inner, "> ", "> ")}
+	case "pre":
+		return []string{"```\n" + strings.TrimRight(textContent(n), "\n") + "\n```"}
+	case "hr":
+		return []string{"---"}
+	case "table":
+		return []string{c.table(n)}
+	case "head":
+		return nil
+	}
+
+	// div, p and the document wrappers
+	return c.blocks(n)
+}
+
+func (c *enmlConverter) list(n *html.Node) string {
+	// the new evernote editor marks checklists with styles instead of en-todo
+	todo := strings.Contains(attr(n, "style"), "--en-todo:true")
+
+	items := []string{}
+	number := 1
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+
+		marker := "- "
+		if n.Data == "ol" {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		content := strings.Join(c.blocks(li), "\n")
+		if todo {
+			if strings.Contains(attr(li, "style"), "--en-checked:true") {
+				content = enmlTodoDone + content
+			} else {
+				content = enmlTodo + content
+			}
+		}
+
+		items = append(items, prefixLines(content, marker, strings.Repeat(" ", len(marker))))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func (c *enmlConverter) table(n *html.Node) string {
+	rows := [][]string{}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+
+			if child.Data != "tr" {
+				walk(child)
+				continue
+			}
+
+			row := []string{}
+			for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+					text := strings.Join(c.blocks(cell), " ")
+					row = append(row, strings.ReplaceAll(strings.ReplaceAll(text, "\n", " "), "|", `\|`))
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	walk(n)
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	// the first row is used as the header, gfm tables need one
+	lines := []string{}
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", width))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (c *enmlConverter) inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if isENMLBlock(child) {
+			b.WriteString(" " + strings.Join(c.blocks(child), " ") + " ")
+			continue
+		}
+
+		b.WriteString(c.inline(child))
+	}
+
+	return b.String()
+}
+
+func (c *enmlConverter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return enmlSpecials.Replace(enmlSpaces.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.Data {
+	case "br":
+		return enmlBreak
+	case "b", "strong":
+		return wrapInline(c.inlineChildren(n), "**")
+	case "i", "em":
+		return wrapInline(c.inlineChildren(n), "*")
+	case "s", "strike", "del":
+		return wrapInline(c.inlineChildren(n), "~~")
+	case "code":
+		return wrapInline(textContent(n), "`")
+	case "a":
+		text := strings.TrimSpace(c.inlineChildren(n))
+		href := attr(n, "href")
+		if href == "" {
+			return text
+		}
+
+		if text == "" {
+			text = href
+		}
+
+		return fmt.Sprintf("[%s](<%s>)", text, href)
+	case "img":
+		src := attr(n, "src")
+		if src == "" || strings.HasPrefix(src, "data:") {
+			return ""
+		}
+
+		return fmt.Sprintf("![%s](<%s>)", enmlSpecials.Replace(attr(n, "alt")), src)
+	case "en-todo":
+		if attr(n, "checked") == "true" {
+			return enmlTodoDone
+		}
+
+		return enmlTodo
+	case "en-media":
+		return c.media(ENMLMedia{Hash: attr(n, "hash"), Type: attr(n, "type")})
+	case "en-crypt", "script", "style", "title":
+		return ""
+	}
+
+	return c.inlineChildren(n)
+}
+
+// wrap keeps the spaces around the text outside of the markers
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+
+	start := strings.Index(text, trimmed)
+	return text[:start] + marker + trimmed + marker + text[start+len(trimmed):]
+}
+
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+			continue
+		}
+
+		lines[i] = prefix + line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "br" {
+			b.WriteString("\n")
+			continue
+		}
+
+		b.WriteString(textContent(child))
+	}
+
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// ETag makes the etag of a version of the data from its updated_at,
+// postgres keeps microseconds so the finer part is left out
+func ETag(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 36) + `"`
+}
+
+// MatchETag tells if the etag is one of the etags of an If-Match or
+// If-None-Match header, weak etags compare as their strong value
+func MatchETag(header string, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckVersion returns a VersionConflictError when ifMatch is not the etag
+// of updatedAt, an empty ifMatch skips the check
+func CheckVersion(ifMatch string, updatedAt time.Time) error {
+	if ifMatch == "" {
+		return nil
+	}
+
+	etag := ETag(updatedAt)
+	if !MatchETag(ifMatch, etag) {
+		return &domain.VersionConflictError{Current: etag}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter is the yaml block at the top of an exported note
+type FrontMatter struct {
+	ShaID     string     `yaml:"sha_id,omitempty"`
+	CreatedAt *time.Time `yaml:"created_at,omitempty"`
+	UpdatedAt *time.Time `yaml:"updated_at,omitempty"`
+}
+
+// put the front matter between --- lines in front of the markdown
+func WithFrontMatter(fm FrontMatter, body string) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+
+	err := yaml.NewEncoder(&buf).Encode(fm)
+	if err != nil {
+		return "", err
+	}
+
+	buf.WriteString("---\n")
+	buf.WriteString(body)
+	return buf.String(), nil
+}
+
+// split the front matter from the markdown, content without
+// front matter is returned as it is with an empty front matter
+func ParseFrontMatter(content string) (FrontMatter, string, error) {
+	var fm FrontMatter
+
+	body, err := UnmarshalFrontMatter(content, &fm)
+	if err != nil {
+		return FrontMatter{}, "", err
+	}
+
+	return fm, body, nil
+}
+
+// decode the front matter into out and return the markdown after it,
+// out is left as it is when there is no front matter
+func UnmarshalFrontMatter(content string, out interface{}) (string, error) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return content, nil
+	}
+
+	rest := "\n" + normalized[len("---\n"):]
+
+	// the closing line may also be the last line of the file
+	var header, body string
+	if end := strings.Index(rest, "\n---\n"); end >= 0 {
+		header, body = rest[:end], rest[end+len("\n---\n"):]
+	} else if strings.HasSuffix(rest, "\n---") {
+		header = strings.TrimSuffix(rest, "\n---")
+	} else {
+		return content, nil
+	}
+
+	err := yaml.Unmarshal([]byte(header), out)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid front matter: %v", domain.ErrBadParamInput, err)
+	}
+
+	return body, nil
+}
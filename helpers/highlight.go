@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"html"
+	"strings"
+)
+
+// the markers ts_headline puts around the matching words, control
+// characters so they cannot be mistaken for markup written in a note
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// HighlightHTML escapes the text of a headline and turns its markers into
+// <mark></mark>, a marker without its pair is dropped
+func HighlightHTML(text string) string {
+	var b strings.Builder
+	open := false
+
+	for text != "" {
+		i := strings.IndexAny(text, HighlightStart+HighlightStop)
+		if i < 0 {
+			b.WriteString(html.EscapeString(text))
+			break
+		}
+
+		b.WriteString(html.EscapeString(text[:i]))
+
+		switch {
+		case text[i:i+1] == HighlightStart && !open:
+			b.WriteString("<mark>")
+			open = true
+		case text[i:i+1] == HighlightStop && open:
+			b.WriteString("</mark>")
+			open = false
+		}
+
+		text = text[i+1:]
+	}
+
+	if open {
+		b.WriteString("</mark>")
+	}
+
+	return b.String()
+}
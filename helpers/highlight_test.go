@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "no match", text: "plain text", want: "plain text"},
+		{name: "matches", text: "a \x02word\x03 and \x02another\x03", want: "a <mark>word</mark> and <mark>another</mark>"},
+		{name: "markup of the note is escaped", text: "<script>\x02alert\x03</script> & <mark>", want: "&lt;script&gt;<mark>alert</mark>&lt;/script&gt; &amp; &lt;mark&gt;"},
+		{name: "quotes are escaped", text: "\"\x02a\x03'", want: "&#34;<mark>a</mark>&#39;"},
+		{name: "unclosed marker", text: "\x02word", want: "<mark>word</mark>"},
+		{name: "stray markers", text: "\x03a\x02b\x02c\x03", want: "a<mark>bc</mark>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HighlightHTML(tt.text))
+		})
+	}
+}
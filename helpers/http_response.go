@@ -1,6 +1,35 @@
 package helpers
 
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
 type HttpResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
 }
+
+// map the error returned by usecase to http status code
+func GetStatusCode(err error) int {
+	var versionConflict *domain.VersionConflictError
+
+	switch {
+	case errors.As(err, &versionConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrBadParamInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+const (
+	// the sha_id columns are varchar(10)
+	idLength = 10
+	// 64 characters so every random byte maps to one character without bias
+	idAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	// number of ids tried before giving up on a collision
+	idMaxAttempts = 5
+)
+
+var ErrIDExhausted = errors.New("failed to generate a unique sha id")
+
+// IDGenerator makes the url safe ids stored in files.sha_id, folders.sha_id
+// and notes.file_sha_id
+type IDGenerator struct {
+	// Source is read for the random bytes, tests can give a deterministic one
+	Source io.Reader
+}
+
+// make a new id
+func (g IDGenerator) NewID() (string, error) {
+	b := make([]byte, idLength)
+	_, err := io.ReadFull(g.Source, b)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range b {
+		b[i] = idAlphabet[b[i]&63]
+	}
+
+	return string(b), nil
+}
+
+// call create with new ids until it does not fail with domain.ErrDuplicateID
+func (g IDGenerator) Generate(create func(id string) error) error {
+	for i := 0; i < idMaxAttempts; i++ {
+		id, err := g.NewID()
+		if err != nil {
+			return err
+		}
+
+		err = create(id)
+		if !errors.Is(err, domain.ErrDuplicateID) {
+			return err
+		}
+	}
+
+	return ErrIDExhausted
+}
+
+// make id generator, nil source means crypto/rand
+func NewIDGenerator(source io.Reader) IDGenerator {
+	if source == nil {
+		source = rand.Reader
+	}
+
+	return IDGenerator{
+		Source: source,
+	}
+}
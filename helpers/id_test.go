@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// source with the bytes 0, 1, 2 ... so the ids are known in advance
+func sequenceSource(n int) *bytes.Reader {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+
+	return bytes.NewReader(b)
+}
+
+func TestIDGeneratorNewID(t *testing.T) {
+	g := NewIDGenerator(sequenceSource(2 * idLength))
+
+	id, err := g.NewID()
+	require.NoError(t, err)
+	assert.Equal(t, "ABCDEFGHIJ", id)
+
+	id, err = g.NewID()
+	require.NoError(t, err)
+	assert.Equal(t, "KLMNOPQRST", id)
+
+	// the source has run out
+	_, err = g.NewID()
+	assert.Error(t, err)
+}
+
+func TestIDGeneratorGenerate(t *testing.T) {
+	errCreate := errors.New("create failed")
+
+	tests := []struct {
+		name       string
+		duplicates int
+		err        error
+		wantErr    error
+		wantIDs    []string
+	}{
+		{
+			name:    "first id is free",
+			wantIDs: []string{"ABCDEFGHIJ"},
+		},
+		{
+			name:       "retried after duplicates",
+			duplicates: 2,
+			wantIDs:    []string{"ABCDEFGHIJ", "KLMNOPQRST", "UVWXYZabcd"},
+		},
+		{
+			name:       "exhausted after the last attempt",
+			duplicates: idMaxAttempts,
+			wantErr:    ErrIDExhausted,
+			wantIDs:    []string{"ABCDEFGHIJ", "KLMNOPQRST", "UVWXYZabcd", "efghijklmn", "opqrstuvwx"},
+		},
+		{
+			name:    "other error is returned at once",
+			err:     errCreate,
+			wantErr: errCreate,
+			wantIDs: []string{"ABCDEFGHIJ"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewIDGenerator(sequenceSource((idMaxAttempts + 1) * idLength))
+
+			ids := []string{}
+			err := g.Generate(func(id string) error {
+				ids = append(ids, id)
+				if len(ids) <= tt.duplicates {
+					return fmt.Errorf("%w: %s", domain.ErrDuplicateID, id)
+				}
+
+				return tt.err
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
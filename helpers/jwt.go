@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"context"
+	"errors"
 	"os"
 	"time"
 
@@ -39,3 +41,13 @@ func ValidateJwt(tokenString string) (*domain.TokenClaims, error) {
 		return nil, err
 	}
 }
+
+// get the token claims that middleware.MyMiddleware put into the context
+func GetCredentials(ctx context.Context) (*domain.TokenClaims, error) {
+	claims, ok := ctx.Value("credentials").(*domain.TokenClaims)
+	if !ok || claims == nil {
+		return nil, errors.New("credentials not found")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// commonmark with the gfm tables and task lists, raw html is kept
+// here and cleaned by the sanitizer afterwards
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.Table, extension.TaskList),
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var markdownPolicy = newMarkdownPolicy()
+
+// user generated content policy plus the disabled checkboxes of task lists
+func newMarkdownPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowAttrs("style").OnElements("th", "td")
+	p.AllowStyles("text-align").MatchingEnum("left", "center", "right").OnElements("th", "td")
+	return p
+}
+
+// render markdown to html without scripts, event handlers or unsafe urls
+func RenderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	err := markdown.Convert([]byte(source), &buf)
+	if err != nil {
+		return "", err
+	}
+
+	return markdownPolicy.Sanitize(buf.String()), nil
+}
+
+// codeFence follows the fenced code blocks of markdown read line by line. A
+// block opens with three or more ` or ~ and closes with a run of the same
+// character at least as long, or at the end of the markdown
+type codeFence struct {
+	// marker is the run that opened the block, empty outside of code
+	marker string
+}
+
+// inCode tells if the line is in a fenced code block, fences included
+func (f *codeFence) inCode(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+
+	// a line indented by four spaces is not a fence
+	marker := ""
+	if len(line)-len(trimmed) <= 3 {
+		marker = fenceMarker(trimmed)
+	}
+
+	if f.marker == "" {
+		f.marker = marker
+		return marker != ""
+	}
+
+	rest := strings.TrimSpace(trimmed[len(marker):])
+	if marker != "" && marker[0] == f.marker[0] && len(marker) >= len(f.marker) && rest == "" {
+		f.marker = ""
+	}
+
+	return true
+}
+
+// fenceMarker returns the run of ` or ~ starting the line when it is a fence
+func fenceMarker(line string) string {
+	if line == "" || (line[0] != '`' && line[0] != '~') {
+		return ""
+	}
+
+	n := 0
+	for n < len(line) && line[n] == line[0] {
+		n++
+	}
+
+	// the info string of a ` fence cannot have a `
+	if n < 3 || (line[0] == '`' && strings.Contains(line[n:], "`")) {
+		return ""
+	}
+
+	return line[:n]
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// build the path of a file from the path of its folder,
+// an empty parent means the file is in the root
+func JoinPath(parent, name string) string {
+	return path.Join("/", parent, name)
+}
+
+// check the name of a file before it become part of a path
+func ValidateFileName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name cannot be empty", domain.ErrBadParamInput)
+	}
+
+	if strings.Contains(name, "/") || name == "." || name == ".." {
+		return fmt.Errorf("%w: name cannot contain / or be . or ..", domain.ErrBadParamInput)
+	}
+
+	if len(name) > 255 {
+		return fmt.Errorf("%w: name cannot be longer than 255 characters", domain.ErrBadParamInput)
+	}
+
+	return nil
+}
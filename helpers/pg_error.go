@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// check whether err is a unique violation of the given constraint or index
+func IsUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
@@ -0,0 +1,37 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"strings"
+)
+
+// SignToken appends to the token its hmac signed with JWT_SECRET, so a link
+// can only be opened with a token this server gave out
+func SignToken(token string) string {
+	return token + "." + tokenSignature(token)
+}
+
+// VerifySignedToken returns the token of a value made by SignToken, ok is
+// false when the signature does not match
+func VerifySignedToken(signed string) (token string, ok bool) {
+	token, signature, found := strings.Cut(signed, ".")
+	if !found || token == "" {
+		return "", false
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(tokenSignature(token))) {
+		return "", false
+	}
+
+	return token, true
+}
+
+func tokenSignature(token string) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("JWT_SECRET")))
+	mac.Write([]byte(token))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
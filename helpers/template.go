@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"regexp"
+)
+
+// templatePlaceholderPattern matches {{name}}, spaces around the name are allowed
+var templatePlaceholderPattern = regexp.MustCompile(`\{\{\s*([\w.\-]+)\s*\}\}`)
+
+// TemplatePlaceholders returns the names of the placeholders of the template,
+// each name once in the order it is first used
+func TemplatePlaceholders(content string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, match := range templatePlaceholderPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// ExpandTemplate writes the values in place of the placeholders of the
+// template. The placeholders without a value are kept and their names returned
+func ExpandTemplate(content string, values map[string]string) (string, []string) {
+	missing := []string{}
+	seen := map[string]bool{}
+
+	expanded := templatePlaceholderPattern.ReplaceAllStringFunc(content, func(placeholder string) string {
+		name := templatePlaceholderPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+
+		if !seen[name] {
+			seen[name] = true
+			missing = append(missing, name)
+		}
+
+		return placeholder
+	})
+
+	return expanded, missing
+}
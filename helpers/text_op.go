@@ -0,0 +1,146 @@
+package helpers
+
+import (
+	"fmt"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// ApplyTextOps applies the ops one after the other on the text
+func ApplyTextOps(text string, ops []domain.TextOp) (string, error) {
+	runes := []rune(text)
+
+	for _, op := range ops {
+		switch op.Type {
+		case domain.TextOpInsert:
+			if op.Pos < 0 || op.Pos > len(runes) {
+				return "", fmt.Errorf("%w: insert position %d is outside the text", domain.ErrBadParamInput, op.Pos)
+			}
+
+			insert := []rune(op.Text)
+			next := make([]rune, 0, len(runes)+len(insert))
+			next = append(next, runes[:op.Pos]...)
+			next = append(next, insert...)
+			runes = append(next, runes[op.Pos:]...)
+		case domain.TextOpDelete:
+			if op.Pos < 0 || op.Length < 0 || op.Pos+op.Length > len(runes) {
+				return "", fmt.Errorf("%w: delete range %d+%d is outside the text", domain.ErrBadParamInput, op.Pos, op.Length)
+			}
+
+			runes = append(runes[:op.Pos], runes[op.Pos+op.Length:]...)
+		default:
+			return "", fmt.Errorf("%w: unknown op type %q", domain.ErrBadParamInput, op.Type)
+		}
+	}
+
+	return string(runes), nil
+}
+
+// DiffTextOps returns the ops changing text into changed, a delete of the
+// part between the common start and end followed by an insert of the new part
+func DiffTextOps(text string, changed string) []domain.TextOp {
+	a, b := []rune(text), []rune(changed)
+
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+
+	end := 0
+	for end < len(a)-start && end < len(b)-start && a[len(a)-1-end] == b[len(b)-1-end] {
+		end++
+	}
+
+	ops := []domain.TextOp{}
+	if length := len(a) - start - end; length > 0 {
+		ops = append(ops, domain.TextOp{Type: domain.TextOpDelete, Pos: start, Length: length})
+	}
+
+	if insert := b[start : len(b)-end]; len(insert) > 0 {
+		ops = append(ops, domain.TextOp{Type: domain.TextOpInsert, Pos: start, Text: string(insert)})
+	}
+
+	return ops
+}
+
+// TransformTextOps rewrites ops made on the same text as against so they
+// can be applied after against. When both insert at the same position the
+// text of against comes first
+func TransformTextOps(ops, against []domain.TextOp) []domain.TextOp {
+	transformed, _ := transformTextOps(ops, against, false)
+	return transformed
+}
+
+// transformTextOps transforms the two concurrent sequences against each
+// other, a is transformed to apply after b and b to apply after a
+func transformTextOps(a, b []domain.TextOp, aFirst bool) ([]domain.TextOp, []domain.TextOp) {
+	if len(a) == 0 || len(b) == 0 {
+		return a, b
+	}
+
+	if len(a) > 1 {
+		head, b1 := transformTextOps(a[:1], b, aFirst)
+		tail, b2 := transformTextOps(a[1:], b1, aFirst)
+		return append(head, tail...), b2
+	}
+
+	if len(b) > 1 {
+		a1, head := transformTextOps(a, b[:1], aFirst)
+		a2, tail := transformTextOps(a1, b[1:], aFirst)
+		return a2, append(head, tail...)
+	}
+
+	return transformTextOp(a[0], b[0], aFirst), transformTextOp(b[0], a[0], !aFirst)
+}
+
+// transformTextOp rewrites op to apply after against, first tells which
+// insert goes first when both insert at the same position. A delete can be
+// split in two by an insert in its range or vanish under another delete
+func transformTextOp(op, against domain.TextOp, first bool) []domain.TextOp {
+	insertLength := len([]rune(against.Text))
+
+	switch {
+	case op.Type == domain.TextOpInsert && against.Type == domain.TextOpInsert:
+		if against.Pos < op.Pos || (against.Pos == op.Pos && !first) {
+			op.Pos += insertLength
+		}
+	case op.Type == domain.TextOpInsert && against.Type == domain.TextOpDelete:
+		if op.Pos >= against.Pos+against.Length {
+			op.Pos -= against.Length
+		} else if op.Pos > against.Pos {
+			// the text around the insert is gone, insert where it was
+			op.Pos = against.Pos
+		}
+	case op.Type == domain.TextOpDelete && against.Type == domain.TextOpInsert:
+		if against.Pos <= op.Pos {
+			op.Pos += insertLength
+		} else if against.Pos < op.Pos+op.Length {
+			// keep the inserted text, delete around it
+			before := against.Pos - op.Pos
+			return []domain.TextOp{
+				{Type: domain.TextOpDelete, Pos: op.Pos, Length: before},
+				{Type: domain.TextOpDelete, Pos: op.Pos + insertLength, Length: op.Length - before},
+			}
+		}
+	case op.Type == domain.TextOpDelete && against.Type == domain.TextOpDelete:
+		end := op.Pos + op.Length
+		againstEnd := against.Pos + against.Length
+
+		// only delete what against has not deleted yet
+		overlap := min(end, againstEnd) - max(op.Pos, against.Pos)
+		if overlap > 0 {
+			op.Length -= overlap
+		}
+
+		if op.Length == 0 {
+			return nil
+		}
+
+		deletedBefore := min(op.Pos, againstEnd) - against.Pos
+		if deletedBefore > 0 {
+			op.Pos -= deletedBefore
+		}
+	}
+
+	return []domain.TextOp{op}
+}
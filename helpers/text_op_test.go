@@ -0,0 +1,198 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+func insert(pos int, text string) domain.TextOp {
+	return domain.TextOp{Type: domain.TextOpInsert, Pos: pos, Text: text}
+}
+
+func remove(pos int, length int) domain.TextOp {
+	return domain.TextOp{Type: domain.TextOpDelete, Pos: pos, Length: length}
+}
+
+func TestApplyTextOps(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		ops     []domain.TextOp
+		want    string
+		wantErr bool
+	}{
+		{name: "insert in the middle", text: "abcdef", ops: []domain.TextOp{insert(3, "X")}, want: "abcXdef"},
+		{name: "insert at the end", text: "abc", ops: []domain.TextOp{insert(3, "X")}, want: "abcX"},
+		{name: "delete", text: "abcdef", ops: []domain.TextOp{remove(1, 3)}, want: "aef"},
+		{name: "ops one after the other", text: "abcdef", ops: []domain.TextOp{insert(0, "X"), remove(1, 2)}, want: "Xcdef"},
+		{name: "positions count code points", text: "héllo", ops: []domain.TextOp{remove(1, 1), insert(1, "e")}, want: "hello"},
+		{name: "insert outside the text", text: "abc", ops: []domain.TextOp{insert(4, "X")}, wantErr: true},
+		{name: "delete outside the text", text: "abc", ops: []domain.TextOp{remove(2, 2)}, wantErr: true},
+		{name: "negative length", text: "abc", ops: []domain.TextOp{remove(2, -1)}, wantErr: true},
+		{name: "unknown op", text: "abc", ops: []domain.TextOp{{Type: "replace"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyTextOps(tt.text, tt.ops)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrBadParamInput)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTransformTextOps(t *testing.T) {
+	// a and b are made on text at the same time, b is applied first
+	tests := []struct {
+		name string
+		text string
+		a    []domain.TextOp
+		b    []domain.TextOp
+		want string
+	}{
+		{
+			name: "inserts at the same offset",
+			text: "abcdef",
+			a:    []domain.TextOp{insert(2, "X")},
+			b:    []domain.TextOp{insert(2, "Y")},
+			want: "abYXcdef",
+		},
+		{
+			name: "inserts at different offsets",
+			text: "abcdef",
+			a:    []domain.TextOp{insert(4, "X")},
+			b:    []domain.TextOp{insert(1, "Y")},
+			want: "aYbcdXef",
+		},
+		{
+			name: "delete and insert at the same offset",
+			text: "abcdef",
+			a:    []domain.TextOp{remove(2, 2)},
+			b:    []domain.TextOp{insert(2, "X")},
+			want: "abXef",
+		},
+		{
+			name: "insert and delete at the same offset",
+			text: "abcdef",
+			a:    []domain.TextOp{insert(2, "X")},
+			b:    []domain.TextOp{remove(2, 2)},
+			want: "abXef",
+		},
+		{
+			name: "insert at the end of a delete",
+			text: "abcdef",
+			a:    []domain.TextOp{insert(4, "X")},
+			b:    []domain.TextOp{remove(2, 2)},
+			want: "abXef",
+		},
+		{
+			name: "insert inside a delete",
+			text: "abcdef",
+			a:    []domain.TextOp{insert(3, "X")},
+			b:    []domain.TextOp{remove(2, 3)},
+			want: "abXf",
+		},
+		{
+			name: "delete around an insert",
+			text: "abcdef",
+			a:    []domain.TextOp{remove(1, 4)},
+			b:    []domain.TextOp{insert(3, "X")},
+			want: "aXf",
+		},
+		{
+			name: "same delete",
+			text: "abcdef",
+			a:    []domain.TextOp{remove(2, 2)},
+			b:    []domain.TextOp{remove(2, 2)},
+			want: "abef",
+		},
+		{
+			name: "overlapping deletes",
+			text: "abcdef",
+			a:    []domain.TextOp{remove(1, 3)},
+			b:    []domain.TextOp{remove(2, 3)},
+			want: "af",
+		},
+		{
+			name: "delete inside a delete",
+			text: "abcdef",
+			a:    []domain.TextOp{remove(2, 1)},
+			b:    []domain.TextOp{remove(1, 4)},
+			want: "af",
+		},
+		{
+			name: "sequences of ops",
+			text: "abcdef",
+			a:    []domain.TextOp{insert(0, "X"), remove(3, 1)},
+			b:    []domain.TextOp{remove(0, 1), insert(5, "Y")},
+			want: "XbdefY",
+		},
+		{
+			name: "code points",
+			text: "héllo",
+			a:    []domain.TextOp{insert(2, "ü")},
+			b:    []domain.TextOp{remove(0, 1)},
+			want: "éüllo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the server applies a after b, as SubmitOps does with a late editor
+			afterB, err := ApplyTextOps(tt.text, tt.b)
+			require.NoError(t, err)
+
+			got, err := ApplyTextOps(afterB, TransformTextOps(tt.a, tt.b))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// the editor who made a applies b after it and ends with the same text
+			_, bAfterA := transformTextOps(tt.a, tt.b, false)
+
+			afterA, err := ApplyTextOps(tt.text, tt.a)
+			require.NoError(t, err)
+
+			got, err = ApplyTextOps(afterA, bAfterA)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiffTextOps(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		changed string
+		want    []domain.TextOp
+	}{
+		{name: "same text", text: "abc", changed: "abc", want: []domain.TextOp{}},
+		{name: "insert", text: "abef", changed: "abcdef", want: []domain.TextOp{insert(2, "cd")}},
+		{name: "delete", text: "abcdef", changed: "abef", want: []domain.TextOp{remove(2, 2)}},
+		{name: "replace", text: "abcdef", changed: "abXYZf", want: []domain.TextOp{remove(2, 3), insert(2, "XYZ")}},
+		{name: "repeated characters", text: "aaa", changed: "aaaa", want: []domain.TextOp{insert(3, "a")}},
+		{name: "from empty", text: "", changed: "abc", want: []domain.TextOp{insert(0, "abc")}},
+		{name: "to empty", text: "abc", changed: "", want: []domain.TextOp{remove(0, 3)}},
+		{name: "code points", text: "héllo", changed: "hüllo", want: []domain.TextOp{remove(1, 1), insert(1, "ü")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := DiffTextOps(tt.text, tt.changed)
+			assert.Equal(t, tt.want, ops)
+
+			got, err := ApplyTextOps(tt.text, ops)
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, got)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package helpers
+
+import (
+	"context"
+	"database/sql"
+)
+
+// run fn inside a transaction, commit when fn succeed and rollback otherwise
+func RunInTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,145 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// biggest number of characters kept around a link in its snippet
+const wikiLinkSnippetLength = 160
+
+// WikiLinkNoteExtension is written by some editors after the name of a note in a link
+const WikiLinkNoteExtension = ".md"
+
+// WikiLinkPattern matches [[target#heading|alias]] and the ![[embed]] form
+var WikiLinkPattern = regexp.MustCompile(`(!?)\[\[([^\[\]\n]+)\]\]`)
+
+// WikiLink is a link of a note to another note by its name, path or sha id
+type WikiLink struct {
+	Target  string
+	Heading string
+	Alias   string
+	Embed   bool
+	// Line is the number of the line of the link, from 1
+	Line int
+	// Start and End are the byte offsets of the whole link in the content
+	Start int
+	End   int
+	// Snippet is the line of the link, cut around it when the line is long
+	Snippet string
+}
+
+// ParseWikiLinks finds the wikilinks of the markdown, the ones in fenced
+// code and the links to a heading of the same note are left out
+func ParseWikiLinks(content string) []WikiLink {
+	links := []WikiLink{}
+	fence := codeFence{}
+	offset := 0
+
+	for i, line := range strings.Split(content, "\n") {
+		lineStart := offset
+		offset += len(line) + 1
+
+		if fence.inCode(line) {
+			continue
+		}
+
+		for _, match := range WikiLinkPattern.FindAllStringSubmatchIndex(line, -1) {
+			inner := line[match[4]:match[5]]
+
+			target, alias, _ := strings.Cut(inner, "|")
+			target, heading, _ := strings.Cut(target, "#")
+			target = strings.TrimSpace(target)
+			if target == "" {
+				continue
+			}
+
+			links = append(links, WikiLink{
+				Target:  target,
+				Heading: strings.TrimSpace(heading),
+				Alias:   strings.TrimSpace(alias),
+				Embed:   match[3] > match[2],
+				Line:    i + 1,
+				Start:   lineStart + match[0],
+				End:     lineStart + match[1],
+				Snippet: snippetAround(line, match[0], match[1]),
+			})
+		}
+	}
+
+	return links
+}
+
+// String writes the link back as markdown
+func (l WikiLink) String() string {
+	var b strings.Builder
+	if l.Embed {
+		b.WriteString("!")
+	}
+
+	b.WriteString("[[")
+	b.WriteString(l.Target)
+	if l.Heading != "" {
+		b.WriteString("#")
+		b.WriteString(l.Heading)
+	}
+
+	if l.Alias != "" {
+		b.WriteString("|")
+		b.WriteString(l.Alias)
+	}
+
+	b.WriteString("]]")
+	return b.String()
+}
+
+// ReplaceWikiLinks writes the text returned by replace in place of the links
+// of the content, a link is kept as it is when replace returns false
+func ReplaceWikiLinks(content string, replace func(link WikiLink) (string, bool)) string {
+	var b strings.Builder
+	last := 0
+
+	for _, link := range ParseWikiLinks(content) {
+		text, ok := replace(link)
+		if !ok {
+			continue
+		}
+
+		b.WriteString(content[last:link.Start])
+		b.WriteString(text)
+		last = link.End
+	}
+
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// snippetAround keeps the line, or the part of it around the link between start and end
+func snippetAround(line string, start, end int) string {
+	if utf8.RuneCountInString(line) <= wikiLinkSnippetLength {
+		return strings.TrimSpace(line)
+	}
+
+	// the room left is shared between both sides of the link
+	room := (wikiLinkSnippetLength - utf8.RuneCountInString(line[start:end])) / 2
+	if room < 0 {
+		room = 0
+	}
+
+	before := []rune(line[:start])
+	after := []rune(line[end:])
+
+	prefix, suffix := "", ""
+	if len(before) > room {
+		before = before[len(before)-room:]
+		prefix = "…"
+	}
+
+	if len(after) > room {
+		after = after[:room]
+		suffix = "…"
+	}
+
+	return strings.TrimSpace(prefix + string(before) + line[start:end] + string(after) + suffix)
+}
@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWikiLinksCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "backtick fence",
+			content: "[[a]]\n```go\n[[b]]\n```\n[[c]]",
+			want:    []string{"a", "c"},
+		},
+		{
+			name:    "tilde fence",
+			content: "~~~\n[[b]]\n~~~\n[[c]]",
+			want:    []string{"c"},
+		},
+		{
+			name:    "fence closed by the same character only",
+			content: "~~~\n[[b]]\n```\n[[c]]\n~~~\n[[d]]",
+			want:    []string{"d"},
+		},
+		{
+			name:    "shorter run does not close the fence",
+			content: "````\n[[b]]\n```\n[[c]]\n````\n[[d]]",
+			want:    []string{"d"},
+		},
+		{
+			name:    "closing fence has nothing after it",
+			content: "```\n[[b]]\n``` x\n[[c]]\n```\n[[d]]",
+			want:    []string{"d"},
+		},
+		{
+			name:    "indented by four spaces is not a fence",
+			content: "    ```\n[[b]]",
+			want:    []string{"b"},
+		},
+		{
+			name:    "inline code is not a fence",
+			content: "``[[b]]``",
+			want:    []string{"b"},
+		},
+		{
+			name:    "unclosed fence runs to the end",
+			content: "[[a]]\n```\n[[b]]",
+			want:    []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := []string{}
+			for _, link := range ParseWikiLinks(tt.content) {
+				targets = append(targets, link.Target)
+			}
+
+			assert.Equal(t, tt.want, targets)
+		})
+	}
+}
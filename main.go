@@ -1,15 +1,50 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	archive_handler "github.com/ihsanbudiman/notes_app/archive/delivery/http"
+	archive_ucase "github.com/ihsanbudiman/notes_app/archive/usecase"
+	attachment_handler "github.com/ihsanbudiman/notes_app/attachment/delivery/http"
+	attachment_repo_local "github.com/ihsanbudiman/notes_app/attachment/repository/local"
+	attachment_repo_pg "github.com/ihsanbudiman/notes_app/attachment/repository/postgres"
+	attachment_repo_s3 "github.com/ihsanbudiman/notes_app/attachment/repository/s3"
+	attachment_ucase "github.com/ihsanbudiman/notes_app/attachment/usecase"
+	"github.com/ihsanbudiman/notes_app/domain"
+	file_handler "github.com/ihsanbudiman/notes_app/file/delivery/http"
+	file_repo_pg "github.com/ihsanbudiman/notes_app/file/repository/postgres"
+	file_ucase "github.com/ihsanbudiman/notes_app/file/usecase"
+	folder_handler "github.com/ihsanbudiman/notes_app/folder/delivery/http"
+	folder_repo_pg "github.com/ihsanbudiman/notes_app/folder/repository/postgres"
+	folder_ucase "github.com/ihsanbudiman/notes_app/folder/usecase"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	note_handler "github.com/ihsanbudiman/notes_app/note/delivery/http"
+	note_repo_pg "github.com/ihsanbudiman/notes_app/note/repository/postgres"
+	note_ucase "github.com/ihsanbudiman/notes_app/note/usecase"
+	reminder_handler "github.com/ihsanbudiman/notes_app/reminder/delivery/http"
+	reminder_repo_email "github.com/ihsanbudiman/notes_app/reminder/repository/email"
+	reminder_repo_pg "github.com/ihsanbudiman/notes_app/reminder/repository/postgres"
+	reminder_repo_webhook "github.com/ihsanbudiman/notes_app/reminder/repository/webhook"
+	reminder_ucase "github.com/ihsanbudiman/notes_app/reminder/usecase"
+	share_handler "github.com/ihsanbudiman/notes_app/share/delivery/http"
+	share_repo_pg "github.com/ihsanbudiman/notes_app/share/repository/postgres"
+	share_ucase "github.com/ihsanbudiman/notes_app/share/usecase"
 	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	tag_handler "github.com/ihsanbudiman/notes_app/tag/delivery/http"
+	tag_repo_pg "github.com/ihsanbudiman/notes_app/tag/repository/postgres"
+	tag_ucase "github.com/ihsanbudiman/notes_app/tag/usecase"
+	template_handler "github.com/ihsanbudiman/notes_app/template/delivery/http"
+	template_repo_pg "github.com/ihsanbudiman/notes_app/template/repository/postgres"
+	template_ucase "github.com/ihsanbudiman/notes_app/template/usecase"
 	user_handler "github.com/ihsanbudiman/notes_app/user/delivery/http"
 	user_repo_pg "github.com/ihsanbudiman/notes_app/user/repository/postgres"
 	user_ucase "github.com/ihsanbudiman/notes_app/user/usecase"
@@ -52,6 +87,189 @@ func main() {
 	userUseCase := user_ucase.NewUserUseCase(userRepo)
 	user_handler.NewUserHandler(r, userUseCase)
 
+	// every sha_id of files, folders and notes is made by this generator
+	idGenerator := helpers.NewIDGenerator(nil)
+
+	fileRepo := file_repo_pg.NewPostgresFileRepo(db, sqlc)
+	fileUseCase := file_ucase.NewFileUseCase(fileRepo, userUseCase)
+	file_handler.NewFileHandler(r, fileUseCase)
+
+	trashRepo := file_repo_pg.NewPostgresTrashRepo(db, sqlc)
+	trashUseCase := file_ucase.NewTrashUseCase(trashRepo, fileRepo)
+	file_handler.NewTrashHandler(r, trashUseCase)
+
+	shareRepo := share_repo_pg.NewPostgresShareRepo(db, sqlc)
+	shareUseCase := share_ucase.NewShareUseCase(shareRepo, fileRepo, userRepo)
+	share_handler.NewShareHandler(r, shareUseCase)
+
+	noteRepo := note_repo_pg.NewPostgresNoteRepo(db, sqlc)
+	noteUseCase := note_ucase.NewNoteUseCase(noteRepo, fileRepo, fileUseCase, userUseCase, shareUseCase, idGenerator)
+	noteRevisionRepo := note_repo_pg.NewPostgresNoteRevisionRepo(sqlc)
+	noteRevisionUseCase := note_ucase.NewNoteRevisionUseCase(noteRevisionRepo, noteUseCase)
+	noteRenderUseCase := note_ucase.NewNoteRenderUseCase(noteUseCase)
+	noteLinkRepo := note_repo_pg.NewPostgresNoteLinkRepo(sqlc)
+	noteLinkUseCase := note_ucase.NewNoteLinkUseCase(noteLinkRepo, noteUseCase, shareUseCase)
+	noteChecklistUseCase := note_ucase.NewNoteChecklistUseCase(noteRepo, noteUseCase, userUseCase, shareUseCase)
+	note_handler.NewNoteHandler(r, noteUseCase, noteRevisionUseCase, noteRenderUseCase, noteLinkUseCase, noteChecklistUseCase)
+	noteCollabUseCase := note_ucase.NewNoteCollabUseCase(noteRepo, noteUseCase, userUseCase, shareUseCase)
+	note_handler.NewNoteCollabHandler(r, noteCollabUseCase)
+
+	folderRepo := folder_repo_pg.NewPostgresFolderRepo(db, sqlc)
+	folderUseCase := folder_ucase.NewFolderUseCase(folderRepo, fileUseCase, shareUseCase, idGenerator)
+	folder_handler.NewFolderHandler(r, folderUseCase)
+
+	tagRepo := tag_repo_pg.NewPostgresTagRepo(db, sqlc)
+	tagUseCase := tag_ucase.NewTagUseCase(tagRepo, noteUseCase)
+	tag_handler.NewTagHandler(r, tagUseCase)
+
+	templateRepo := template_repo_pg.NewPostgresTemplateRepo(sqlc)
+	templateUseCase := template_ucase.NewTemplateUseCase(templateRepo, noteUseCase, folderUseCase, userUseCase, shareUseCase)
+	template_handler.NewTemplateHandler(r, templateUseCase)
+
+	publicLinkRepo := share_repo_pg.NewPostgresPublicLinkRepo(sqlc)
+	publicLinkUseCase := share_ucase.NewPublicLinkUseCase(publicLinkRepo, fileRepo, shareUseCase, folderUseCase, noteUseCase, noteRenderUseCase, idGenerator)
+	share_handler.NewPublicLinkHandler(r, publicLinkUseCase)
+
+	attachmentRepo := attachment_repo_pg.NewPostgresAttachmentRepo(db, sqlc)
+	attachmentUseCase := attachment_ucase.NewAttachmentUseCase(attachmentRepo, newBlobStore(), shareUseCase, idGenerator, attachmentQuota())
+	attachment_handler.NewAttachmentHandler(r, attachmentUseCase)
+
+	reminderRepo := reminder_repo_pg.NewPostgresReminderRepo(db, sqlc)
+	notificationRepo := reminder_repo_pg.NewPostgresNotificationRepo(sqlc)
+	reminderUseCase := reminder_ucase.NewReminderUseCase(reminderRepo, notificationRepo, newNotifiers(sqlc), userUseCase, shareUseCase)
+	reminder_handler.NewReminderHandler(r, reminderUseCase)
+
+	archiveUseCase := archive_ucase.NewArchiveUseCase(fileRepo, noteRepo, folderUseCase, noteUseCase, tagUseCase, attachmentUseCase)
+	archive_handler.NewArchiveHandler(r, archiveUseCase)
+
+	// purge the trash in the background
+	go purgeTrash(trashUseCase, attachmentUseCase, trashRetentionDays())
+
+	// save the notes being edited together
+	go saveCollabSnapshots(noteCollabUseCase, 30*time.Second)
+
+	// send the due reminders, every replica runs its own scheduler
+	go sendReminders(reminderUseCase, 30*time.Second)
+
 	http.ListenAndServe(":3000", r)
 
 }
+
+// number of days a deleted file stays in the trash
+func trashRetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		return domain.DefaultTrashRetentionDays
+	}
+
+	return days
+}
+
+// total size in bytes of the attachments of a user
+func attachmentQuota() int64 {
+	mb, err := strconv.ParseInt(os.Getenv("ATTACHMENT_QUOTA_MB"), 10, 64)
+	if err != nil || mb <= 0 {
+		return domain.DefaultAttachmentQuota
+	}
+
+	return mb << 20
+}
+
+// the attachments are kept on the local disk unless BLOB_STORE is s3
+func newBlobStore() domain.BlobStore {
+	if os.Getenv("BLOB_STORE") == "s3" {
+		return attachment_repo_s3.NewS3BlobStore(attachment_repo_s3.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	}
+
+	dir := os.Getenv("BLOB_DIR")
+	if dir == "" {
+		dir = "data/attachments"
+	}
+
+	return attachment_repo_local.NewLocalBlobStore(dir)
+}
+
+// the reminders are always shown in the app, they are also sent by mail
+// when SMTP_HOST is set and to REMINDER_WEBHOOK_URL when set
+func newNotifiers(sqlc sqlcpg.Querier) []domain.Notifier {
+	notifiers := []domain.Notifier{reminder_repo_pg.NewInAppNotifier(sqlc)}
+
+	if os.Getenv("SMTP_HOST") != "" {
+		notifiers = append(notifiers, reminder_repo_email.NewEmailNotifier(reminder_repo_email.EmailConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}))
+	}
+
+	if os.Getenv("REMINDER_WEBHOOK_URL") != "" {
+		notifiers = append(notifiers, reminder_repo_webhook.NewWebhookNotifier(reminder_repo_webhook.WebhookConfig{
+			URL:    os.Getenv("REMINDER_WEBHOOK_URL"),
+			Secret: os.Getenv("REMINDER_WEBHOOK_SECRET"),
+		}))
+	}
+
+	return notifiers
+}
+
+// purgeTrash deletes for good the files trashed more than days ago with
+// their attachments, once at start and then every hour
+func purgeTrash(u domain.TrashUsecase, au domain.AttachmentUsecase, days int) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		count, err := u.PurgeTrash(context.Background(), time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			log.Printf("failed to purge the trash: %v", err)
+		} else if count > 0 {
+			log.Printf("purged %d files from the trash", count)
+		}
+
+		// then the attachments of the purged notes, also the ones of an emptied trash
+		count, err = au.PurgeOrphanAttachments(context.Background())
+		if err != nil {
+			log.Printf("failed to purge the attachments: %v", err)
+		} else if count > 0 {
+			log.Printf("purged %d attachments", count)
+		}
+
+		<-ticker.C
+	}
+}
+
+// saveCollabSnapshots saves the notes changed in the editing sessions every interval
+func saveCollabSnapshots(u domain.NoteCollabUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := u.SaveSnapshots(context.Background())
+		if err != nil {
+			log.Printf("failed to save the editing sessions: %v", err)
+		}
+	}
+}
+
+// sendReminders sends the due reminders every interval
+func sendReminders(u domain.ReminderUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := u.SendDueReminders(context.Background(), time.Now())
+		if err != nil {
+			log.Printf("failed to send the reminders: %v", err)
+		} else if count > 0 {
+			log.Printf("sent %d reminders", count)
+		}
+	}
+}
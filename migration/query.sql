@@ -30,3 +30,637 @@ WHERE phone_number = $1 LIMIT 1;
 SELECT * FROM users
 WHERE username = $1 OR( email = $2 and email IS NOT NULL) OR (phone_number = $3 and phone_number IS NOT NULL) LIMIT 1;
 
+-- name: CreateFile :one
+INSERT INTO files (folder_sha_id, name, type, sha_id, path, user_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: FindFile :one
+SELECT * FROM files
+WHERE sha_id = $1 AND user_id = $2 AND deleted_at IS NULL LIMIT 1;
+
+-- name: FindFileByPath :one
+SELECT * FROM files
+WHERE user_id = $1 AND path = $2 AND deleted_at IS NULL LIMIT 1;
+
+-- name: DeleteFile :exec
+DELETE FROM files
+WHERE sha_id = $1 AND user_id = $2;
+
+-- name: CreateNote :one
+INSERT INTO notes (file_sha_id, note, created_at, updated_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: FindNote :one
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.sha_id = $1 AND files.user_id = $2 AND files.type = 'note' AND files.deleted_at IS NULL LIMIT 1;
+
+-- name: FindNoteForUpdate :one
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.sha_id = $1 AND files.user_id = $2 AND files.type = 'note' AND files.deleted_at IS NULL LIMIT 1
+FOR UPDATE OF notes;
+
+-- name: GetNotes :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = $1 AND files.type = 'note' AND files.deleted_at IS NULL
+ORDER BY files.path;
+
+-- name: GetNotesByFolder :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = $1 AND files.folder_sha_id = $2 AND files.type = 'note' AND files.deleted_at IS NULL
+ORDER BY files.name;
+
+-- name: GetNotesWithOpenTasks :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = $1 AND files.type = 'note' AND files.deleted_at IS NULL AND notes.note LIKE '%[ ]%'
+ORDER BY files.path;
+
+-- name: UpdateNote :one
+UPDATE notes SET note = $2, updated_at = $3
+WHERE file_sha_id = $1
+RETURNING *;
+
+-- name: TouchNote :exec
+UPDATE notes SET updated_at = $2
+WHERE file_sha_id = $1;
+
+-- name: DeleteNote :exec
+DELETE FROM notes
+WHERE file_sha_id = $1;
+
+-- name: FindFileForUpdate :one
+SELECT * FROM files
+WHERE sha_id = $1 AND user_id = $2 AND deleted_at IS NULL LIMIT 1
+FOR UPDATE;
+
+-- name: LockFolder :one
+SELECT sha_id FROM files
+WHERE sha_id = $1 AND user_id = $2 AND type = 'folder' AND deleted_at IS NULL
+FOR SHARE;
+
+-- name: CountFilesByName :one
+SELECT count(*) FROM files
+WHERE user_id = $1 AND folder_sha_id = $2 AND name = $3 AND sha_id <> $4 AND deleted_at IS NULL;
+
+-- name: GetFileAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT files.sha_id, files.folder_sha_id, ARRAY[files.sha_id]::varchar[] AS visited
+    FROM files
+    WHERE files.sha_id = $1 AND files.user_id = $2
+    UNION ALL
+    SELECT files.sha_id, files.folder_sha_id, ancestors.visited || files.sha_id
+    FROM files
+    JOIN ancestors ON files.sha_id = ancestors.folder_sha_id
+    WHERE files.user_id = $2 AND NOT files.sha_id = ANY(ancestors.visited)
+)
+SELECT sha_id FROM ancestors;
+
+-- name: UpdateFile :one
+UPDATE files SET folder_sha_id = $3, name = $4, path = $5, updated_at = $6
+WHERE sha_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: UpdateFilePathPrefix :exec
+UPDATE files SET path = @new_path::text || substr(path, length(@old_path::text) + 1), updated_at = @updated_at
+WHERE user_id = @user_id AND sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: GetFileDescendants :many
+WITH RECURSIVE tree AS (
+    SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, files.deleted_at, ARRAY[files.sha_id]::varchar[] AS visited
+    FROM files
+    WHERE files.folder_sha_id = $1 AND files.user_id = $2
+    UNION ALL
+    SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, files.deleted_at, tree.visited || files.sha_id
+    FROM files
+    JOIN tree ON files.folder_sha_id = tree.sha_id
+    WHERE files.user_id = $2 AND NOT files.sha_id = ANY(tree.visited)
+)
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM tree
+ORDER BY path;
+
+-- name: DeleteFiles :exec
+DELETE FROM files
+WHERE user_id = @user_id AND sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: DeleteNotes :exec
+DELETE FROM notes
+WHERE file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: CreateFolder :one
+INSERT INTO folders (sha_id, parent_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: FindFolder :one
+SELECT folders.id, folders.parent_id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, files.created_at, files.updated_at
+FROM folders
+JOIN files ON files.sha_id = folders.sha_id
+WHERE files.sha_id = $1 AND files.user_id = $2 AND files.type = 'folder' AND files.deleted_at IS NULL LIMIT 1;
+
+-- name: GetFolderChildren :many
+SELECT * FROM files
+WHERE user_id = $1 AND folder_sha_id = $2 AND deleted_at IS NULL
+ORDER BY type, name;
+
+-- name: CountFolderChildren :one
+SELECT count(*) FROM files
+WHERE user_id = $1 AND folder_sha_id = $2 AND deleted_at IS NULL;
+
+-- name: UpdateFolderParentBySha :exec
+UPDATE folders SET parent_id = (SELECT parent.id FROM folders AS parent WHERE parent.sha_id = @parent_sha_id), updated_at = @updated_at
+WHERE folders.sha_id = @sha_id;
+
+-- name: DeleteFolders :exec
+DELETE FROM folders
+WHERE sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: GetFileTree :many
+WITH RECURSIVE tree AS (
+    SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, 1 AS depth, ARRAY[files.sha_id]::varchar[] AS visited
+    FROM files
+    WHERE files.folder_sha_id = @folder_sha_id AND files.user_id = @user_id AND files.deleted_at IS NULL
+    UNION ALL
+    SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, tree.depth + 1, tree.visited || files.sha_id
+    FROM files
+    JOIN tree ON files.folder_sha_id = tree.sha_id
+    WHERE files.user_id = @user_id AND files.deleted_at IS NULL AND tree.depth < @max_depth::int AND NOT files.sha_id = ANY(tree.visited)
+)
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, depth FROM tree
+ORDER BY depth, type, name;
+
+-- name: CreateNoteRevision :one
+INSERT INTO note_revisions (file_sha_id, note, created_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetNoteRevisions :many
+SELECT * FROM note_revisions
+WHERE file_sha_id = $1
+ORDER BY id DESC;
+
+-- name: FindNoteRevision :one
+SELECT * FROM note_revisions
+WHERE id = $1 AND file_sha_id = $2 LIMIT 1;
+
+-- name: PruneNoteRevisions :exec
+DELETE FROM note_revisions
+WHERE note_revisions.file_sha_id = @file_sha_id AND note_revisions.id NOT IN (
+    SELECT kept.id FROM note_revisions AS kept
+    WHERE kept.file_sha_id = @file_sha_id
+    ORDER BY kept.id DESC
+    LIMIT @keep::int
+);
+
+-- name: DeleteNoteRevisions :exec
+DELETE FROM note_revisions
+WHERE file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: FindUserSettings :one
+SELECT * FROM user_settings
+WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertUserSettings :one
+INSERT INTO user_settings (user_id, note_revision_retention, updated_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET note_revision_retention = EXCLUDED.note_revision_retention, updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: SearchNotes :many
+WITH RECURSIVE subtree AS (
+    SELECT files.sha_id, ARRAY[files.sha_id]::varchar[] AS visited
+    FROM files
+    WHERE files.sha_id = @folder_sha_id AND files.user_id = @user_id AND files.type = 'folder'
+    UNION ALL
+    SELECT files.sha_id, subtree.visited || files.sha_id
+    FROM files
+    JOIN subtree ON files.folder_sha_id = subtree.sha_id
+    WHERE files.user_id = @user_id AND files.type = 'folder' AND NOT files.sha_id = ANY(subtree.visited)
+)
+SELECT files.sha_id, files.folder_sha_id, files.name, files.path, notes.updated_at,
+    ts_headline('english', files.name, websearch_to_tsquery('english', @query), E'StartSel=\x02, StopSel=\x03, HighlightAll=true')::text AS name_highlight,
+    ts_headline('english', COALESCE(notes.note, ''), websearch_to_tsquery('english', @query), E'StartSel=\x02, StopSel=\x03, MaxFragments=2')::text AS snippet,
+    ts_rank(
+        setweight(to_tsvector('english', files.name), 'A') || setweight(to_tsvector('english', COALESCE(notes.note, '')), 'B'),
+        websearch_to_tsquery('english', @query)
+    )::real AS rank
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = @user_id AND files.type = 'note' AND files.deleted_at IS NULL
+    AND (@folder_sha_id::varchar = '' OR files.folder_sha_id IN (SELECT subtree.sha_id FROM subtree))
+    AND (
+        to_tsvector('english', files.name) @@ websearch_to_tsquery('english', @query)
+        OR to_tsvector('english', COALESCE(notes.note, '')) @@ websearch_to_tsquery('english', @query)
+    )
+ORDER BY rank DESC, notes.updated_at DESC
+LIMIT @result_limit::int OFFSET @result_offset::int;
+
+-- name: UpsertTag :one
+INSERT INTO tags (user_id, name, created_at, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, name) DO UPDATE SET updated_at = tags.updated_at
+RETURNING *;
+
+-- name: FindTag :one
+SELECT * FROM tags
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: GetTags :many
+SELECT tags.id, tags.user_id, tags.name, tags.created_at, tags.updated_at, count(files.sha_id) AS usage_count
+FROM tags
+LEFT JOIN note_tags ON note_tags.tag_id = tags.id
+LEFT JOIN files ON files.sha_id = note_tags.file_sha_id AND files.deleted_at IS NULL
+WHERE tags.user_id = $1
+GROUP BY tags.id
+ORDER BY tags.name;
+
+-- name: GetNoteTags :many
+SELECT tags.id, tags.user_id, tags.name, tags.created_at, tags.updated_at
+FROM tags
+JOIN note_tags ON note_tags.tag_id = tags.id
+WHERE note_tags.file_sha_id = $1 AND tags.user_id = $2
+ORDER BY tags.name;
+
+-- name: AttachTag :exec
+INSERT INTO note_tags (file_sha_id, tag_id, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: DetachTag :execrows
+DELETE FROM note_tags
+WHERE file_sha_id = $1 AND tag_id = $2;
+
+-- name: RenameTag :one
+UPDATE tags SET name = $3, updated_at = $4
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: MergeTagNotes :exec
+INSERT INTO note_tags (file_sha_id, tag_id, created_at)
+SELECT note_tags.file_sha_id, @into_tag_id::int, @created_at::timestamp
+FROM note_tags
+WHERE note_tags.tag_id = @from_tag_id
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteTagNotes :exec
+DELETE FROM note_tags
+WHERE tag_id = $1;
+
+-- name: DeleteTag :exec
+DELETE FROM tags
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteNoteTags :exec
+DELETE FROM note_tags
+WHERE file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: GetNotesByTags :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = @user_id AND files.type = 'note' AND files.deleted_at IS NULL
+    AND (@folder_sha_id::varchar = '' OR files.folder_sha_id = @folder_sha_id::varchar)
+    AND files.sha_id IN (
+        SELECT note_tags.file_sha_id
+        FROM note_tags
+        JOIN tags ON tags.id = note_tags.tag_id
+        WHERE tags.user_id = @user_id AND tags.name = ANY(@tag_names::varchar[])
+        GROUP BY note_tags.file_sha_id
+        HAVING count(DISTINCT tags.id) >= @min_matches::int
+    )
+ORDER BY files.path;
+
+
+-- name: TrashFiles :exec
+UPDATE files SET deleted_at = @deleted_at::timestamp
+WHERE user_id = @user_id AND sha_id = ANY(@sha_ids::varchar[]) AND deleted_at IS NULL;
+
+-- name: TrashFolders :exec
+UPDATE folders SET deleted_at = @deleted_at::timestamp
+WHERE sha_id = ANY(@sha_ids::varchar[]) AND deleted_at IS NULL;
+
+-- name: TrashNotes :exec
+UPDATE notes SET deleted_at = @deleted_at::timestamp
+WHERE file_sha_id = ANY(@sha_ids::varchar[]) AND deleted_at IS NULL;
+
+-- name: RestoreFiles :exec
+UPDATE files SET deleted_at = NULL
+WHERE user_id = @user_id AND sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: RestoreFolders :exec
+UPDATE folders SET deleted_at = NULL
+WHERE sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: RestoreNotes :exec
+UPDATE notes SET deleted_at = NULL
+WHERE file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: GetTrash :many
+SELECT files.*
+FROM files
+LEFT JOIN files AS parent ON parent.sha_id = files.folder_sha_id
+WHERE files.user_id = $1 AND files.deleted_at IS NOT NULL
+    AND (parent.deleted_at IS NULL OR parent.deleted_at <> files.deleted_at)
+ORDER BY files.deleted_at DESC, files.path;
+
+-- name: FindTrashedFile :one
+SELECT * FROM files
+WHERE sha_id = $1 AND user_id = $2 AND deleted_at IS NOT NULL LIMIT 1;
+
+-- name: GetDeletedFiles :many
+SELECT * FROM files
+WHERE user_id = $1 AND deleted_at IS NOT NULL;
+
+-- name: GetExpiredFiles :many
+SELECT * FROM files
+WHERE deleted_at < @before::timestamp
+ORDER BY user_id;
+
+-- name: FindFileByShaID :one
+SELECT * FROM files
+WHERE sha_id = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: UpsertShare :one
+INSERT INTO shares (file_sha_id, owner_id, user_id, role, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (file_sha_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetFileShares :many
+SELECT shares.*, users.username
+FROM shares
+JOIN users ON users.id = shares.user_id
+WHERE shares.file_sha_id = $1
+ORDER BY users.username;
+
+-- name: GetUserShares :many
+SELECT * FROM shares
+WHERE user_id = @user_id AND file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: GetSharedFiles :many
+SELECT files.*, shares.role, users.username
+FROM shares
+JOIN files ON files.sha_id = shares.file_sha_id
+JOIN users ON users.id = files.user_id
+WHERE shares.user_id = $1 AND files.deleted_at IS NULL
+ORDER BY files.type, files.name;
+
+-- name: DeleteShare :execrows
+DELETE FROM shares
+WHERE file_sha_id = $1 AND user_id = $2;
+
+-- name: DeleteFileShares :exec
+DELETE FROM shares
+WHERE file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: CreatePublicLink :one
+INSERT INTO public_links (token, file_sha_id, user_id, password, expires_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: FindPublicLink :one
+SELECT * FROM public_links
+WHERE token = $1 AND revoked_at IS NULL LIMIT 1;
+
+-- name: GetPublicLinks :many
+SELECT * FROM public_links
+WHERE file_sha_id = $1 AND user_id = $2
+ORDER BY created_at DESC;
+
+-- name: RevokePublicLink :execrows
+UPDATE public_links SET revoked_at = $3, updated_at = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: IncrementPublicLinkViews :one
+UPDATE public_links SET view_count = view_count + 1
+WHERE id = $1
+RETURNING view_count;
+
+-- name: DeletePublicLinks :exec
+DELETE FROM public_links
+WHERE file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: CreateAttachment :one
+INSERT INTO attachments (sha_id, note_sha_id, user_id, name, mime_type, size, checksum, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: FindAttachment :one
+SELECT * FROM attachments
+WHERE sha_id = $1 LIMIT 1;
+
+-- name: GetNoteAttachments :many
+SELECT * FROM attachments
+WHERE note_sha_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteAttachment :one
+DELETE FROM attachments
+WHERE sha_id = $1
+RETURNING checksum;
+
+-- name: GetOrphanAttachments :many
+SELECT * FROM attachments
+WHERE NOT EXISTS (SELECT 1 FROM files WHERE files.sha_id = attachments.note_sha_id)
+ORDER BY id
+LIMIT $1;
+
+-- name: GetAttachmentUsage :one
+SELECT COUNT(*) AS count, COALESCE(SUM(size), 0)::bigint AS size FROM attachments
+WHERE user_id = $1;
+
+-- name: LockUser :exec
+SELECT id FROM users
+WHERE id = $1
+FOR UPDATE;
+
+-- name: AddBlobReference :one
+INSERT INTO blobs (checksum, size, ref_count, created_at, updated_at)
+VALUES ($1, $2, 1, $3, $3)
+ON CONFLICT (checksum) DO UPDATE SET ref_count = blobs.ref_count + 1, updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: MarkBlobStored :exec
+UPDATE blobs SET stored_at = $2
+WHERE checksum = $1;
+
+-- name: RemoveBlobReference :exec
+UPDATE blobs SET ref_count = ref_count - 1, updated_at = $2
+WHERE checksum = $1;
+
+-- name: GetUnusedBlobs :many
+SELECT * FROM blobs
+WHERE ref_count = 0
+ORDER BY updated_at
+LIMIT $1;
+
+-- name: LockUnusedBlob :one
+SELECT * FROM blobs
+WHERE checksum = $1 AND ref_count = 0
+FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteBlob :exec
+DELETE FROM blobs
+WHERE checksum = $1;
+
+-- name: CreateNoteLink :exec
+INSERT INTO note_links (source_sha_id, target_sha_id, user_id, target, heading, alias, line, snippet, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: DeleteNoteLinks :exec
+DELETE FROM note_links
+WHERE source_sha_id = $1;
+
+-- name: FindLinkTargets :many
+SELECT sha_id, folder_sha_id, path FROM files
+WHERE user_id = @user_id AND type = 'note' AND deleted_at IS NULL
+AND (sha_id = @target::text OR right(lower(path), length(@target::text) + 1) = '/' || lower(@target::text));
+
+-- name: ResolveNoteLinks :exec
+UPDATE note_links SET target_sha_id = @target_sha_id
+WHERE user_id = @user_id AND target_sha_id IS NULL
+AND right(lower(@path::text), length(target) + 1) = '/' || lower(target);
+
+-- name: GetNoteLinks :many
+SELECT note_links.*, files.name AS note_name, files.path AS note_path FROM note_links
+LEFT JOIN files ON files.sha_id = note_links.target_sha_id AND files.deleted_at IS NULL
+WHERE note_links.source_sha_id = $1
+ORDER BY note_links.line, note_links.id;
+
+-- name: GetNoteBacklinks :many
+SELECT note_links.*, files.name AS note_name, files.path AS note_path FROM note_links
+JOIN files ON files.sha_id = note_links.source_sha_id AND files.deleted_at IS NULL
+WHERE note_links.target_sha_id = $1
+ORDER BY files.path, note_links.line, note_links.id;
+
+-- name: GetNoteGraphNodes :many
+SELECT sha_id, name, path FROM files
+WHERE user_id = $1 AND type = 'note' AND deleted_at IS NULL
+ORDER BY path;
+
+-- name: GetNoteGraphEdges :many
+SELECT note_links.source_sha_id, note_links.target_sha_id, COUNT(*) AS count FROM note_links
+JOIN files sources ON sources.sha_id = note_links.source_sha_id AND sources.deleted_at IS NULL
+JOIN files targets ON targets.sha_id = note_links.target_sha_id AND targets.deleted_at IS NULL
+WHERE note_links.user_id = $1
+GROUP BY note_links.source_sha_id, note_links.target_sha_id
+ORDER BY note_links.source_sha_id, note_links.target_sha_id;
+
+-- name: DeleteNotesLinks :exec
+DELETE FROM note_links
+WHERE source_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: UnresolveNoteLinks :exec
+UPDATE note_links SET target_sha_id = NULL
+WHERE target_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: GetLinkingNotes :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE notes.file_sha_id IN (SELECT source_sha_id FROM note_links WHERE target_sha_id = ANY(@sha_ids::varchar[]))
+ORDER BY files.sha_id
+FOR UPDATE OF notes;
+
+-- name: UpsertTemplate :one
+INSERT INTO templates (file_sha_id, user_id, description, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (file_sha_id) DO UPDATE SET description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: FindTemplate :one
+SELECT templates.*, users.username
+FROM templates
+JOIN users ON users.id = templates.user_id
+WHERE templates.file_sha_id = $1 LIMIT 1;
+
+-- name: GetTemplates :many
+SELECT templates.*, files.folder_sha_id, files.name, files.path, users.username
+FROM templates
+JOIN files ON files.sha_id = templates.file_sha_id AND files.deleted_at IS NULL
+JOIN users ON users.id = templates.user_id
+WHERE templates.user_id = @user_id OR EXISTS (
+    SELECT 1 FROM shares
+    JOIN files shared ON shared.sha_id = shares.file_sha_id AND shared.deleted_at IS NULL
+    WHERE shares.user_id = @user_id AND shared.user_id = files.user_id
+    AND (shared.sha_id = files.sha_id OR (shared.type = 'folder' AND left(files.path, length(shared.path) + 1) = shared.path || '/'))
+)
+ORDER BY files.name, files.path;
+
+-- name: DeleteTemplate :execrows
+DELETE FROM templates
+WHERE file_sha_id = $1;
+
+-- name: DeleteTemplates :exec
+DELETE FROM templates
+WHERE file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: CreateReminder :one
+INSERT INTO reminders (file_sha_id, user_id, due_at, offset_minutes, remind_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetNoteReminders :many
+SELECT * FROM reminders
+WHERE file_sha_id = $1 AND user_id = $2
+ORDER BY remind_at, id;
+
+-- name: GetUserReminders :many
+SELECT reminders.*, files.name, files.path FROM reminders
+JOIN files ON files.sha_id = reminders.file_sha_id AND files.deleted_at IS NULL
+WHERE reminders.user_id = $1 AND reminders.sent_at IS NULL
+ORDER BY reminders.remind_at, reminders.id;
+
+-- name: DeleteNoteReminders :exec
+DELETE FROM reminders
+WHERE file_sha_id = $1 AND user_id = $2;
+
+-- name: ClaimDueReminders :many
+UPDATE reminders SET claimed_until = @claimed_until, attempts = attempts + 1
+WHERE id IN (
+    SELECT reminders.id FROM reminders
+    JOIN files ON files.sha_id = reminders.file_sha_id AND files.deleted_at IS NULL
+    WHERE reminders.sent_at IS NULL AND reminders.remind_at <= @now AND reminders.attempts < @max_attempts
+    AND (reminders.claimed_until IS NULL OR reminders.claimed_until <= @now)
+    ORDER BY reminders.remind_at
+    LIMIT @max_count
+    FOR UPDATE OF reminders SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkReminderSent :exec
+UPDATE reminders SET sent_at = $2, claimed_until = NULL, last_error = ''
+WHERE id = $1;
+
+-- name: RetryReminder :exec
+UPDATE reminders SET claimed_until = $2, last_error = $3, delivered_channels = $4
+WHERE id = $1;
+
+-- name: DeleteReminders :exec
+DELETE FROM reminders
+WHERE file_sha_id = ANY(@sha_ids::varchar[]);
+
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, file_sha_id, title, body, created_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetNotifications :many
+SELECT * FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: ReadNotification :execrows
+UPDATE notifications SET read_at = COALESCE(read_at, $3)
+WHERE id = $1 AND user_id = $2;
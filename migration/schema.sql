@@ -1,5 +1,56 @@
 -- Adminer 4.8.1 PostgreSQL 14.5 (Debian 14.5-1.pgdg110+1) dump
 
+DROP TABLE IF EXISTS "attachments";
+DROP SEQUENCE IF EXISTS attachments_id_seq;
+CREATE SEQUENCE attachments_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+CREATE TABLE "public"."attachments" (
+    "id" integer DEFAULT nextval('attachments_id_seq') NOT NULL,
+    "sha_id" character varying(10) NOT NULL,
+    "note_sha_id" character varying(10) NOT NULL,
+    "user_id" integer NOT NULL,
+    "name" character varying(255) NOT NULL,
+    "mime_type" character varying(100) NOT NULL,
+    "size" bigint NOT NULL,
+    "checksum" character varying(64) NOT NULL,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "attachments_pkey" PRIMARY KEY ("id")
+) WITH (oids = false);
+
+CREATE UNIQUE INDEX "attachments_sha_id" ON "public"."attachments" USING btree ("sha_id");
+
+CREATE INDEX "attachments_note_sha_id" ON "public"."attachments" USING btree ("note_sha_id");
+
+CREATE INDEX "attachments_user_id" ON "public"."attachments" USING btree ("user_id");
+
+CREATE INDEX "attachments_checksum" ON "public"."attachments" USING btree ("checksum");
+
+COMMENT ON COLUMN "public"."attachments"."user_id" IS 'owner of the note';
+
+COMMENT ON COLUMN "public"."attachments"."checksum" IS 'hex sha-256 of the content, the blob it is stored in';
+
+
+DROP TABLE IF EXISTS "blobs";
+
+CREATE TABLE "public"."blobs" (
+    "checksum" character varying(64) NOT NULL,
+    "size" bigint NOT NULL,
+    "ref_count" integer DEFAULT '0' NOT NULL,
+    "stored_at" timestamp,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    "updated_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "blobs_pkey" PRIMARY KEY ("checksum")
+) WITH (oids = false);
+
+CREATE INDEX "blobs_unused" ON "public"."blobs" USING btree ("updated_at") WHERE "ref_count" = 0;
+
+COMMENT ON COLUMN "public"."blobs"."checksum" IS 'hex sha-256 of the content, the blob store key is made from it';
+
+COMMENT ON COLUMN "public"."blobs"."ref_count" IS 'number of attachments with the content, 0 means the content can be deleted';
+
+COMMENT ON COLUMN "public"."blobs"."stored_at" IS 'null until the content is in the blob store';
+
+
 DROP TABLE IF EXISTS "files";
 DROP SEQUENCE IF EXISTS files_id_seq;
 CREATE SEQUENCE files_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
@@ -14,6 +65,7 @@ CREATE TABLE "public"."files" (
     "sha_id" character varying(10) NOT NULL,
     "path" text NOT NULL,
     "user_id" integer NOT NULL,
+    "deleted_at" timestamp,
     CONSTRAINT "files_pkey" PRIMARY KEY ("id")
 ) WITH (oids = false);
 
@@ -21,12 +73,18 @@ CREATE INDEX "files_folder_sha_id" ON "public"."files" USING btree ("folder_sha_
 
 CREATE INDEX "files_path" ON "public"."files" USING btree ("path");
 
-CREATE INDEX "files_sha_id" ON "public"."files" USING btree ("sha_id");
+CREATE UNIQUE INDEX "files_sha_id" ON "public"."files" USING btree ("sha_id");
 
 CREATE INDEX "files_type" ON "public"."files" USING btree ("type");
 
 CREATE INDEX "files_user_id" ON "public"."files" USING btree ("user_id");
 
+CREATE UNIQUE INDEX "files_user_id_folder_sha_id_name" ON "public"."files" USING btree ("user_id", "folder_sha_id", "name") WHERE "deleted_at" IS NULL;
+
+CREATE INDEX "files_deleted_at" ON "public"."files" USING btree ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+CREATE INDEX "files_name_search" ON "public"."files" USING gin (to_tsvector('english', "name"));
+
 COMMENT ON COLUMN "public"."files"."type" IS 'folder, note';
 
 
@@ -40,12 +98,13 @@ CREATE TABLE "public"."folders" (
     "parent_id" integer,
     "created_at" timestamp DEFAULT now() NOT NULL,
     "updated_at" timestamp DEFAULT now() NOT NULL,
+    "deleted_at" timestamp,
     CONSTRAINT "folders_pkey" PRIMARY KEY ("id")
 ) WITH (oids = false);
 
 CREATE INDEX "folders_parent_id" ON "public"."folders" USING btree ("parent_id");
 
-CREATE INDEX "folders_sha_id" ON "public"."folders" USING btree ("sha_id");
+CREATE UNIQUE INDEX "folders_sha_id" ON "public"."folders" USING btree ("sha_id");
 
 
 DROP TABLE IF EXISTS "notes";
@@ -58,10 +117,203 @@ CREATE TABLE "public"."notes" (
     "note" text,
     "created_at" timestamp DEFAULT now() NOT NULL,
     "updated_at" timestamp DEFAULT now() NOT NULL,
+    "deleted_at" timestamp,
     CONSTRAINT "notes_pkey" PRIMARY KEY ("id")
 ) WITH (oids = false);
 
-CREATE INDEX "notes_file_sha_id" ON "public"."notes" USING btree ("file_sha_id");
+CREATE UNIQUE INDEX "notes_file_sha_id" ON "public"."notes" USING btree ("file_sha_id");
+
+CREATE INDEX "notes_note_search" ON "public"."notes" USING gin (to_tsvector('english', COALESCE("note", '')));
+
+
+DROP TABLE IF EXISTS "note_links";
+DROP SEQUENCE IF EXISTS note_links_id_seq;
+CREATE SEQUENCE note_links_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+CREATE TABLE "public"."note_links" (
+    "id" integer DEFAULT nextval('note_links_id_seq') NOT NULL,
+    "source_sha_id" character varying(10) NOT NULL,
+    "target_sha_id" character varying(10),
+    "user_id" integer NOT NULL,
+    "target" text NOT NULL,
+    "heading" text DEFAULT '' NOT NULL,
+    "alias" text DEFAULT '' NOT NULL,
+    "line" integer NOT NULL,
+    "snippet" text DEFAULT '' NOT NULL,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "note_links_pkey" PRIMARY KEY ("id")
+) WITH (oids = false);
+
+CREATE INDEX "note_links_source_sha_id" ON "public"."note_links" USING btree ("source_sha_id");
+
+CREATE INDEX "note_links_target_sha_id" ON "public"."note_links" USING btree ("target_sha_id");
+
+CREATE INDEX "note_links_user_id" ON "public"."note_links" USING btree ("user_id");
+
+COMMENT ON COLUMN "public"."note_links"."target_sha_id" IS 'null while no note matches the target';
+
+COMMENT ON COLUMN "public"."note_links"."user_id" IS 'owner of the source note';
+
+COMMENT ON COLUMN "public"."note_links"."target" IS 'note name, path or sha id written in the link';
+
+
+DROP TABLE IF EXISTS "note_revisions";
+DROP SEQUENCE IF EXISTS note_revisions_id_seq;
+CREATE SEQUENCE note_revisions_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+CREATE TABLE "public"."note_revisions" (
+    "id" integer DEFAULT nextval('note_revisions_id_seq') NOT NULL,
+    "file_sha_id" character varying(10) NOT NULL,
+    "note" text,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "note_revisions_pkey" PRIMARY KEY ("id")
+) WITH (oids = false);
+
+CREATE INDEX "note_revisions_file_sha_id" ON "public"."note_revisions" USING btree ("file_sha_id");
+
+
+DROP TABLE IF EXISTS "note_tags";
+
+CREATE TABLE "public"."note_tags" (
+    "file_sha_id" character varying(10) NOT NULL,
+    "tag_id" integer NOT NULL,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "note_tags_pkey" PRIMARY KEY ("file_sha_id", "tag_id")
+) WITH (oids = false);
+
+CREATE INDEX "note_tags_tag_id" ON "public"."note_tags" USING btree ("tag_id");
+
+
+DROP TABLE IF EXISTS "notifications";
+DROP SEQUENCE IF EXISTS notifications_id_seq;
+CREATE SEQUENCE notifications_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+CREATE TABLE "public"."notifications" (
+    "id" integer DEFAULT nextval('notifications_id_seq') NOT NULL,
+    "user_id" integer NOT NULL,
+    "file_sha_id" character varying(10) NOT NULL,
+    "title" text NOT NULL,
+    "body" text DEFAULT '' NOT NULL,
+    "read_at" timestamp,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "notifications_pkey" PRIMARY KEY ("id")
+) WITH (oids = false);
+
+CREATE INDEX "notifications_user_id" ON "public"."notifications" USING btree ("user_id");
+
+
+DROP TABLE IF EXISTS "public_links";
+DROP SEQUENCE IF EXISTS public_links_id_seq;
+CREATE SEQUENCE public_links_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+CREATE TABLE "public"."public_links" (
+    "id" integer DEFAULT nextval('public_links_id_seq') NOT NULL,
+    "token" character varying(10) NOT NULL,
+    "file_sha_id" character varying(10) NOT NULL,
+    "user_id" integer NOT NULL,
+    "password" character varying(255),
+    "expires_at" timestamp,
+    "revoked_at" timestamp,
+    "view_count" integer DEFAULT '0' NOT NULL,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    "updated_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "public_links_pkey" PRIMARY KEY ("id")
+) WITH (oids = false);
+
+CREATE UNIQUE INDEX "public_links_token" ON "public"."public_links" USING btree ("token");
+
+CREATE INDEX "public_links_file_sha_id" ON "public"."public_links" USING btree ("file_sha_id");
+
+COMMENT ON COLUMN "public"."public_links"."password" IS 'argon2id hash, null when the link has no password';
+
+
+DROP TABLE IF EXISTS "reminders";
+DROP SEQUENCE IF EXISTS reminders_id_seq;
+CREATE SEQUENCE reminders_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+CREATE TABLE "public"."reminders" (
+    "id" integer DEFAULT nextval('reminders_id_seq') NOT NULL,
+    "file_sha_id" character varying(10) NOT NULL,
+    "user_id" integer NOT NULL,
+    "due_at" timestamp NOT NULL,
+    "offset_minutes" integer NOT NULL,
+    "remind_at" timestamp NOT NULL,
+    "sent_at" timestamp,
+    "claimed_until" timestamp,
+    "attempts" integer DEFAULT '0' NOT NULL,
+    "last_error" text DEFAULT '' NOT NULL,
+    "delivered_channels" text[] DEFAULT '{}' NOT NULL,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "reminders_pkey" PRIMARY KEY ("id")
+) WITH (oids = false);
+
+CREATE INDEX "reminders_file_sha_id_user_id" ON "public"."reminders" USING btree ("file_sha_id", "user_id");
+
+CREATE INDEX "reminders_pending" ON "public"."reminders" USING btree ("remind_at") WHERE "sent_at" IS NULL;
+
+COMMENT ON COLUMN "public"."reminders"."user_id" IS 'user the reminder is sent to';
+
+COMMENT ON COLUMN "public"."reminders"."offset_minutes" IS 'minutes before due_at the reminder is sent';
+
+COMMENT ON COLUMN "public"."reminders"."claimed_until" IS 'a scheduler is sending the reminder until then, a failed reminder is retried after it';
+
+COMMENT ON COLUMN "public"."reminders"."delivered_channels" IS 'notifiers the reminder was sent through, a failed reminder is retried on the others';
+
+
+DROP TABLE IF EXISTS "shares";
+DROP SEQUENCE IF EXISTS shares_id_seq;
+CREATE SEQUENCE shares_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+CREATE TABLE "public"."shares" (
+    "id" integer DEFAULT nextval('shares_id_seq') NOT NULL,
+    "file_sha_id" character varying(10) NOT NULL,
+    "owner_id" integer NOT NULL,
+    "user_id" integer NOT NULL,
+    "role" character varying(20) NOT NULL,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    "updated_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "shares_pkey" PRIMARY KEY ("id")
+) WITH (oids = false);
+
+CREATE UNIQUE INDEX "shares_file_sha_id_user_id" ON "public"."shares" USING btree ("file_sha_id", "user_id");
+
+CREATE INDEX "shares_user_id" ON "public"."shares" USING btree ("user_id");
+
+COMMENT ON COLUMN "public"."shares"."user_id" IS 'the user the file is shared with';
+
+COMMENT ON COLUMN "public"."shares"."role" IS 'viewer, commenter or editor';
+
+
+DROP TABLE IF EXISTS "tags";
+DROP SEQUENCE IF EXISTS tags_id_seq;
+CREATE SEQUENCE tags_id_seq INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+CREATE TABLE "public"."tags" (
+    "id" integer DEFAULT nextval('tags_id_seq') NOT NULL,
+    "user_id" integer NOT NULL,
+    "name" character varying(100) NOT NULL,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    "updated_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "tags_pkey" PRIMARY KEY ("id")
+) WITH (oids = false);
+
+CREATE UNIQUE INDEX "tags_user_id_name" ON "public"."tags" USING btree ("user_id", "name");
+
+
+DROP TABLE IF EXISTS "templates";
+
+CREATE TABLE "public"."templates" (
+    "file_sha_id" character varying(10) NOT NULL,
+    "user_id" integer NOT NULL,
+    "description" text DEFAULT '' NOT NULL,
+    "created_at" timestamp DEFAULT now() NOT NULL,
+    "updated_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "templates_pkey" PRIMARY KEY ("file_sha_id")
+) WITH (oids = false);
+
+CREATE INDEX "templates_user_id" ON "public"."templates" USING btree ("user_id");
+
+COMMENT ON COLUMN "public"."templates"."user_id" IS 'owner of the note';
 
 
 DROP TABLE IF EXISTS "users";
@@ -87,4 +339,16 @@ CREATE INDEX "users_phone_number" ON "public"."users" USING btree ("phone_number
 CREATE INDEX "users_username" ON "public"."users" USING btree ("username");
 
 
+DROP TABLE IF EXISTS "user_settings";
+
+CREATE TABLE "public"."user_settings" (
+    "user_id" integer NOT NULL,
+    "note_revision_retention" integer DEFAULT '100' NOT NULL,
+    "updated_at" timestamp DEFAULT now() NOT NULL,
+    CONSTRAINT "user_settings_pkey" PRIMARY KEY ("user_id")
+) WITH (oids = false);
+
+COMMENT ON COLUMN "public"."user_settings"."note_revision_retention" IS 'number of note revisions kept, 0 keeps all';
+
+
 -- 2022-08-23 09:05:42.61381+00
\ No newline at end of file
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+)
+
+const (
+	// an editor that does not answer the pings for this long is gone
+	collabPongWait   = 60 * time.Second
+	collabPingPeriod = collabPongWait * 9 / 10
+	collabWriteWait  = 10 * time.Second
+	// largest message an editor can send
+	collabMaxMessageSize = 1 << 20
+	// messages waiting to be written to an editor before it is dropped
+	collabSendBuffer = 256
+	// subprotocol of the socket, the server answers with it
+	collabSubprotocol = "notes-collab"
+	// prefix of the subprotocol carrying the token of a browser
+	collabTokenPrefix = "bearer."
+)
+
+type NoteCollabHandler struct {
+	NoteCollabUsecase domain.NoteCollabUsecase
+	Upgrader          websocket.Upgrader
+}
+
+func NewNoteCollabHandler(r *chi.Mux, u domain.NoteCollabUsecase) {
+	handler := &NoteCollabHandler{
+		NoteCollabUsecase: u,
+		Upgrader: websocket.Upgrader{
+			// the request is authenticated by its token and not by cookies,
+			// so the page opening the socket can be on any origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+			// never the token, the server must not send it back
+			Subprotocols: []string{collabSubprotocol},
+		},
+	}
+
+	// make group v1, browsers cannot set the authorization header on a
+	// websocket so they give the token as a subprotocol. It is not read from
+	// the query string, which the access log writes out
+	r.Route("/collab", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Get("/note/{sha_id}", helpers.RecoverWrap(handler.EditNote))
+		})
+	})
+
+}
+
+func (n NoteCollabHandler) EditNote(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	// a browser offers notes-collab and bearer.<token>
+	for _, protocol := range websocket.Subprotocols(r) {
+		if strings.HasPrefix(protocol, collabTokenPrefix) {
+			token = strings.TrimPrefix(protocol, collabTokenPrefix)
+		}
+	}
+
+	credentials, err := helpers.ValidateJwt(token)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// the upgrader answers the request itself when it fails
+	conn, err := n.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	shaID := chi.URLParam(r, "sha_id")
+	editor := &domain.CollabEditor{
+		UserID: credentials.ID,
+		Send:   make(chan domain.CollabMessage, collabSendBuffer),
+	}
+
+	// call usecase
+	err = n.NoteCollabUsecase.JoinNote(r.Context(), editor, shaID)
+	if err != nil {
+		conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+		conn.WriteJSON(domain.CollabMessage{
+			Type:  domain.CollabMessageError,
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// the note is saved when the last editor leaves, even once the request is gone
+	defer n.NoteCollabUsecase.LeaveNote(context.Background(), editor, shaID)
+
+	// only the writer writes to the connection, the reader hands it its errors
+	errs := make(chan domain.CollabMessage, collabSendBuffer)
+	go n.writeMessages(conn, editor, errs)
+
+	conn.SetReadLimit(collabMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var message domain.CollabMessage
+		err = json.Unmarshal(data, &message)
+		if err == nil && message.Type != domain.CollabMessageOps {
+			err = fmt.Errorf("%w: unknown message type %q", domain.ErrBadParamInput, message.Type)
+		}
+
+		if err == nil {
+			err = n.NoteCollabUsecase.SubmitOps(r.Context(), editor, shaID, message.Revision, message.Ops)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		select {
+		case errs <- domain.CollabMessage{
+			Type:     domain.CollabMessageError,
+			Revision: message.Revision,
+			Error:    err.Error(),
+		}:
+		default:
+			// the writer is gone or far behind, the connection is done
+			return
+		}
+	}
+}
+
+// writeMessages writes the messages of the session and the errors to the
+// connection and pings it until the session drops the editor
+func (n NoteCollabHandler) writeMessages(conn *websocket.Conn, editor *domain.CollabEditor, errs <-chan domain.CollabMessage) {
+	ticker := time.NewTicker(collabPingPeriod)
+	defer ticker.Stop()
+
+	// closing the connection also ends the reader
+	defer conn.Close()
+
+	for {
+		var err error
+
+		select {
+		case message, ok := <-editor.Send:
+			conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+
+			err = conn.WriteJSON(message)
+		case message := <-errs:
+			conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			err = conn.WriteJSON(message)
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			err = conn.WriteMessage(websocket.PingMessage, nil)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
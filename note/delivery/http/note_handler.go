@@ -0,0 +1,705 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+type NoteHandler struct {
+	NoteUsecase          domain.NoteUsecase
+	NoteRevisionUsecase  domain.NoteRevisionUsecase
+	NoteRenderUsecase    domain.NoteRenderUsecase
+	NoteLinkUsecase      domain.NoteLinkUsecase
+	NoteChecklistUsecase domain.NoteChecklistUsecase
+}
+
+func NewNoteHandler(r *chi.Mux, u domain.NoteUsecase, ru domain.NoteRevisionUsecase, rdu domain.NoteRenderUsecase, lu domain.NoteLinkUsecase, cu domain.NoteChecklistUsecase) {
+	handler := &NoteHandler{
+		NoteUsecase:          u,
+		NoteRevisionUsecase:  ru,
+		NoteRenderUsecase:    rdu,
+		NoteLinkUsecase:      lu,
+		NoteChecklistUsecase: cu,
+	}
+
+	// make group v1
+	r.Route("/note", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(middleware.MyMiddleware)
+			r.Post("/", helpers.RecoverWrap(handler.CreateNote))
+			r.Get("/", helpers.RecoverWrap(handler.GetNotes))
+			r.Get("/search", helpers.RecoverWrap(handler.SearchNotes))
+			r.Get("/graph", helpers.RecoverWrap(handler.GetNoteGraph))
+			r.Get("/tasks", helpers.RecoverWrap(handler.GetOpenTasks))
+			r.Get("/{sha_id}", helpers.RecoverWrap(handler.FindNote))
+			r.Put("/{sha_id}", helpers.RecoverWrap(handler.UpdateNote))
+			r.Delete("/{sha_id}", helpers.RecoverWrap(handler.DeleteNote))
+			r.Get("/{sha_id}/render", helpers.RecoverWrap(handler.RenderNote))
+			r.Get("/{sha_id}/links", helpers.RecoverWrap(handler.GetNoteLinks))
+			r.Get("/{sha_id}/backlinks", helpers.RecoverWrap(handler.GetNoteBacklinks))
+			r.Get("/{sha_id}/checklist", helpers.RecoverWrap(handler.GetChecklist))
+			r.Post("/{sha_id}/checklist", helpers.RecoverWrap(handler.AddChecklistItem))
+			r.Put("/{sha_id}/checklist/order", helpers.RecoverWrap(handler.ReorderChecklist))
+			r.Put("/{sha_id}/checklist/{anchor}/toggle", helpers.RecoverWrap(handler.ToggleChecklistItem))
+			r.Get("/{sha_id}/revisions", helpers.RecoverWrap(handler.GetNoteRevisions))
+			r.Get("/{sha_id}/revisions/diff", helpers.RecoverWrap(handler.DiffNoteRevisions))
+			r.Get("/{sha_id}/revisions/{revision_id}", helpers.RecoverWrap(handler.FindNoteRevision))
+			r.Post("/{sha_id}/revisions/{revision_id}/restore", helpers.RecoverWrap(handler.RestoreNoteRevision))
+		})
+	})
+
+}
+
+func (n NoteHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request body
+	var note domain.Note
+
+	err = json.NewDecoder(r.Body).Decode(&note)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// timestamps are set by the server
+	note.CreatedAt, note.UpdatedAt = time.Time{}, time.Time{}
+
+	// call usecase
+	note, err = n.NoteUsecase.CreateNote(r.Context(), credentials.ID, note)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "note created",
+		Data: map[string]interface{}{
+			"note": note,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) GetNotes(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form query params, tags is a comma separated list
+	// matched by any tag unless match=all
+	query := r.URL.Query()
+
+	params := domain.NoteListParams{
+		FolderShaID: query.Get("folder_sha_id"),
+		MatchAll:    query.Get("match") == "all",
+	}
+
+	if value := query.Get("tags"); value != "" {
+		params.Tags = strings.Split(value, ",")
+	}
+
+	// call usecase
+	notes, err := n.NoteUsecase.GetNotes(r.Context(), credentials.ID, params)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "notes found",
+		Data: map[string]interface{}{
+			"notes": notes,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) SearchNotes(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form query params
+	query := r.URL.Query()
+
+	params := domain.NoteSearchParams{
+		Query:       query.Get("q"),
+		FolderShaID: query.Get("folder_sha_id"),
+	}
+
+	if value := query.Get("limit"); value != "" {
+		params.Limit, err = strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if value := query.Get("offset"); value != "" {
+		params.Offset, err = strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// call usecase
+	results, err := n.NoteUsecase.SearchNotes(r.Context(), credentials.ID, params)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "notes found",
+		Data: map[string]interface{}{
+			"results": results,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) FindNote(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	note, err := n.NoteUsecase.FindNote(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	// the client already has this version
+	etag := helpers.ETag(note.UpdatedAt)
+	if helpers.MatchETag(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "note found",
+		Data: map[string]interface{}{
+			"note": note,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request body
+	var note domain.Note
+
+	err = json.NewDecoder(r.Body).Decode(&note)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	note.ShaID = chi.URLParam(r, "sha_id")
+
+	// call usecase, If-Match holds the etag of the version the change was made on
+	note, err = n.NoteUsecase.UpdateNote(r.Context(), credentials.ID, note, r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "note updated",
+		Data: map[string]interface{}{
+			"note": note,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", helpers.ETag(note.UpdatedAt))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase, If-Match holds the etag of the version the change was made on
+	err = n.NoteUsecase.DeleteNote(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "note moved to trash",
+		Data:    nil,
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) GetNoteRevisions(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	revisions, err := n.NoteRevisionUsecase.GetNoteRevisions(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "revisions found",
+		Data: map[string]interface{}{
+			"revisions": revisions,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) FindNoteRevision(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	revisionID, err := strconv.Atoi(chi.URLParam(r, "revision_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	revision, err := n.NoteRevisionUsecase.FindNoteRevision(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), revisionID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "revision found",
+		Data: map[string]interface{}{
+			"revision": revision,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) DiffNoteRevisions(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form query params
+	query := r.URL.Query()
+
+	fromID, err := strconv.Atoi(query.Get("from"))
+	if err != nil {
+		http.Error(w, "from must be a revision id", http.StatusBadRequest)
+		return
+	}
+
+	toID, err := strconv.Atoi(query.Get("to"))
+	if err != nil {
+		http.Error(w, "to must be a revision id", http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	diff, err := n.NoteRevisionUsecase.DiffNoteRevisions(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), fromID, toID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "diff created",
+		Data: map[string]interface{}{
+			"diff": diff,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) RestoreNoteRevision(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	revisionID, err := strconv.Atoi(chi.URLParam(r, "revision_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	note, err := n.NoteRevisionUsecase.RestoreNoteRevision(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), revisionID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "revision restored",
+		Data: map[string]interface{}{
+			"note": note,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) RenderNote(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	render, err := n.NoteRenderUsecase.RenderNote(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	// format=html returns the bare document for web and email views
+	if r.URL.Query().Get("format") == "html" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Last-Modified", render.UpdatedAt.UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(render.HTML))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "note rendered",
+		Data: map[string]interface{}{
+			"render": render,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) GetNoteLinks(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	links, err := n.NoteLinkUsecase.GetNoteLinks(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "links found",
+		Data: map[string]interface{}{
+			"links": links,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) GetNoteBacklinks(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	backlinks, err := n.NoteLinkUsecase.GetNoteBacklinks(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "backlinks found",
+		Data: map[string]interface{}{
+			"backlinks": backlinks,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) GetNoteGraph(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	graph, err := n.NoteLinkUsecase.GetNoteGraph(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "graph found",
+		Data: map[string]interface{}{
+			"graph": graph,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	checklist, err := n.NoteChecklistUsecase.GetChecklist(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "checklist found",
+		Data: map[string]interface{}{
+			"checklist": checklist,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", helpers.ETag(checklist.UpdatedAt))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request body
+	req := struct {
+		Text string `json:"text"`
+		// After is the anchor of the item the new one goes after
+		After string `json:"after"`
+	}{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase, If-Match holds the etag of the version the change was made on
+	checklist, err := n.NoteChecklistUsecase.AddChecklistItem(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), req.Text, req.After, r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "checklist item added",
+		Data: map[string]interface{}{
+			"checklist": checklist,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", helpers.ETag(checklist.UpdatedAt))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request body
+	req := struct {
+		Anchors []string `json:"anchors"`
+	}{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase, If-Match holds the etag of the version the change was made on
+	checklist, err := n.NoteChecklistUsecase.ReorderChecklist(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), req.Anchors, r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "checklist reordered",
+		Data: map[string]interface{}{
+			"checklist": checklist,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", helpers.ETag(checklist.UpdatedAt))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) ToggleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase, If-Match holds the etag of the version the change was made on
+	checklist, err := n.NoteChecklistUsecase.ToggleChecklistItem(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), chi.URLParam(r, "anchor"), r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "checklist item toggled",
+		Data: map[string]interface{}{
+			"checklist": checklist,
+		},
+	}
+
+	// return response
+	w.Header().Set("ETag", helpers.ETag(checklist.UpdatedAt))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (n NoteHandler) GetOpenTasks(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	tasks, err := n.NoteChecklistUsecase.GetOpenTasks(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "tasks found",
+		Data: map[string]interface{}{
+			"tasks": tasks,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
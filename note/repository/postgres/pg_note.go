@@ -0,0 +1,396 @@
+package note_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresNoteRepo struct {
+	DB     *sql.DB
+	Source sqlcpg.Querier
+}
+
+// CreateNote implements domain.NoteRepo
+func (p postgresNoteRepo) CreateNote(ctx context.Context, note domain.Note, retention int) (domain.Note, error) {
+	var result domain.Note
+	now := time.Now()
+
+	// imported notes keep the timestamps they had before
+	createdAt, updatedAt := now, now
+	if !note.CreatedAt.IsZero() {
+		createdAt = note.CreatedAt
+	}
+
+	if !note.UpdatedAt.IsZero() {
+		updatedAt = note.UpdatedAt
+	}
+
+	// the file and its content must be created together
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		// the folder is locked so it is not deleted while the note is created in it
+		if note.FolderShaID.String != "" {
+			_, err := q.LockFolder(ctx, sqlcpg.LockFolderParams{
+				ShaID:  note.FolderShaID.String,
+				UserID: int32(note.UserID),
+			})
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: folder not found", domain.ErrNotFound)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		file, err := q.CreateFile(ctx, sqlcpg.CreateFileParams{
+			FolderShaID: note.FolderShaID.String,
+			Name:        note.Name,
+			Type:        domain.FileTypeNote,
+			ShaID:       note.ShaID,
+			Path:        note.Path,
+			UserID:      int32(note.UserID),
+			CreatedAt:   createdAt,
+			UpdatedAt:   updatedAt,
+		})
+		if helpers.IsUniqueViolation(err, "files_user_id_folder_sha_id_name") {
+			return fmt.Errorf("%w: name already used in the folder", domain.ErrConflict)
+		}
+
+		if helpers.IsUniqueViolation(err, "files_sha_id") {
+			return domain.ErrDuplicateID
+		}
+
+		if err != nil {
+			return err
+		}
+
+		data, err := q.CreateNote(ctx, sqlcpg.CreateNoteParams{
+			FileShaID: file.ShaID,
+			Note:      note.Note.NullString,
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		})
+		if helpers.IsUniqueViolation(err, "notes_file_sha_id") {
+			return domain.ErrDuplicateID
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = saveRevision(ctx, q, data, retention)
+		if err != nil {
+			return err
+		}
+
+		created := sqlcpg.FindNoteRow{
+			ID:          data.ID,
+			ShaID:       file.ShaID,
+			FolderShaID: file.FolderShaID,
+			UserID:      file.UserID,
+			Name:        file.Name,
+			Path:        file.Path,
+			Note:        data.Note,
+			CreatedAt:   data.CreatedAt,
+			UpdatedAt:   data.UpdatedAt,
+		}
+
+		err = saveLinks(ctx, q, created)
+		if err != nil {
+			return err
+		}
+
+		// the links written before the note existed point at it now
+		err = resolvePendingLinks(ctx, q, file)
+		if err != nil {
+			return err
+		}
+
+		result = toDomainNote(created)
+		return nil
+	})
+
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return result, nil
+}
+
+// FindNote implements domain.NoteRepo
+func (p postgresNoteRepo) FindNote(ctx context.Context, userID int, shaID string) (domain.Note, error) {
+	data, err := p.Source.FindNote(ctx, sqlcpg.FindNoteParams{
+		ShaID:  shaID,
+		UserID: int32(userID),
+	})
+
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return toDomainNote(data), nil
+}
+
+// GetNotes implements domain.NoteRepo
+func (p postgresNoteRepo) GetNotes(ctx context.Context, userID int) ([]domain.Note, error) {
+	data, err := p.Source.GetNotes(ctx, int32(userID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []domain.Note{}
+	for _, v := range data {
+		notes = append(notes, toDomainNote(sqlcpg.FindNoteRow(v)))
+	}
+
+	return notes, nil
+}
+
+// GetNotesWithOpenTasks implements domain.NoteRepo
+func (p postgresNoteRepo) GetNotesWithOpenTasks(ctx context.Context, userID int) ([]domain.Note, error) {
+	data, err := p.Source.GetNotesWithOpenTasks(ctx, int32(userID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []domain.Note{}
+	for _, v := range data {
+		notes = append(notes, toDomainNote(sqlcpg.FindNoteRow(v)))
+	}
+
+	return notes, nil
+}
+
+// GetNotesByFolder implements domain.NoteRepo
+func (p postgresNoteRepo) GetNotesByFolder(ctx context.Context, userID int, folderShaID string) ([]domain.Note, error) {
+	data, err := p.Source.GetNotesByFolder(ctx, sqlcpg.GetNotesByFolderParams{
+		UserID:      int32(userID),
+		FolderShaID: folderShaID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []domain.Note{}
+	for _, v := range data {
+		notes = append(notes, toDomainNote(sqlcpg.FindNoteRow(v)))
+	}
+
+	return notes, nil
+}
+
+// GetNotesByTags implements domain.NoteRepo
+func (p postgresNoteRepo) GetNotesByTags(ctx context.Context, userID int, params domain.NoteListParams) ([]domain.Note, error) {
+	// a note needs one of the tags, or every one of them
+	minMatches := 1
+	if params.MatchAll {
+		minMatches = len(params.Tags)
+	}
+
+	data, err := p.Source.GetNotesByTags(ctx, sqlcpg.GetNotesByTagsParams{
+		UserID:      int32(userID),
+		FolderShaID: params.FolderShaID,
+		TagNames:    params.Tags,
+		MinMatches:  int32(minMatches),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []domain.Note{}
+	for _, v := range data {
+		notes = append(notes, toDomainNote(sqlcpg.FindNoteRow(v)))
+	}
+
+	return notes, nil
+}
+
+// UpdateNote implements domain.NoteRepo
+func (p postgresNoteRepo) UpdateNote(ctx context.Context, note domain.Note, version string, retention int) (domain.Note, error) {
+	var result domain.Note
+
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		// make sure the note belongs to the user and is still at the version
+		// the change was made on, the lock keeps it so until the end
+		existing, err := q.FindNoteForUpdate(ctx, sqlcpg.FindNoteForUpdateParams{
+			ShaID:  note.ShaID,
+			UserID: int32(note.UserID),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = helpers.CheckVersion(version, existing.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		result, err = SaveNote(ctx, q, note, retention)
+		return err
+	})
+
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return result, nil
+}
+
+// DeleteNote implements domain.NoteRepo
+func (p postgresNoteRepo) DeleteNote(ctx context.Context, userID int, shaID string, version string) error {
+	return helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		// make sure the note belongs to the user and is still at the version
+		// the change was made on, the lock keeps it so until the end
+		existing, err := q.FindNoteForUpdate(ctx, sqlcpg.FindNoteForUpdateParams{
+			ShaID:  shaID,
+			UserID: int32(userID),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = helpers.CheckVersion(version, existing.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		// the note stays in the trash with its revisions and tags until it is purged
+		now := time.Now()
+		err = q.TrashFiles(ctx, sqlcpg.TrashFilesParams{
+			DeletedAt: now,
+			UserID:    int32(userID),
+			ShaIds:    []string{shaID},
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.TrashNotes(ctx, sqlcpg.TrashNotesParams{
+			DeletedAt: now,
+			ShaIds:    []string{shaID},
+		})
+	})
+}
+
+// SearchNotes implements domain.NoteRepo
+func (p postgresNoteRepo) SearchNotes(ctx context.Context, userID int, params domain.NoteSearchParams) ([]domain.NoteSearchResult, error) {
+	data, err := p.Source.SearchNotes(ctx, sqlcpg.SearchNotesParams{
+		FolderShaID:  params.FolderShaID,
+		UserID:       int32(userID),
+		Query:        params.Query,
+		ResultLimit:  int32(params.Limit),
+		ResultOffset: int32(params.Offset),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the headlines are the text of the note, it is escaped before the
+	// markers of the matching words become html
+	results := []domain.NoteSearchResult{}
+	for _, v := range data {
+		results = append(results, domain.NoteSearchResult{
+			ShaID:         v.ShaID,
+			FolderShaID:   null.NewString(v.FolderShaID, v.FolderShaID != ""),
+			Name:          v.Name,
+			Path:          v.Path,
+			NameHighlight: helpers.HighlightHTML(v.NameHighlight),
+			Snippet:       helpers.HighlightHTML(v.Snippet),
+			Rank:          v.Rank,
+			UpdatedAt:     v.UpdatedAt,
+		})
+	}
+
+	return results, nil
+}
+
+// SaveNote saves the content of the note as a new revision with q and
+// indexes its links, the note must be locked by the transaction of q. The
+// file repo saves a renamed note with it in the transaction of the rename
+func SaveNote(ctx context.Context, q sqlcpg.Querier, note domain.Note, retention int) (domain.Note, error) {
+	updated, err := q.UpdateNote(ctx, sqlcpg.UpdateNoteParams{
+		FileShaID: note.ShaID,
+		Note:      note.Note.NullString,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	err = saveRevision(ctx, q, updated, retention)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	data, err := q.FindNote(ctx, sqlcpg.FindNoteParams{
+		ShaID:  note.ShaID,
+		UserID: int32(note.UserID),
+	})
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	err = saveLinks(ctx, q, data)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return toDomainNote(data), nil
+}
+
+// save the content of the note as a new revision and drop the old ones
+func saveRevision(ctx context.Context, q sqlcpg.Querier, note sqlcpg.Note, retention int) error {
+	_, err := q.CreateNoteRevision(ctx, sqlcpg.CreateNoteRevisionParams{
+		FileShaID: note.FileShaID,
+		Note:      note.Note,
+		CreatedAt: note.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if retention <= 0 {
+		return nil
+	}
+
+	return q.PruneNoteRevisions(ctx, sqlcpg.PruneNoteRevisionsParams{
+		FileShaID: note.FileShaID,
+		Keep:      int32(retention),
+	})
+}
+
+func toDomainNote(data sqlcpg.FindNoteRow) domain.Note {
+	return domain.Note{
+		ID:          int(data.ID),
+		ShaID:       data.ShaID,
+		FolderShaID: null.NewString(data.FolderShaID, data.FolderShaID != ""),
+		UserID:      int(data.UserID),
+		Name:        data.Name,
+		Path:        data.Path,
+		Note:        null.String{NullString: data.Note},
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func NewPostgresNoteRepo(db *sql.DB, source sqlcpg.Querier) domain.NoteRepo {
+	return &postgresNoteRepo{db, source}
+}
@@ -0,0 +1,236 @@
+package note_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresNoteLinkRepo struct {
+	Source sqlcpg.Querier
+}
+
+// GetNoteLinks implements domain.NoteLinkRepo
+func (p postgresNoteLinkRepo) GetNoteLinks(ctx context.Context, shaID string) ([]domain.NoteLink, error) {
+	data, err := p.Source.GetNoteLinks(ctx, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	links := []domain.NoteLink{}
+	for _, v := range data {
+		link := toDomainNoteLink(sqlcpg.NoteLink{
+			ID:          v.ID,
+			SourceShaID: v.SourceShaID,
+			TargetShaID: v.TargetShaID,
+			UserID:      v.UserID,
+			Target:      v.Target,
+			Heading:     v.Heading,
+			Alias:       v.Alias,
+			Line:        v.Line,
+			Snippet:     v.Snippet,
+			CreatedAt:   v.CreatedAt,
+		})
+
+		// the target may be in the trash
+		if v.NoteName.Valid {
+			link.Note = &domain.NoteNode{
+				ShaID: v.TargetShaID.String,
+				Name:  v.NoteName.String,
+				Path:  v.NotePath.String,
+			}
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// GetNoteBacklinks implements domain.NoteLinkRepo
+func (p postgresNoteLinkRepo) GetNoteBacklinks(ctx context.Context, shaID string) ([]domain.NoteLink, error) {
+	data, err := p.Source.GetNoteBacklinks(ctx, sql.NullString{String: shaID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	links := []domain.NoteLink{}
+	for _, v := range data {
+		link := toDomainNoteLink(sqlcpg.NoteLink{
+			ID:          v.ID,
+			SourceShaID: v.SourceShaID,
+			TargetShaID: v.TargetShaID,
+			UserID:      v.UserID,
+			Target:      v.Target,
+			Heading:     v.Heading,
+			Alias:       v.Alias,
+			Line:        v.Line,
+			Snippet:     v.Snippet,
+			CreatedAt:   v.CreatedAt,
+		})
+		link.Note = &domain.NoteNode{
+			ShaID: v.SourceShaID,
+			Name:  v.NoteName,
+			Path:  v.NotePath,
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// GetNoteGraph implements domain.NoteLinkRepo
+func (p postgresNoteLinkRepo) GetNoteGraph(ctx context.Context, userID int) (domain.NoteGraph, error) {
+	nodes, err := p.Source.GetNoteGraphNodes(ctx, int32(userID))
+	if err != nil {
+		return domain.NoteGraph{}, err
+	}
+
+	edges, err := p.Source.GetNoteGraphEdges(ctx, int32(userID))
+	if err != nil {
+		return domain.NoteGraph{}, err
+	}
+
+	graph := domain.NoteGraph{
+		Nodes: []domain.NoteNode{},
+		Edges: []domain.NoteEdge{},
+	}
+
+	for _, v := range nodes {
+		graph.Nodes = append(graph.Nodes, domain.NoteNode{
+			ShaID: v.ShaID,
+			Name:  v.Name,
+			Path:  v.Path,
+		})
+	}
+
+	for _, v := range edges {
+		graph.Edges = append(graph.Edges, domain.NoteEdge{
+			Source: v.SourceShaID,
+			Target: v.TargetShaID.String,
+			Count:  int(v.Count),
+		})
+	}
+
+	return graph, nil
+}
+
+// saveLinks replaces the links of the note with the ones written in its
+// content, they point at the notes of the owner of the note
+func saveLinks(ctx context.Context, q sqlcpg.Querier, note sqlcpg.FindNoteRow) error {
+	err := q.DeleteNoteLinks(ctx, note.ShaID)
+	if err != nil {
+		return err
+	}
+
+	targets := map[string]sql.NullString{}
+	for _, link := range helpers.ParseWikiLinks(note.Note.String) {
+		target, ok := targets[link.Target]
+		if !ok {
+			target, err = resolveLink(ctx, q, note, link.Target)
+			if err != nil {
+				return err
+			}
+
+			targets[link.Target] = target
+		}
+
+		// an embed that is not a note is most likely an image
+		if link.Embed && !target.Valid {
+			continue
+		}
+
+		err = q.CreateNoteLink(ctx, sqlcpg.CreateNoteLinkParams{
+			SourceShaID: note.ShaID,
+			TargetShaID: target,
+			UserID:      note.UserID,
+			Target:      link.Target,
+			Heading:     link.Heading,
+			Alias:       link.Alias,
+			Line:        int32(link.Line),
+			Snippet:     link.Snippet,
+			CreatedAt:   note.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveLink finds the note the target of a link points at, by sha id or by
+// the end of its path. A note in the same folder wins, then the shortest path
+func resolveLink(ctx context.Context, q sqlcpg.Querier, note sqlcpg.FindNoteRow, target string) (sql.NullString, error) {
+	target = strings.Trim(target, "/")
+	if strings.HasSuffix(strings.ToLower(target), helpers.WikiLinkNoteExtension) {
+		target = target[:len(target)-len(helpers.WikiLinkNoteExtension)]
+	}
+
+	candidates, err := q.FindLinkTargets(ctx, sqlcpg.FindLinkTargetsParams{
+		UserID: note.UserID,
+		Target: target,
+	})
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	if len(candidates) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	dir := path.Dir(note.Path)
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.ShaID == target) != (b.ShaID == target) {
+			return a.ShaID == target
+		}
+
+		if (path.Dir(a.Path) == dir) != (path.Dir(b.Path) == dir) {
+			return path.Dir(a.Path) == dir
+		}
+
+		if len(a.Path) != len(b.Path) {
+			return len(a.Path) < len(b.Path)
+		}
+
+		return a.Path < b.Path
+	})
+
+	return sql.NullString{String: candidates[0].ShaID, Valid: true}, nil
+}
+
+// resolvePendingLinks points the links waiting for a note of the path at the note
+func resolvePendingLinks(ctx context.Context, q sqlcpg.Querier, file sqlcpg.File) error {
+	return q.ResolveNoteLinks(ctx, sqlcpg.ResolveNoteLinksParams{
+		TargetShaID: sql.NullString{String: file.ShaID, Valid: true},
+		UserID:      file.UserID,
+		Path:        file.Path,
+	})
+}
+
+func toDomainNoteLink(data sqlcpg.NoteLink) domain.NoteLink {
+	return domain.NoteLink{
+		ID:          int(data.ID),
+		SourceShaID: data.SourceShaID,
+		TargetShaID: null.String{NullString: data.TargetShaID},
+		Target:      data.Target,
+		Heading:     data.Heading,
+		Alias:       data.Alias,
+		Line:        int(data.Line),
+		Snippet:     data.Snippet,
+		CreatedAt:   data.CreatedAt,
+	}
+}
+
+func NewPostgresNoteLinkRepo(source sqlcpg.Querier) domain.NoteLinkRepo {
+	return &postgresNoteLinkRepo{source}
+}
@@ -0,0 +1,56 @@
+package note_repo_pg
+
+import (
+	"context"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresNoteRevisionRepo struct {
+	Source sqlcpg.Querier
+}
+
+// GetNoteRevisions implements domain.NoteRevisionRepo
+func (p postgresNoteRevisionRepo) GetNoteRevisions(ctx context.Context, fileShaID string) ([]domain.NoteRevision, error) {
+	data, err := p.Source.GetNoteRevisions(ctx, fileShaID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []domain.NoteRevision{}
+	for _, v := range data {
+		revisions = append(revisions, toDomainNoteRevision(v))
+	}
+
+	return revisions, nil
+}
+
+// FindNoteRevision implements domain.NoteRevisionRepo
+func (p postgresNoteRevisionRepo) FindNoteRevision(ctx context.Context, fileShaID string, id int) (domain.NoteRevision, error) {
+	data, err := p.Source.FindNoteRevision(ctx, sqlcpg.FindNoteRevisionParams{
+		ID:        int32(id),
+		FileShaID: fileShaID,
+	})
+
+	if err != nil {
+		return domain.NoteRevision{}, err
+	}
+
+	return toDomainNoteRevision(data), nil
+}
+
+func toDomainNoteRevision(data sqlcpg.NoteRevision) domain.NoteRevision {
+	return domain.NoteRevision{
+		ID:        int(data.ID),
+		FileShaID: data.FileShaID,
+		Note:      null.String{NullString: data.Note},
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func NewPostgresNoteRevisionRepo(source sqlcpg.Querier) domain.NoteRevisionRepo {
+	return &postgresNoteRevisionRepo{source}
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+)
+
+type NoteChecklistUseCaseImpl struct {
+	NoteRepo     domain.NoteRepo
+	NoteUsecase  domain.NoteUsecase
+	UserUsecase  domain.UserUsecase
+	ShareUsecase domain.ShareUsecase
+}
+
+// GetChecklist implements domain.NoteChecklistUsecase
+func (n NoteChecklistUseCaseImpl) GetChecklist(ctx context.Context, userID int, shaID string) (domain.NoteChecklist, error) {
+	note, err := n.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return domain.NoteChecklist{}, err
+	}
+
+	return toNoteChecklist(note, helpers.ParseChecklist(note.Note.String)), nil
+}
+
+// ToggleChecklistItem implements domain.NoteChecklistUsecase
+func (n NoteChecklistUseCaseImpl) ToggleChecklistItem(ctx context.Context, userID int, shaID string, anchor string, version string) (domain.NoteChecklist, error) {
+	return n.rewriteNote(ctx, userID, shaID, version, func(content string) (string, error) {
+		return helpers.ToggleChecklistItem(content, anchor)
+	})
+}
+
+// AddChecklistItem implements domain.NoteChecklistUsecase
+func (n NoteChecklistUseCaseImpl) AddChecklistItem(ctx context.Context, userID int, shaID string, text string, after string, version string) (domain.NoteChecklist, error) {
+	return n.rewriteNote(ctx, userID, shaID, version, func(content string) (string, error) {
+		return helpers.AddChecklistItem(content, text, after)
+	})
+}
+
+// ReorderChecklist implements domain.NoteChecklistUsecase
+func (n NoteChecklistUseCaseImpl) ReorderChecklist(ctx context.Context, userID int, shaID string, anchors []string, version string) (domain.NoteChecklist, error) {
+	return n.rewriteNote(ctx, userID, shaID, version, func(content string) (string, error) {
+		return helpers.ReorderChecklist(content, anchors)
+	})
+}
+
+// GetOpenTasks implements domain.NoteChecklistUsecase
+func (n NoteChecklistUseCaseImpl) GetOpenTasks(ctx context.Context, userID int) ([]domain.NoteChecklist, error) {
+	// call repository
+	notes, err := n.NoteRepo.GetNotesWithOpenTasks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the query also finds the boxes in fenced code and the checked items
+	// written next to an unchecked box, the parser has the last word
+	checklists := []domain.NoteChecklist{}
+	for _, note := range notes {
+		items := []domain.ChecklistItem{}
+		for _, item := range helpers.ParseChecklist(note.Note.String) {
+			if !item.Checked {
+				items = append(items, item)
+			}
+		}
+
+		if len(items) > 0 {
+			checklists = append(checklists, toNoteChecklist(note, items))
+		}
+	}
+
+	return checklists, nil
+}
+
+// rewriteNote saves the content changed by rewrite. The note is read once and
+// written only when it is still at the version read, so a note saved in
+// between is not overwritten
+func (n NoteChecklistUseCaseImpl) rewriteNote(ctx context.Context, userID int, shaID string, version string, rewrite func(content string) (string, error)) (domain.NoteChecklist, error) {
+	file, err := n.ShareUsecase.Authorize(ctx, userID, shaID, domain.ShareRoleEditor)
+	if err != nil {
+		return domain.NoteChecklist{}, err
+	}
+
+	if file.Type != domain.FileTypeNote {
+		return domain.NoteChecklist{}, fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	// call repository with the owner of the note
+	note, err := n.NoteRepo.FindNote(ctx, file.UserID, shaID)
+	if err == sql.ErrNoRows {
+		return domain.NoteChecklist{}, fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.NoteChecklist{}, err
+	}
+
+	// a change made on an older version fails here, the write checks the
+	// version read has not changed since
+	err = helpers.CheckVersion(version, note.UpdatedAt)
+	if err != nil {
+		return domain.NoteChecklist{}, err
+	}
+
+	content, err := rewrite(note.Note.String)
+	if err != nil {
+		return domain.NoteChecklist{}, err
+	}
+
+	settings, err := n.UserUsecase.GetUserSettings(ctx, file.UserID)
+	if err != nil {
+		return domain.NoteChecklist{}, err
+	}
+
+	// call repository
+	note, err = n.NoteRepo.UpdateNote(ctx, domain.Note{
+		ShaID:  shaID,
+		UserID: file.UserID,
+		Note:   null.StringFrom(content),
+	}, helpers.ETag(note.UpdatedAt), settings.NoteRevisionRetention)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NoteChecklist{}, fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.NoteChecklist{}, err
+	}
+
+	return toNoteChecklist(note, helpers.ParseChecklist(note.Note.String)), nil
+}
+
+func toNoteChecklist(note domain.Note, items []domain.ChecklistItem) domain.NoteChecklist {
+	return domain.NoteChecklist{
+		ShaID:     note.ShaID,
+		Name:      note.Name,
+		Path:      note.Path,
+		Items:     items,
+		UpdatedAt: note.UpdatedAt,
+	}
+}
+
+func NewNoteChecklistUseCase(nr domain.NoteRepo, nu domain.NoteUsecase, uu domain.UserUsecase, su domain.ShareUsecase) domain.NoteChecklistUsecase {
+	return &NoteChecklistUseCaseImpl{
+		NoteRepo:     nr,
+		NoteUsecase:  nu,
+		UserUsecase:  uu,
+		ShareUsecase: su,
+	}
+}
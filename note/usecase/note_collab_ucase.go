@@ -0,0 +1,477 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/guregu/null.v4"
+)
+
+// number of past revisions kept to transform late ops, an editor
+// further behind has to join again
+const collabHistorySize = 1000
+
+// collabSession is the text of a note being edited and the ops applied to it
+type collabSession struct {
+	mu       sync.Mutex
+	shaID    string
+	ownerID  int
+	text     string
+	revision int
+	// history holds the ops that made the last revisions,
+	// the first entry made revision - len(history) + 1
+	history [][]domain.TextOp
+	// editors maps every editor to whether it can change the text
+	editors map[*domain.CollabEditor]bool
+	// dirty is set when the text changed since the last snapshot
+	dirty bool
+	// version is the etag of the saved note the text was last in sync with
+	version string
+	// saved is the text of the note at version, made into the session text
+	// by the ops since savedRevision. A savedRevision of -1 means the ops
+	// since the last save are unknown and a change outside cannot be merged
+	saved         string
+	savedRevision int
+	// closed is set once the session is left or lost to a change made
+	// outside of it, an editor finding it closed opens a new one
+	closed bool
+}
+
+type NoteCollabUseCaseImpl struct {
+	NoteRepo     domain.NoteRepo
+	NoteUsecase  domain.NoteUsecase
+	UserUsecase  domain.UserUsecase
+	ShareUsecase domain.ShareUsecase
+
+	mu       *sync.Mutex
+	sessions map[string]*collabSession
+}
+
+// JoinNote implements domain.NoteCollabUsecase
+func (n NoteCollabUseCaseImpl) JoinNote(ctx context.Context, editor *domain.CollabEditor, shaID string) error {
+	canEdit, err := n.canEdit(ctx, editor.UserID, shaID)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// read again after a closed session, it may have just saved the note
+		note, err := n.NoteUsecase.FindNote(ctx, editor.UserID, shaID)
+		if err != nil {
+			return err
+		}
+
+		session := n.openSession(note)
+
+		// the session is locked without the sessions, a save holding it only
+		// keeps the editors of this note waiting
+		session.mu.Lock()
+		if session.closed {
+			session.mu.Unlock()
+			n.removeSession(session)
+			continue
+		}
+
+		editor.Send <- domain.CollabMessage{
+			Type:     domain.CollabMessageInit,
+			Revision: session.revision,
+			Text:     session.text,
+			ReadOnly: !canEdit,
+		}
+
+		session.broadcast(nil, domain.CollabMessage{
+			Type:     domain.CollabMessageJoin,
+			Revision: session.revision,
+			UserID:   editor.UserID,
+		})
+		session.editors[editor] = canEdit
+		session.mu.Unlock()
+
+		return nil
+	}
+}
+
+// openSession returns the session of the note, a new one starts from the note
+func (n NoteCollabUseCaseImpl) openSession(note domain.Note) *collabSession {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	session, ok := n.sessions[note.ShaID]
+	if !ok {
+		session = &collabSession{
+			shaID:   note.ShaID,
+			ownerID: note.UserID,
+			text:    note.Note.String,
+			version: helpers.ETag(note.UpdatedAt),
+			saved:   note.Note.String,
+			editors: map[*domain.CollabEditor]bool{},
+		}
+		n.sessions[note.ShaID] = session
+	}
+
+	return session
+}
+
+// removeSession forgets the session once it is closed, unless a new session
+// of the note already took its place
+func (n NoteCollabUseCaseImpl) removeSession(session *collabSession) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.sessions[session.shaID] == session {
+		delete(n.sessions, session.shaID)
+	}
+}
+
+// canEdit tells if the user can change the note, viewers and commenters
+// follow the session read only
+func (n NoteCollabUseCaseImpl) canEdit(ctx context.Context, userID int, shaID string) (bool, error) {
+	_, err := n.ShareUsecase.Authorize(ctx, userID, shaID, domain.ShareRoleEditor)
+	if errors.Is(err, domain.ErrForbidden) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SubmitOps implements domain.NoteCollabUsecase
+func (n NoteCollabUseCaseImpl) SubmitOps(ctx context.Context, editor *domain.CollabEditor, shaID string, revision int, ops []domain.TextOp) error {
+	session := n.findSession(shaID)
+	if session == nil {
+		return fmt.Errorf("%w: join the note first", domain.ErrBadParamInput)
+	}
+
+	// the share may have changed since the editor joined
+	canEdit, err := n.canEdit(ctx, editor.UserID, shaID)
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if _, ok := session.editors[editor]; !ok {
+		return fmt.Errorf("%w: join the note first", domain.ErrBadParamInput)
+	}
+
+	// a user who cannot see the note anymore stops following it
+	if errors.Is(err, domain.ErrNotFound) {
+		session.drop(editor)
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	session.editors[editor] = canEdit
+	if !canEdit {
+		return fmt.Errorf("%w: %s access is required", domain.ErrForbidden, domain.ShareRoleEditor)
+	}
+
+	if revision > session.revision || revision < 0 {
+		return fmt.Errorf("%w: unknown revision %d", domain.ErrBadParamInput, revision)
+	}
+
+	// the ops were made without the ones applied since the revision
+	missed := session.revision - revision
+	if missed > len(session.history) {
+		return fmt.Errorf("%w: revision %d is too old, join the note again", domain.ErrConflict, revision)
+	}
+
+	for _, applied := range session.history[len(session.history)-missed:] {
+		ops = helpers.TransformTextOps(ops, applied)
+	}
+
+	err = session.apply(editor, ops)
+	if err != nil {
+		return err
+	}
+
+	session.dirty = true
+	session.send(editor, domain.CollabMessage{
+		Type:     domain.CollabMessageAck,
+		Revision: session.revision,
+	})
+
+	return nil
+}
+
+// LeaveNote implements domain.NoteCollabUsecase
+func (n NoteCollabUseCaseImpl) LeaveNote(ctx context.Context, editor *domain.CollabEditor, shaID string) {
+	session := n.findSession(shaID)
+	if session == nil {
+		return
+	}
+
+	// the session stays open until saved so nobody opens it from an old note
+	session.mu.Lock()
+
+	if _, ok := session.editors[editor]; ok {
+		session.drop(editor)
+		session.broadcast(nil, domain.CollabMessage{
+			Type:     domain.CollabMessageLeave,
+			Revision: session.revision,
+			UserID:   editor.UserID,
+		})
+	}
+
+	if len(session.editors) > 0 || session.closed {
+		session.mu.Unlock()
+		return
+	}
+
+	// the last editor is gone, nobody is left to tell about a failed save
+	_ = n.saveSession(ctx, session)
+
+	session.closed = true
+	session.mu.Unlock()
+
+	n.removeSession(session)
+}
+
+// SaveSnapshots implements domain.NoteCollabUsecase
+func (n NoteCollabUseCaseImpl) SaveSnapshots(ctx context.Context) error {
+	n.mu.Lock()
+	sessions := make([]*collabSession, 0, len(n.sessions))
+	for _, session := range n.sessions {
+		sessions = append(sessions, session)
+	}
+	n.mu.Unlock()
+
+	var errs []error
+	for _, session := range sessions {
+		session.mu.Lock()
+		err := n.saveSession(ctx, session)
+		closed := session.closed
+		session.mu.Unlock()
+
+		if closed {
+			n.removeSession(session)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("note %s: %w", session.shaID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (n NoteCollabUseCaseImpl) findSession(shaID string) *collabSession {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.sessions[shaID]
+}
+
+// saveSession saves the text of the session as a new revision of the note,
+// the session must be locked. The note is only saved at the version the
+// session is in sync with, a note changed outside of the session is merged
+// into it first. A session with nothing to save only takes in the changes
+func (n NoteCollabUseCaseImpl) saveSession(ctx context.Context, session *collabSession) error {
+	if session.closed {
+		return nil
+	}
+
+	if !session.dirty {
+		return n.syncSession(ctx, session)
+	}
+
+	settings, err := n.UserUsecase.GetUserSettings(ctx, session.ownerID)
+	if err != nil {
+		return err
+	}
+
+	// a note changed again between the merge and the save is not merged twice
+	for attempt := 0; ; attempt++ {
+		// call repository
+		note, err := n.NoteRepo.UpdateNote(ctx, domain.Note{
+			ShaID:  session.shaID,
+			UserID: session.ownerID,
+			Note:   null.StringFrom(session.text),
+		}, session.version, settings.NoteRevisionRetention)
+
+		var versionConflict *domain.VersionConflictError
+		if errors.As(err, &versionConflict) && attempt == 0 {
+			err = n.syncSession(ctx, session)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if errors.As(err, &versionConflict) {
+			session.close(domain.CollabMessage{
+				Type:     domain.CollabMessageError,
+				Revision: session.revision,
+				Text:     session.text,
+				Error:    "the note keeps being changed outside of the session, the text was not saved",
+			})
+			return err
+		}
+
+		if err != nil {
+			return err
+		}
+
+		session.synced(note.Note.String, note.UpdatedAt)
+		return nil
+	}
+}
+
+// syncSession merges the note saved outside of the session into it, the
+// editors get the changes as ops of the server. A session that cannot take
+// them in is closed with its text so the editors keep what was not saved
+func (n NoteCollabUseCaseImpl) syncSession(ctx context.Context, session *collabSession) error {
+	// call repository
+	note, err := n.NoteRepo.FindNote(ctx, session.ownerID, session.shaID)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	if err == nil && helpers.ETag(note.UpdatedAt) == session.version {
+		return nil
+	}
+
+	if err == nil {
+		err = session.merge(note)
+	}
+
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrConflict) {
+		session.close(domain.CollabMessage{
+			Type:     domain.CollabMessageError,
+			Revision: session.revision,
+			Text:     session.text,
+			Error:    fmt.Sprintf("the note was changed outside of the session, the text was not saved: %s", err),
+		})
+	}
+
+	return err
+}
+
+// merge applies the changes between the saved text and the note after the
+// ops made since, as the ops of an editor who was late
+func (s *collabSession) merge(note domain.Note) error {
+	missed := s.revision - s.savedRevision
+	if s.savedRevision < 0 || missed > len(s.history) {
+		return fmt.Errorf("%w: the changes of the session are too far behind to merge", domain.ErrConflict)
+	}
+
+	ops := helpers.DiffTextOps(s.saved, note.Note.String)
+	for _, applied := range s.history[len(s.history)-missed:] {
+		ops = helpers.TransformTextOps(ops, applied)
+	}
+
+	if len(ops) > 0 {
+		err := s.apply(nil, ops)
+		if err != nil {
+			return err
+		}
+	}
+
+	// with nothing of its own to save the session is now the note, else
+	// the next save writes both and the saved text is not a base anymore
+	if !s.dirty {
+		s.synced(note.Note.String, note.UpdatedAt)
+		return nil
+	}
+
+	s.version = helpers.ETag(note.UpdatedAt)
+	s.savedRevision = -1
+	return nil
+}
+
+// apply makes a new revision of the ops and sends them to the editors but
+// the one who made them, nil for the changes made outside of the session
+func (s *collabSession) apply(editor *domain.CollabEditor, ops []domain.TextOp) error {
+	text, err := helpers.ApplyTextOps(s.text, ops)
+	if err != nil {
+		return err
+	}
+
+	s.text = text
+	s.revision++
+	s.history = append(s.history, ops)
+	if len(s.history) > collabHistorySize {
+		s.history = s.history[len(s.history)-collabHistorySize:]
+	}
+
+	userID := 0
+	if editor != nil {
+		userID = editor.UserID
+	}
+
+	s.broadcast(editor, domain.CollabMessage{
+		Type:     domain.CollabMessageOps,
+		Revision: s.revision,
+		Ops:      ops,
+		UserID:   userID,
+	})
+
+	return nil
+}
+
+// synced records the text saved at the version of updatedAt as the session text
+func (s *collabSession) synced(text string, updatedAt time.Time) {
+	s.saved = text
+	s.savedRevision = s.revision
+	s.version = helpers.ETag(updatedAt)
+	s.dirty = false
+}
+
+// send gives the message to the editor, dropping it when its buffer is full
+func (s *collabSession) send(editor *domain.CollabEditor, message domain.CollabMessage) {
+	select {
+	case editor.Send <- message:
+	default:
+		s.drop(editor)
+	}
+}
+
+// broadcast sends the message to every editor but except
+func (s *collabSession) broadcast(except *domain.CollabEditor, message domain.CollabMessage) {
+	for editor := range s.editors {
+		if editor != except {
+			s.send(editor, message)
+		}
+	}
+}
+
+// close sends the message to every editor and drops them all
+func (s *collabSession) close(message domain.CollabMessage) {
+	s.closed = true
+	for editor := range s.editors {
+		s.send(editor, message)
+		s.drop(editor)
+	}
+}
+
+// drop removes the editor and closes its channel so its connection ends
+func (s *collabSession) drop(editor *domain.CollabEditor) {
+	if _, ok := s.editors[editor]; !ok {
+		return
+	}
+
+	delete(s.editors, editor)
+	close(editor.Send)
+}
+
+func NewNoteCollabUseCase(nr domain.NoteRepo, nu domain.NoteUsecase, uu domain.UserUsecase, su domain.ShareUsecase) domain.NoteCollabUsecase {
+	return &NoteCollabUseCaseImpl{
+		NoteRepo:     nr,
+		NoteUsecase:  nu,
+		UserUsecase:  uu,
+		ShareUsecase: su,
+		mu:           &sync.Mutex{},
+		sessions:     map[string]*collabSession{},
+	}
+}
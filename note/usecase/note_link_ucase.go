@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"gopkg.in/guregu/null.v4"
+)
+
+type NoteLinkUseCaseImpl struct {
+	NoteLinkRepo domain.NoteLinkRepo
+	NoteUsecase  domain.NoteUsecase
+	ShareUsecase domain.ShareUsecase
+}
+
+// GetNoteLinks implements domain.NoteLinkUsecase
+func (n NoteLinkUseCaseImpl) GetNoteLinks(ctx context.Context, userID int, shaID string) ([]domain.NoteLink, error) {
+	// make sure the user can see the note
+	note, err := n.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	// call repository
+	links, err := n.NoteLinkRepo.GetNoteLinks(ctx, note.ShaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.UserID == userID {
+		return links, nil
+	}
+
+	// the note is shared, the targets the user cannot see are shown as
+	// written in the note only
+	visible := n.visibleNotes(ctx, userID)
+	for i, v := range links {
+		if v.Note == nil {
+			continue
+		}
+
+		ok, err := visible(v.Note.ShaID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			links[i].TargetShaID = null.String{}
+			links[i].Note = nil
+		}
+	}
+
+	return links, nil
+}
+
+// GetNoteBacklinks implements domain.NoteLinkUsecase
+func (n NoteLinkUseCaseImpl) GetNoteBacklinks(ctx context.Context, userID int, shaID string) ([]domain.NoteLink, error) {
+	// make sure the user can see the note
+	note, err := n.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	// call repository
+	links, err := n.NoteLinkRepo.GetNoteBacklinks(ctx, note.ShaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.UserID == userID {
+		return links, nil
+	}
+
+	// the note is shared, only the backlinks of the notes the user can see are kept
+	visible := n.visibleNotes(ctx, userID)
+	backlinks := []domain.NoteLink{}
+	for _, v := range links {
+		ok, err := visible(v.SourceShaID)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			backlinks = append(backlinks, v)
+		}
+	}
+
+	return backlinks, nil
+}
+
+// GetNoteGraph implements domain.NoteLinkUsecase
+func (n NoteLinkUseCaseImpl) GetNoteGraph(ctx context.Context, userID int) (domain.NoteGraph, error) {
+	// call repository
+	return n.NoteLinkRepo.GetNoteGraph(ctx, userID)
+}
+
+// visibleNotes returns a check of the notes the user can see, each note is only checked once
+func (n NoteLinkUseCaseImpl) visibleNotes(ctx context.Context, userID int) func(shaID string) (bool, error) {
+	checked := map[string]bool{}
+
+	return func(shaID string) (bool, error) {
+		if ok, found := checked[shaID]; found {
+			return ok, nil
+		}
+
+		_, err := n.ShareUsecase.Authorize(ctx, userID, shaID, domain.ShareRoleViewer)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) && !errors.Is(err, domain.ErrForbidden) {
+			return false, err
+		}
+
+		checked[shaID] = err == nil
+		return err == nil, nil
+	}
+}
+
+func NewNoteLinkUseCase(nlr domain.NoteLinkRepo, nu domain.NoteUsecase, su domain.ShareUsecase) domain.NoteLinkUsecase {
+	return &NoteLinkUseCaseImpl{
+		NoteLinkRepo: nlr,
+		NoteUsecase:  nu,
+		ShareUsecase: su,
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+)
+
+// number of renders kept in memory before the cache is cleared
+const noteRenderCacheSize = 1000
+
+type NoteRenderUseCaseImpl struct {
+	NoteUsecase domain.NoteUsecase
+
+	mu    sync.Mutex
+	cache map[string]domain.NoteRender
+}
+
+// RenderNote implements domain.NoteRenderUsecase
+func (n *NoteRenderUseCaseImpl) RenderNote(ctx context.Context, userID int, shaID string) (domain.NoteRender, error) {
+	// make sure the note belongs to the user, this also gives the current updated_at
+	note, err := n.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return domain.NoteRender{}, err
+	}
+
+	if render, ok := n.cached(note); ok {
+		return render, nil
+	}
+
+	html, err := helpers.RenderMarkdown(note.Note.ValueOrZero())
+	if err != nil {
+		return domain.NoteRender{}, err
+	}
+
+	render := domain.NoteRender{
+		ShaID:     note.ShaID,
+		HTML:      html,
+		UpdatedAt: note.UpdatedAt,
+	}
+
+	n.store(render)
+	return render, nil
+}
+
+// a render is only used when it was made from the same updated_at
+func (n *NoteRenderUseCaseImpl) cached(note domain.Note) (domain.NoteRender, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	render, ok := n.cache[note.ShaID]
+	if !ok || !render.UpdatedAt.Equal(note.UpdatedAt) {
+		return domain.NoteRender{}, false
+	}
+
+	return render, true
+}
+
+func (n *NoteRenderUseCaseImpl) store(render domain.NoteRender) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// keep the memory bounded, the renders are cheap to make again
+	if _, ok := n.cache[render.ShaID]; !ok && len(n.cache) >= noteRenderCacheSize {
+		n.cache = map[string]domain.NoteRender{}
+	}
+
+	n.cache[render.ShaID] = render
+}
+
+func NewNoteRenderUseCase(nu domain.NoteUsecase) domain.NoteRenderUsecase {
+	return &NoteRenderUseCaseImpl{
+		NoteUsecase: nu,
+		cache:       map[string]domain.NoteRender{},
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/guregu/null.v4"
+)
+
+type NoteRevisionUseCaseImpl struct {
+	NoteRevisionRepo domain.NoteRevisionRepo
+	NoteUsecase      domain.NoteUsecase
+}
+
+// GetNoteRevisions implements domain.NoteRevisionUsecase
+func (n NoteRevisionUseCaseImpl) GetNoteRevisions(ctx context.Context, userID int, shaID string) ([]domain.NoteRevision, error) {
+	// make sure the note belongs to the user
+	note, err := n.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	// call repository
+	return n.NoteRevisionRepo.GetNoteRevisions(ctx, note.ShaID)
+}
+
+// FindNoteRevision implements domain.NoteRevisionUsecase
+func (n NoteRevisionUseCaseImpl) FindNoteRevision(ctx context.Context, userID int, shaID string, id int) (domain.NoteRevision, error) {
+	// make sure the note belongs to the user
+	note, err := n.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return domain.NoteRevision{}, err
+	}
+
+	// call repository
+	revision, err := n.NoteRevisionRepo.FindNoteRevision(ctx, note.ShaID, id)
+	if err == sql.ErrNoRows {
+		return domain.NoteRevision{}, fmt.Errorf("%w: revision not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.NoteRevision{}, err
+	}
+
+	return revision, nil
+}
+
+// DiffNoteRevisions implements domain.NoteRevisionUsecase
+func (n NoteRevisionUseCaseImpl) DiffNoteRevisions(ctx context.Context, userID int, shaID string, fromID int, toID int) (string, error) {
+	from, err := n.FindNoteRevision(ctx, userID, shaID, fromID)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := n.FindNoteRevision(ctx, userID, shaID, toID)
+	if err != nil {
+		return "", err
+	}
+
+	return helpers.UnifiedDiff(
+		fmt.Sprintf("revision %d", from.ID),
+		fmt.Sprintf("revision %d", to.ID),
+		from.Note.String,
+		to.Note.String,
+	)
+}
+
+// RestoreNoteRevision implements domain.NoteRevisionUsecase
+func (n NoteRevisionUseCaseImpl) RestoreNoteRevision(ctx context.Context, userID int, shaID string, id int) (domain.Note, error) {
+	revision, err := n.FindNoteRevision(ctx, userID, shaID, id)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	// saving the old content makes it the newest revision, a revision saved
+	// without content empties the note
+	return n.NoteUsecase.UpdateNote(ctx, userID, domain.Note{
+		ShaID: shaID,
+		Note:  null.StringFrom(revision.Note.String),
+	}, "")
+}
+
+func NewNoteRevisionUseCase(nrr domain.NoteRevisionRepo, nu domain.NoteUsecase) domain.NoteRevisionUsecase {
+	return &NoteRevisionUseCaseImpl{
+		NoteRevisionRepo: nrr,
+		NoteUsecase:      nu,
+	}
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+)
+
+type NoteUseCaseImpl struct {
+	NoteRepo     domain.NoteRepo
+	FileRepo     domain.FileRepo
+	FileUsecase  domain.FileUsecase
+	UserUsecase  domain.UserUsecase
+	ShareUsecase domain.ShareUsecase
+	IDGenerator  helpers.IDGenerator
+}
+
+// CreateNote implements domain.NoteUsecase
+func (n NoteUseCaseImpl) CreateNote(ctx context.Context, userID int, note domain.Note) (domain.Note, error) {
+	err := helpers.ValidateFileName(note.Name)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	// find the folder the note will be put in, empty folder means root.
+	// a note created in a shared folder belongs to the owner of the folder
+	parentPath := ""
+	ownerID := userID
+	if note.FolderShaID.ValueOrZero() != "" {
+		folder, err := n.ShareUsecase.Authorize(ctx, userID, note.FolderShaID.String, domain.ShareRoleEditor)
+		if err != nil {
+			return domain.Note{}, err
+		}
+
+		if folder.Type != domain.FileTypeFolder {
+			return domain.Note{}, fmt.Errorf("%w: folder_sha_id is not a folder", domain.ErrBadParamInput)
+		}
+
+		parentPath = folder.Path
+		ownerID = folder.UserID
+	}
+
+	settings, err := n.UserUsecase.GetUserSettings(ctx, ownerID)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	note.UserID = ownerID
+	note.Path = helpers.JoinPath(parentPath, note.Name)
+
+	// call repository, a new sha id is tried when the previous one is taken
+	var created domain.Note
+	err = n.IDGenerator.Generate(func(shaID string) error {
+		note.ShaID = shaID
+		created, err = n.NoteRepo.CreateNote(ctx, note, settings.NoteRevisionRetention)
+		return err
+	})
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return created, nil
+}
+
+// FindNote implements domain.NoteUsecase
+func (n NoteUseCaseImpl) FindNote(ctx context.Context, userID int, shaID string) (domain.Note, error) {
+	return n.findNote(ctx, userID, shaID, domain.ShareRoleViewer)
+}
+
+// findNote returns the note when the user has at least the role on it
+func (n NoteUseCaseImpl) findNote(ctx context.Context, userID int, shaID string, role string) (domain.Note, error) {
+	file, err := n.ShareUsecase.Authorize(ctx, userID, shaID, role)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	if file.Type != domain.FileTypeNote {
+		return domain.Note{}, fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	// call repository with the owner of the note
+	note, err := n.NoteRepo.FindNote(ctx, file.UserID, shaID)
+	if err == sql.ErrNoRows {
+		return domain.Note{}, fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return note, nil
+}
+
+// GetNotes implements domain.NoteUsecase
+func (n NoteUseCaseImpl) GetNotes(ctx context.Context, userID int, params domain.NoteListParams) ([]domain.Note, error) {
+	// drop the empty tags so "a,,b" filters by a and b
+	tags := []string{}
+	for _, tag := range params.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	params.Tags = tags
+
+	// the notes of a shared folder are listed as its owner
+	ownerID := userID
+	if params.FolderShaID != "" {
+		folder, err := n.ShareUsecase.Authorize(ctx, userID, params.FolderShaID, domain.ShareRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+
+		if folder.Type != domain.FileTypeFolder {
+			return nil, fmt.Errorf("%w: folder_sha_id is not a folder", domain.ErrBadParamInput)
+		}
+
+		ownerID = folder.UserID
+	}
+
+	if len(params.Tags) > 0 {
+		return n.NoteRepo.GetNotesByTags(ctx, ownerID, params)
+	}
+
+	if params.FolderShaID == "" {
+		return n.NoteRepo.GetNotes(ctx, ownerID)
+	}
+
+	return n.NoteRepo.GetNotesByFolder(ctx, ownerID, params.FolderShaID)
+}
+
+// UpdateNote implements domain.NoteUsecase
+func (n NoteUseCaseImpl) UpdateNote(ctx context.Context, userID int, note domain.Note, version string) (domain.Note, error) {
+	existing, err := n.findNote(ctx, userID, note.ShaID, domain.ShareRoleEditor)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	settings, err := n.UserUsecase.GetUserSettings(ctx, existing.UserID)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	note.UserID = existing.UserID
+
+	// the version check, the rename and the content are saved together
+	var result domain.Note
+	err = n.FileUsecase.Transaction(ctx, func(files domain.FileUsecase, repo domain.FileRepo) error {
+		locked, err := repo.FindNoteForUpdate(ctx, existing.UserID, note.ShaID)
+		if err != nil {
+			return err
+		}
+
+		err = helpers.CheckVersion(version, locked.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		// rename through the file usecase so the path stay unique and in sync
+		if note.Name != "" && note.Name != locked.Name {
+			_, _, err = files.RenameFile(ctx, existing.UserID, note.ShaID, note.Name, false)
+			if err != nil {
+				return err
+			}
+		}
+
+		// a note left out of the body keeps its content
+		if !note.Note.Valid {
+			result, err = repo.FindNoteForUpdate(ctx, existing.UserID, note.ShaID)
+			return err
+		}
+
+		// call repository
+		result, err = repo.SaveNote(ctx, note, settings.NoteRevisionRetention)
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Note{}, fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return result, nil
+}
+
+// DeleteNote implements domain.NoteUsecase
+func (n NoteUseCaseImpl) DeleteNote(ctx context.Context, userID int, shaID string, version string) error {
+	note, err := n.findNote(ctx, userID, shaID, domain.ShareRoleEditor)
+	if err != nil {
+		return err
+	}
+
+	// call repository, the note goes to the trash of its owner once its
+	// version is checked on the locked note
+	err = n.NoteRepo.DeleteNote(ctx, note.UserID, shaID, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	return err
+}
+
+// SearchNotes implements domain.NoteUsecase
+func (n NoteUseCaseImpl) SearchNotes(ctx context.Context, userID int, params domain.NoteSearchParams) ([]domain.NoteSearchResult, error) {
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Query == "" {
+		return nil, fmt.Errorf("%w: query cannot be empty", domain.ErrBadParamInput)
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	if params.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset cannot be negative", domain.ErrBadParamInput)
+	}
+
+	// make sure the user can read the folder, a shared folder is searched as its owner
+	ownerID := userID
+	if params.FolderShaID != "" {
+		folder, err := n.ShareUsecase.Authorize(ctx, userID, params.FolderShaID, domain.ShareRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+
+		if folder.Type != domain.FileTypeFolder {
+			return nil, fmt.Errorf("%w: folder_sha_id is not a folder", domain.ErrBadParamInput)
+		}
+
+		ownerID = folder.UserID
+	}
+
+	// call repository
+	return n.NoteRepo.SearchNotes(ctx, ownerID, params)
+}
+
+func NewNoteUseCase(nr domain.NoteRepo, fr domain.FileRepo, fu domain.FileUsecase, uu domain.UserUsecase, su domain.ShareUsecase, ig helpers.IDGenerator) domain.NoteUsecase {
+	return &NoteUseCaseImpl{
+		NoteRepo:     nr,
+		FileRepo:     fr,
+		FileUsecase:  fu,
+		UserUsecase:  uu,
+		ShareUsecase: su,
+		IDGenerator:  ig,
+	}
+}
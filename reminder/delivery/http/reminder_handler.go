@@ -0,0 +1,210 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+type ReminderHandler struct {
+	ReminderUsecase domain.ReminderUsecase
+}
+
+func NewReminderHandler(r *chi.Mux, u domain.ReminderUsecase) {
+	handler := &ReminderHandler{
+		ReminderUsecase: u,
+	}
+
+	// make group v1
+	r.Route("/reminder", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(middleware.MyMiddleware)
+			r.Get("/", helpers.RecoverWrap(handler.GetUserReminders))
+			r.Get("/notifications", helpers.RecoverWrap(handler.GetNotifications))
+			r.Put("/notifications/{id}/read", helpers.RecoverWrap(handler.ReadNotification))
+			r.Get("/note/{sha_id}", helpers.RecoverWrap(handler.GetNoteReminders))
+			r.Put("/note/{sha_id}", helpers.RecoverWrap(handler.SetNoteReminders))
+			r.Delete("/note/{sha_id}", helpers.RecoverWrap(handler.DeleteNoteReminders))
+		})
+	})
+
+}
+
+func (h ReminderHandler) GetUserReminders(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	reminders, err := h.ReminderUsecase.GetUserReminders(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "reminders found",
+		Data: map[string]interface{}{
+			"reminders": reminders,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h ReminderHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	notifications, err := h.ReminderUsecase.GetNotifications(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "notifications found",
+		Data: map[string]interface{}{
+			"notifications": notifications,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h ReminderHandler) ReadNotification(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	err = h.ReminderUsecase.ReadNotification(r.Context(), credentials.ID, id)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "notification read",
+		Data:    nil,
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h ReminderHandler) GetNoteReminders(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	reminders, err := h.ReminderUsecase.GetNoteReminders(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "reminders found",
+		Data: map[string]interface{}{
+			"reminders": reminders,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h ReminderHandler) SetNoteReminders(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request body
+	var params domain.ReminderParams
+
+	err = json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	reminders, err := h.ReminderUsecase.SetNoteReminders(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), params)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "reminders saved",
+		Data: map[string]interface{}{
+			"reminders": reminders,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h ReminderHandler) DeleteNoteReminders(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	err = h.ReminderUsecase.DeleteNoteReminders(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "reminders deleted",
+		Data:    nil,
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
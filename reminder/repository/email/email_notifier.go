@@ -0,0 +1,157 @@
+package reminder_repo_email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// time a mail has to be sent when the context has no deadline
+const sendTimeout = 30 * time.Second
+
+type EmailConfig struct {
+	Host string
+	Port string
+	// Username and Password are left empty when the server needs no login
+	Username string
+	Password string
+	// From is the address the reminders are sent from
+	From string
+}
+
+type emailNotifier struct {
+	Config EmailConfig
+}
+
+// Name implements domain.Notifier
+func (e emailNotifier) Name() string {
+	return "email"
+}
+
+// Notify implements domain.Notifier
+func (e emailNotifier) Notify(ctx context.Context, notice domain.ReminderNotice) error {
+	// the reminder is still sent through the other channels
+	if !notice.User.Email.Valid || notice.User.Email.String == "" {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if e.Config.Username != "" {
+		auth = smtp.PlainAuth("", e.Config.Username, e.Config.Password, e.Config.Host)
+	}
+
+	to := notice.User.Email.String
+
+	return e.sendMail(ctx, auth, to, message(e.Config.From, to, notice))
+}
+
+// sendMail does what smtp.SendMail does on a connection that is given up
+// when ctx is done or after sendTimeout
+func (e emailNotifier) sendMail(ctx context.Context, auth smtp.Auth, to string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(e.Config.Host, e.Config.Port))
+	if err != nil {
+		return err
+	}
+
+	// the deadline stops a server that stops answering, closing the
+	// connection stops the mail when ctx is canceled
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	c, err := smtp.NewClient(conn, e.Config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	err = c.Hello("localhost")
+	if err != nil {
+		return err
+	}
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		err = c.StartTLS(&tls.Config{ServerName: e.Config.Host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp server %s does not support AUTH", e.Config.Host)
+		}
+
+		err = c.Auth(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.Mail(e.Config.From)
+	if err != nil {
+		return err
+	}
+
+	err = c.Rcpt(to)
+	if err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// message writes the reminder as a plain text mail
+func message(from string, to string, notice domain.ReminderNotice) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notice.Title()))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(notice.Body())
+	b.WriteString("\r\n")
+
+	return b.Bytes()
+}
+
+func NewEmailNotifier(config EmailConfig) domain.Notifier {
+	if config.Port == "" {
+		config.Port = "587"
+	}
+
+	return &emailNotifier{config}
+}
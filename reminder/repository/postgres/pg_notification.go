@@ -0,0 +1,110 @@
+package reminder_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresNotificationRepo struct {
+	Source sqlcpg.Querier
+}
+
+// CreateNotification implements domain.NotificationRepo
+func (p postgresNotificationRepo) CreateNotification(ctx context.Context, notification domain.Notification) (domain.Notification, error) {
+	data, err := p.Source.CreateNotification(ctx, sqlcpg.CreateNotificationParams{
+		UserID:    int32(notification.UserID),
+		FileShaID: notification.FileShaID,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return domain.Notification{}, err
+	}
+
+	return toDomainNotification(data), nil
+}
+
+// GetNotifications implements domain.NotificationRepo
+func (p postgresNotificationRepo) GetNotifications(ctx context.Context, userID int, limit int) ([]domain.Notification, error) {
+	data, err := p.Source.GetNotifications(ctx, sqlcpg.GetNotificationsParams{
+		UserID: int32(userID),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := []domain.Notification{}
+	for _, v := range data {
+		notifications = append(notifications, toDomainNotification(v))
+	}
+
+	return notifications, nil
+}
+
+// ReadNotification implements domain.NotificationRepo
+func (p postgresNotificationRepo) ReadNotification(ctx context.Context, userID int, id int) error {
+	rows, err := p.Source.ReadNotification(ctx, sqlcpg.ReadNotificationParams{
+		ID:     int32(id),
+		UserID: int32(userID),
+		ReadAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// inAppNotifier keeps the reminders as notifications shown in the app
+type inAppNotifier struct {
+	Source sqlcpg.Querier
+}
+
+// Name implements domain.Notifier
+func (n inAppNotifier) Name() string {
+	return "in_app"
+}
+
+// Notify implements domain.Notifier
+func (n inAppNotifier) Notify(ctx context.Context, notice domain.ReminderNotice) error {
+	_, err := n.Source.CreateNotification(ctx, sqlcpg.CreateNotificationParams{
+		UserID:    int32(notice.Reminder.UserID),
+		FileShaID: notice.Reminder.FileShaID,
+		Title:     notice.Title(),
+		Body:      notice.Body(),
+		CreatedAt: time.Now(),
+	})
+
+	return err
+}
+
+func toDomainNotification(data sqlcpg.Notification) domain.Notification {
+	return domain.Notification{
+		ID:        int(data.ID),
+		UserID:    int(data.UserID),
+		FileShaID: data.FileShaID,
+		Title:     data.Title,
+		Body:      data.Body,
+		ReadAt:    null.Time{NullTime: data.ReadAt},
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func NewPostgresNotificationRepo(source sqlcpg.Querier) domain.NotificationRepo {
+	return &postgresNotificationRepo{source}
+}
+
+func NewInAppNotifier(source sqlcpg.Querier) domain.Notifier {
+	return &inAppNotifier{source}
+}
@@ -0,0 +1,176 @@
+package reminder_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresReminderRepo struct {
+	DB     *sql.DB
+	Source sqlcpg.Querier
+}
+
+// SetNoteReminders implements domain.ReminderRepo
+func (p postgresReminderRepo) SetNoteReminders(ctx context.Context, shaID string, userID int, reminders []domain.Reminder) ([]domain.Reminder, error) {
+	result := []domain.Reminder{}
+
+	err := helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		err := q.DeleteNoteReminders(ctx, sqlcpg.DeleteNoteRemindersParams{
+			FileShaID: shaID,
+			UserID:    int32(userID),
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, v := range reminders {
+			data, err := q.CreateReminder(ctx, sqlcpg.CreateReminderParams{
+				FileShaID:     shaID,
+				UserID:        int32(userID),
+				DueAt:         v.DueAt,
+				OffsetMinutes: int32(v.Offset),
+				RemindAt:      v.RemindAt,
+				CreatedAt:     now,
+			})
+			if err != nil {
+				return err
+			}
+
+			result = append(result, toDomainReminder(data))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetNoteReminders implements domain.ReminderRepo
+func (p postgresReminderRepo) GetNoteReminders(ctx context.Context, shaID string, userID int) ([]domain.Reminder, error) {
+	data, err := p.Source.GetNoteReminders(ctx, sqlcpg.GetNoteRemindersParams{
+		FileShaID: shaID,
+		UserID:    int32(userID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := []domain.Reminder{}
+	for _, v := range data {
+		reminders = append(reminders, toDomainReminder(v))
+	}
+
+	return reminders, nil
+}
+
+// GetUserReminders implements domain.ReminderRepo
+func (p postgresReminderRepo) GetUserReminders(ctx context.Context, userID int) ([]domain.Reminder, error) {
+	data, err := p.Source.GetUserReminders(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := []domain.Reminder{}
+	for _, v := range data {
+		reminder := toDomainReminder(sqlcpg.Reminder{
+			ID:                v.ID,
+			FileShaID:         v.FileShaID,
+			UserID:            v.UserID,
+			DueAt:             v.DueAt,
+			OffsetMinutes:     v.OffsetMinutes,
+			RemindAt:          v.RemindAt,
+			SentAt:            v.SentAt,
+			ClaimedUntil:      v.ClaimedUntil,
+			Attempts:          v.Attempts,
+			LastError:         v.LastError,
+			CreatedAt:         v.CreatedAt,
+			DeliveredChannels: v.DeliveredChannels,
+		})
+		reminder.Name = v.Name
+		reminder.Path = v.Path
+
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
+
+// DeleteNoteReminders implements domain.ReminderRepo
+func (p postgresReminderRepo) DeleteNoteReminders(ctx context.Context, shaID string, userID int) error {
+	return p.Source.DeleteNoteReminders(ctx, sqlcpg.DeleteNoteRemindersParams{
+		FileShaID: shaID,
+		UserID:    int32(userID),
+	})
+}
+
+// ClaimDueReminders implements domain.ReminderRepo
+func (p postgresReminderRepo) ClaimDueReminders(ctx context.Context, now time.Time, claimedUntil time.Time, count int) ([]domain.Reminder, error) {
+	// the rows locked by another scheduler are skipped instead of waited for
+	data, err := p.Source.ClaimDueReminders(ctx, sqlcpg.ClaimDueRemindersParams{
+		ClaimedUntil: sql.NullTime{Time: claimedUntil, Valid: true},
+		Now:          now,
+		MaxAttempts:  domain.MaxReminderAttempts,
+		MaxCount:     int32(count),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := []domain.Reminder{}
+	for _, v := range data {
+		reminders = append(reminders, toDomainReminder(v))
+	}
+
+	return reminders, nil
+}
+
+// MarkReminderSent implements domain.ReminderRepo
+func (p postgresReminderRepo) MarkReminderSent(ctx context.Context, id int, sentAt time.Time) error {
+	return p.Source.MarkReminderSent(ctx, sqlcpg.MarkReminderSentParams{
+		ID:     int32(id),
+		SentAt: sql.NullTime{Time: sentAt, Valid: true},
+	})
+}
+
+// RetryReminder implements domain.ReminderRepo
+func (p postgresReminderRepo) RetryReminder(ctx context.Context, id int, retryAt time.Time, reason string, delivered []string) error {
+	return p.Source.RetryReminder(ctx, sqlcpg.RetryReminderParams{
+		ID:                int32(id),
+		ClaimedUntil:      sql.NullTime{Time: retryAt, Valid: true},
+		LastError:         reason,
+		DeliveredChannels: delivered,
+	})
+}
+
+func toDomainReminder(data sqlcpg.Reminder) domain.Reminder {
+	return domain.Reminder{
+		ID:                int(data.ID),
+		FileShaID:         data.FileShaID,
+		UserID:            int(data.UserID),
+		DueAt:             data.DueAt,
+		Offset:            int(data.OffsetMinutes),
+		RemindAt:          data.RemindAt,
+		SentAt:            null.Time{NullTime: data.SentAt},
+		Attempts:          int(data.Attempts),
+		LastError:         data.LastError,
+		CreatedAt:         data.CreatedAt,
+		DeliveredChannels: data.DeliveredChannels,
+	}
+}
+
+func NewPostgresReminderRepo(db *sql.DB, source sqlcpg.Querier) domain.ReminderRepo {
+	return &postgresReminderRepo{db, source}
+}
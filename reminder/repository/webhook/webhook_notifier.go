@@ -0,0 +1,91 @@
+package reminder_repo_webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// header holding the hex hmac sha-256 of the body made with the secret
+const signatureHeader = "X-Notes-Signature"
+
+type WebhookConfig struct {
+	URL string
+	// Secret signs the body when not empty
+	Secret string
+}
+
+type webhookNotifier struct {
+	Config WebhookConfig
+	Client *http.Client
+}
+
+// Name implements domain.Notifier
+func (w webhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Notify implements domain.Notifier
+func (w webhookNotifier) Notify(ctx context.Context, notice domain.ReminderNotice) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"event":    "reminder.due",
+		"title":    notice.Title(),
+		"body":     notice.Body(),
+		"reminder": notice.Reminder,
+		"note": map[string]interface{}{
+			"sha_id": notice.Note.ShaID,
+			"name":   notice.Note.Name,
+			"path":   notice.Note.Path,
+		},
+		"user": map[string]interface{}{
+			"id":       notice.User.ID,
+			"username": notice.User.Username,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if w.Config.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Config.Secret))
+		mac.Write(body)
+		req.Header.Set(signatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	res, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// the connection is reused only when the body is read
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+
+	return nil
+}
+
+func NewWebhookNotifier(config WebhookConfig) domain.Notifier {
+	return &webhookNotifier{
+		Config: config,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+const (
+	// time a reminder has to be sent through every notifier, longer than
+	// the slowest notifier, the email one gives up after 30 seconds
+	reminderSendTimeout = time.Minute
+	// reminders claimed by a scheduler at once
+	reminderBatch = 4
+	// time a scheduler has to send the reminders it claimed before another
+	// scheduler can take them, a whole batch sent at the timeout still fits
+	// with a minute left to save the results
+	reminderClaim = (reminderBatch + 1) * reminderSendTimeout
+	// notifications listed at once
+	notificationLimit = 100
+)
+
+type ReminderUseCaseImpl struct {
+	ReminderRepo     domain.ReminderRepo
+	NotificationRepo domain.NotificationRepo
+	Notifiers        []domain.Notifier
+	UserUsecase      domain.UserUsecase
+	ShareUsecase     domain.ShareUsecase
+}
+
+// SetNoteReminders implements domain.ReminderUsecase
+func (r ReminderUseCaseImpl) SetNoteReminders(ctx context.Context, userID int, shaID string, params domain.ReminderParams) ([]domain.Reminder, error) {
+	file, err := r.findNote(ctx, userID, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.DueAt.IsZero() {
+		return nil, fmt.Errorf("%w: due_at cannot be empty", domain.ErrBadParamInput)
+	}
+
+	// the timestamps are saved in the time zone of the server
+	now := time.Now()
+	dueAt := params.DueAt.Local()
+	if !dueAt.After(now) {
+		return nil, fmt.Errorf("%w: due_at has already passed", domain.ErrBadParamInput)
+	}
+
+	// one reminder at the due time unless given
+	offsets := params.Offsets
+	if len(offsets) == 0 {
+		offsets = []int{0}
+	}
+
+	if len(offsets) > domain.MaxReminderOffsets {
+		return nil, fmt.Errorf("%w: at most %d offsets", domain.ErrBadParamInput, domain.MaxReminderOffsets)
+	}
+
+	seen := map[int]bool{}
+	reminders := []domain.Reminder{}
+	for _, offset := range offsets {
+		if offset < 0 || offset > domain.MaxReminderOffset {
+			return nil, fmt.Errorf("%w: offset must be between 0 and %d minutes", domain.ErrBadParamInput, domain.MaxReminderOffset)
+		}
+
+		if seen[offset] {
+			continue
+		}
+		seen[offset] = true
+
+		// a reminder whose time has passed would be sent at once
+		remindAt := dueAt.Add(-time.Duration(offset) * time.Minute)
+		if remindAt.Before(now) {
+			continue
+		}
+
+		reminders = append(reminders, domain.Reminder{
+			DueAt:    dueAt,
+			Offset:   offset,
+			RemindAt: remindAt,
+		})
+	}
+
+	sort.Slice(reminders, func(i, j int) bool {
+		return reminders[i].RemindAt.Before(reminders[j].RemindAt)
+	})
+
+	// call repository
+	return r.ReminderRepo.SetNoteReminders(ctx, file.ShaID, userID, reminders)
+}
+
+// GetNoteReminders implements domain.ReminderUsecase
+func (r ReminderUseCaseImpl) GetNoteReminders(ctx context.Context, userID int, shaID string) ([]domain.Reminder, error) {
+	file, err := r.findNote(ctx, userID, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	// call repository
+	return r.ReminderRepo.GetNoteReminders(ctx, file.ShaID, userID)
+}
+
+// GetUserReminders implements domain.ReminderUsecase
+func (r ReminderUseCaseImpl) GetUserReminders(ctx context.Context, userID int) ([]domain.Reminder, error) {
+	// call repository
+	return r.ReminderRepo.GetUserReminders(ctx, userID)
+}
+
+// DeleteNoteReminders implements domain.ReminderUsecase
+func (r ReminderUseCaseImpl) DeleteNoteReminders(ctx context.Context, userID int, shaID string) error {
+	file, err := r.findNote(ctx, userID, shaID)
+	if err != nil {
+		return err
+	}
+
+	// call repository
+	return r.ReminderRepo.DeleteNoteReminders(ctx, file.ShaID, userID)
+}
+
+// GetNotifications implements domain.ReminderUsecase
+func (r ReminderUseCaseImpl) GetNotifications(ctx context.Context, userID int) ([]domain.Notification, error) {
+	// call repository
+	return r.NotificationRepo.GetNotifications(ctx, userID, notificationLimit)
+}
+
+// ReadNotification implements domain.ReminderUsecase
+func (r ReminderUseCaseImpl) ReadNotification(ctx context.Context, userID int, id int) error {
+	// call repository
+	err := r.NotificationRepo.ReadNotification(ctx, userID, id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: notification not found", domain.ErrNotFound)
+	}
+
+	return err
+}
+
+// SendDueReminders implements domain.ReminderUsecase
+func (r ReminderUseCaseImpl) SendDueReminders(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+
+	for {
+		// call repository, the claimed reminders are skipped by the other schedulers,
+		// the claim starts when the batch is taken and not when the run started
+		reminders, err := r.ReminderRepo.ClaimDueReminders(ctx, now, time.Now().Add(reminderClaim), reminderBatch)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, reminder := range reminders {
+			sendCtx, cancel := context.WithTimeout(ctx, reminderSendTimeout)
+			delivered, err := r.sendReminder(sendCtx, reminder)
+			cancel()
+			if err != nil {
+				// tried again later on the failed channels, waiting longer after each attempt
+				retryAt := time.Now().Add(time.Duration(reminder.Attempts) * time.Minute)
+				err = r.ReminderRepo.RetryReminder(ctx, reminder.ID, retryAt, err.Error(), delivered)
+				if err != nil {
+					return sent, err
+				}
+
+				continue
+			}
+
+			err = r.ReminderRepo.MarkReminderSent(ctx, reminder.ID, time.Now())
+			if err != nil {
+				return sent, err
+			}
+
+			sent++
+		}
+
+		if len(reminders) < reminderBatch {
+			return sent, nil
+		}
+	}
+}
+
+// sendReminder tells the user of the reminder through every notifier it has
+// not been delivered through yet and returns the channels it now has been
+func (r ReminderUseCaseImpl) sendReminder(ctx context.Context, reminder domain.Reminder) ([]string, error) {
+	delivered := append([]string{}, reminder.DeliveredChannels...)
+
+	// the user may have lost the access to the note since
+	note, err := r.ShareUsecase.Authorize(ctx, reminder.UserID, reminder.FileShaID, domain.ShareRoleViewer)
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+		return delivered, nil
+	}
+
+	if err != nil {
+		return delivered, err
+	}
+
+	user, err := r.UserUsecase.FindUser(ctx, reminder.UserID)
+	if err != nil {
+		return delivered, err
+	}
+
+	notice := domain.ReminderNotice{
+		Reminder: reminder,
+		User:     user,
+		Note:     note,
+	}
+
+	// a failed channel does not send the reminder again through the others
+	var errs []error
+	for _, notifier := range r.Notifiers {
+		if slices.Contains(reminder.DeliveredChannels, notifier.Name()) {
+			continue
+		}
+
+		err = notifier.Notify(ctx, notice)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+			continue
+		}
+
+		delivered = append(delivered, notifier.Name())
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+// findNote checks the user can see the note the reminders are set on
+func (r ReminderUseCaseImpl) findNote(ctx context.Context, userID int, shaID string) (domain.File, error) {
+	file, err := r.ShareUsecase.Authorize(ctx, userID, shaID, domain.ShareRoleViewer)
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	if file.Type != domain.FileTypeNote {
+		return domain.File{}, fmt.Errorf("%w: note not found", domain.ErrNotFound)
+	}
+
+	return file, nil
+}
+
+func NewReminderUseCase(rr domain.ReminderRepo, nr domain.NotificationRepo, notifiers []domain.Notifier, uu domain.UserUsecase, su domain.ShareUsecase) domain.ReminderUsecase {
+	return &ReminderUseCaseImpl{
+		ReminderRepo:     rr,
+		NotificationRepo: nr,
+		Notifiers:        notifiers,
+		UserUsecase:      uu,
+		ShareUsecase:     su,
+	}
+}
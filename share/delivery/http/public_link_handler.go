@@ -0,0 +1,179 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+type PublicLinkHandler struct {
+	PublicLinkUsecase domain.PublicLinkUsecase
+}
+
+func NewPublicLinkHandler(r *chi.Mux, u domain.PublicLinkUsecase) {
+	handler := &PublicLinkHandler{
+		PublicLinkUsecase: u,
+	}
+
+	// make group v1
+	r.Route("/public", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			// managing the links needs an account
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.MyMiddleware)
+				r.Get("/links", helpers.RecoverWrap(handler.GetPublicLinks))
+				r.Post("/links", helpers.RecoverWrap(handler.CreatePublicLink))
+				r.Delete("/links/{link_id}", helpers.RecoverWrap(handler.RevokePublicLink))
+			})
+
+			// opening a link does not
+			r.Get("/{token}", helpers.RecoverWrap(handler.ViewPublicLink))
+			r.Get("/{token}/{sha_id}", helpers.RecoverWrap(handler.ViewPublicLink))
+		})
+	})
+
+}
+
+func (p PublicLinkHandler) GetPublicLinks(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	links, err := p.PublicLinkUsecase.GetPublicLinks(r.Context(), credentials.ID, r.URL.Query().Get("sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "links found",
+		Data: map[string]interface{}{
+			"links": links,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (p PublicLinkHandler) CreatePublicLink(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form body json
+	req := struct {
+		ShaID     string    `json:"sha_id"`
+		ExpiresAt null.Time `json:"expires_at"`
+		Password  string    `json:"password"`
+	}{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	link, err := p.PublicLinkUsecase.CreatePublicLink(r.Context(), credentials.ID, domain.PublicLinkParams{
+		ShaID:     req.ShaID,
+		ExpiresAt: req.ExpiresAt,
+		Password:  req.Password,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "link created",
+		Data: map[string]interface{}{
+			"link": link,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (p PublicLinkHandler) RevokePublicLink(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	linkID, err := strconv.Atoi(chi.URLParam(r, "link_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	err = p.PublicLinkUsecase.RevokePublicLink(r.Context(), credentials.ID, linkID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "link revoked",
+		Data:    nil,
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (p PublicLinkHandler) ViewPublicLink(w http.ResponseWriter, r *http.Request) {
+	// the password of a protected link comes in a header so it stays out of the logs
+	view, err := p.PublicLinkUsecase.ViewPublicLink(
+		r.Context(),
+		chi.URLParam(r, "token"),
+		r.Header.Get("X-Link-Password"),
+		chi.URLParam(r, "sha_id"),
+	)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	// format=html returns the bare document of a note
+	if r.URL.Query().Get("format") == "html" && view.File.Type == domain.FileTypeNote {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Last-Modified", view.File.UpdatedAt.Time.UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(view.HTML))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "link opened",
+		Data: map[string]interface{}{
+			"view": view,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/user/delivery/http/middleware"
+)
+
+type ShareHandler struct {
+	ShareUsecase domain.ShareUsecase
+}
+
+func NewShareHandler(r *chi.Mux, u domain.ShareUsecase) {
+	handler := &ShareHandler{
+		ShareUsecase: u,
+	}
+
+	// make group v1
+	r.Route("/share", func(r chi.Router) {
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(middleware.MyMiddleware)
+			r.Get("/", helpers.RecoverWrap(handler.GetSharedFiles))
+			r.Get("/{sha_id}", helpers.RecoverWrap(handler.GetFileShares))
+			r.Put("/{sha_id}", helpers.RecoverWrap(handler.ShareFile))
+			r.Delete("/{sha_id}/{user_id}", helpers.RecoverWrap(handler.UnshareFile))
+		})
+	})
+
+}
+
+func (s ShareHandler) GetSharedFiles(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	files, err := s.ShareUsecase.GetSharedFiles(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "shared files found",
+		Data: map[string]interface{}{
+			"files": files,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s ShareHandler) GetFileShares(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	shares, err := s.ShareUsecase.GetFileShares(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"))
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "shares found",
+		Data: map[string]interface{}{
+			"shares": shares,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s ShareHandler) ShareFile(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request form body json
+	req := struct {
+		Username string `json:"username"`
+		Role     string `json:"role"`
+	}{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	share, err := s.ShareUsecase.ShareFile(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), req.Username, req.Role)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "file shared",
+		Data: map[string]interface{}{
+			"share": share,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s ShareHandler) UnshareFile(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	err = s.ShareUsecase.UnshareFile(r.Context(), credentials.ID, chi.URLParam(r, "sha_id"), userID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "file unshared",
+		Data:    nil,
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
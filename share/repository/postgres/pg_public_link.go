@@ -0,0 +1,116 @@
+package share_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresPublicLinkRepo struct {
+	Source sqlcpg.Querier
+}
+
+// CreatePublicLink implements domain.PublicLinkRepo
+func (p postgresPublicLinkRepo) CreatePublicLink(ctx context.Context, link domain.PublicLink) (domain.PublicLink, error) {
+	now := time.Now()
+
+	data, err := p.Source.CreatePublicLink(ctx, sqlcpg.CreatePublicLinkParams{
+		Token:     link.Token,
+		FileShaID: link.FileShaID,
+		UserID:    int32(link.UserID),
+		Password:  sql.NullString{String: link.Password, Valid: link.Password != ""},
+		ExpiresAt: link.ExpiresAt.NullTime,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if helpers.IsUniqueViolation(err, "public_links_token") {
+		return domain.PublicLink{}, domain.ErrDuplicateID
+	}
+
+	if err != nil {
+		return domain.PublicLink{}, err
+	}
+
+	return toDomainPublicLink(data), nil
+}
+
+// FindPublicLink implements domain.PublicLinkRepo
+func (p postgresPublicLinkRepo) FindPublicLink(ctx context.Context, token string) (domain.PublicLink, error) {
+	data, err := p.Source.FindPublicLink(ctx, token)
+	if err != nil {
+		return domain.PublicLink{}, err
+	}
+
+	return toDomainPublicLink(data), nil
+}
+
+// GetPublicLinks implements domain.PublicLinkRepo
+func (p postgresPublicLinkRepo) GetPublicLinks(ctx context.Context, userID int, shaID string) ([]domain.PublicLink, error) {
+	data, err := p.Source.GetPublicLinks(ctx, sqlcpg.GetPublicLinksParams{
+		FileShaID: shaID,
+		UserID:    int32(userID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	links := []domain.PublicLink{}
+	for _, v := range data {
+		links = append(links, toDomainPublicLink(v))
+	}
+
+	return links, nil
+}
+
+// RevokePublicLink implements domain.PublicLinkRepo
+func (p postgresPublicLinkRepo) RevokePublicLink(ctx context.Context, userID int, id int) error {
+	rows, err := p.Source.RevokePublicLink(ctx, sqlcpg.RevokePublicLinkParams{
+		ID:        int32(id),
+		UserID:    int32(userID),
+		RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// IncrementPublicLinkViews implements domain.PublicLinkRepo
+func (p postgresPublicLinkRepo) IncrementPublicLinkViews(ctx context.Context, id int) (int, error) {
+	count, err := p.Source.IncrementPublicLinkViews(ctx, int32(id))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func toDomainPublicLink(data sqlcpg.PublicLink) domain.PublicLink {
+	return domain.PublicLink{
+		ID:          int(data.ID),
+		Token:       data.Token,
+		FileShaID:   data.FileShaID,
+		UserID:      int(data.UserID),
+		Password:    data.Password.String,
+		HasPassword: data.Password.Valid,
+		ExpiresAt:   null.Time{NullTime: data.ExpiresAt},
+		RevokedAt:   null.Time{NullTime: data.RevokedAt},
+		ViewCount:   int(data.ViewCount),
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func NewPostgresPublicLinkRepo(source sqlcpg.Querier) domain.PublicLinkRepo {
+	return &postgresPublicLinkRepo{source}
+}
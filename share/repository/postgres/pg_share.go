@@ -0,0 +1,147 @@
+package share_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresShareRepo struct {
+	DB     *sql.DB
+	Source sqlcpg.Querier
+}
+
+// UpsertShare implements domain.ShareRepo
+func (p postgresShareRepo) UpsertShare(ctx context.Context, share domain.Share) (domain.Share, error) {
+	now := time.Now()
+
+	data, err := p.Source.UpsertShare(ctx, sqlcpg.UpsertShareParams{
+		FileShaID: share.FileShaID,
+		OwnerID:   int32(share.OwnerID),
+		UserID:    int32(share.UserID),
+		Role:      share.Role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return domain.Share{}, err
+	}
+
+	created := toDomainShare(data)
+	created.Username = share.Username
+
+	return created, nil
+}
+
+// GetFileShares implements domain.ShareRepo
+func (p postgresShareRepo) GetFileShares(ctx context.Context, shaID string) ([]domain.Share, error) {
+	data, err := p.Source.GetFileShares(ctx, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := []domain.Share{}
+	for _, v := range data {
+		share := toDomainShare(sqlcpg.Share{
+			ID:        v.ID,
+			FileShaID: v.FileShaID,
+			OwnerID:   v.OwnerID,
+			UserID:    v.UserID,
+			Role:      v.Role,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		})
+		share.Username = v.Username
+
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// GetUserShares implements domain.ShareRepo
+func (p postgresShareRepo) GetUserShares(ctx context.Context, userID int, shaIDs []string) ([]domain.Share, error) {
+	data, err := p.Source.GetUserShares(ctx, sqlcpg.GetUserSharesParams{
+		UserID: int32(userID),
+		ShaIds: shaIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	shares := []domain.Share{}
+	for _, v := range data {
+		shares = append(shares, toDomainShare(v))
+	}
+
+	return shares, nil
+}
+
+// GetSharedFiles implements domain.ShareRepo
+func (p postgresShareRepo) GetSharedFiles(ctx context.Context, userID int) ([]domain.SharedFile, error) {
+	data, err := p.Source.GetSharedFiles(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []domain.SharedFile{}
+	for _, v := range data {
+		files = append(files, domain.SharedFile{
+			File: domain.File{
+				ID:          int(v.ID),
+				FolderShaID: null.NewString(v.FolderShaID, v.FolderShaID != ""),
+				ShaID:       v.ShaID,
+				UserID:      int(v.UserID),
+				Path:        v.Path,
+				Name:        v.Name,
+				Type:        v.Type,
+				CreatedAt:   null.TimeFrom(v.CreatedAt),
+				UpdatedAt:   null.TimeFrom(v.UpdatedAt),
+			},
+			Role:  v.Role,
+			Owner: v.Username,
+		})
+	}
+
+	return files, nil
+}
+
+// DeleteShare implements domain.ShareRepo
+func (p postgresShareRepo) DeleteShare(ctx context.Context, shaID string, userID int) error {
+	rows, err := p.Source.DeleteShare(ctx, sqlcpg.DeleteShareParams{
+		FileShaID: shaID,
+		UserID:    int32(userID),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func toDomainShare(data sqlcpg.Share) domain.Share {
+	return domain.Share{
+		ID:        int(data.ID),
+		FileShaID: data.FileShaID,
+		OwnerID:   int(data.OwnerID),
+		UserID:    int(data.UserID),
+		Role:      data.Role,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+}
+
+func NewPostgresShareRepo(db *sql.DB, source sqlcpg.Querier) domain.ShareRepo {
+	return &postgresShareRepo{
+		DB:     db,
+		Source: source,
+	}
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/guregu/null.v4"
+)
+
+type PublicLinkUseCaseImpl struct {
+	PublicLinkRepo    domain.PublicLinkRepo
+	FileRepo          domain.FileRepo
+	ShareUsecase      domain.ShareUsecase
+	FolderUsecase     domain.FolderUsecase
+	NoteUsecase       domain.NoteUsecase
+	NoteRenderUsecase domain.NoteRenderUsecase
+	IDGenerator       helpers.IDGenerator
+}
+
+// CreatePublicLink implements domain.PublicLinkUsecase
+func (p PublicLinkUseCaseImpl) CreatePublicLink(ctx context.Context, userID int, params domain.PublicLinkParams) (domain.PublicLink, error) {
+	// only the owner publishes a file
+	file, err := p.ShareUsecase.Authorize(ctx, userID, params.ShaID, domain.ShareRoleOwner)
+	if err != nil {
+		return domain.PublicLink{}, err
+	}
+
+	if params.ExpiresAt.Valid && !params.ExpiresAt.Time.After(time.Now()) {
+		return domain.PublicLink{}, fmt.Errorf("%w: expires_at must be in the future", domain.ErrBadParamInput)
+	}
+
+	link := domain.PublicLink{
+		FileShaID: file.ShaID,
+		UserID:    file.UserID,
+		ExpiresAt: params.ExpiresAt,
+	}
+
+	if params.Password != "" {
+		link.Password, err = helpers.ArgonHash(params.Password)
+		if err != nil {
+			return domain.PublicLink{}, err
+		}
+	}
+
+	// call repository, a new token is tried when the previous one is taken
+	var created domain.PublicLink
+	err = p.IDGenerator.Generate(func(token string) error {
+		link.Token = token
+		created, err = p.PublicLinkRepo.CreatePublicLink(ctx, link)
+		return err
+	})
+	if err != nil {
+		return domain.PublicLink{}, err
+	}
+
+	return signLink(created), nil
+}
+
+// GetPublicLinks implements domain.PublicLinkUsecase
+func (p PublicLinkUseCaseImpl) GetPublicLinks(ctx context.Context, userID int, shaID string) ([]domain.PublicLink, error) {
+	file, err := p.ShareUsecase.Authorize(ctx, userID, shaID, domain.ShareRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// call repository
+	links, err := p.PublicLinkRepo.GetPublicLinks(ctx, file.UserID, file.ShaID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range links {
+		links[i] = signLink(links[i])
+	}
+
+	return links, nil
+}
+
+// RevokePublicLink implements domain.PublicLinkUsecase
+func (p PublicLinkUseCaseImpl) RevokePublicLink(ctx context.Context, userID int, id int) error {
+	// call repository
+	err := p.PublicLinkRepo.RevokePublicLink(ctx, userID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: link not found", domain.ErrNotFound)
+	}
+
+	return err
+}
+
+// ViewPublicLink implements domain.PublicLinkUsecase
+func (p PublicLinkUseCaseImpl) ViewPublicLink(ctx context.Context, token string, password string, shaID string) (domain.PublicView, error) {
+	link, err := p.openLink(ctx, token, password)
+	if err != nil {
+		return domain.PublicView{}, err
+	}
+
+	root, err := p.FileRepo.FindFileByShaID(ctx, link.FileShaID)
+	if err == sql.ErrNoRows {
+		return domain.PublicView{}, fmt.Errorf("%w: link not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.PublicView{}, err
+	}
+
+	// a folder link also opens every file under the folder
+	file := root
+	if shaID != "" && shaID != root.ShaID {
+		file, err = p.findLinkedFile(ctx, root, shaID)
+		if err != nil {
+			return domain.PublicView{}, err
+		}
+	}
+
+	// the files are read as their owner, the link already gives access
+	view := domain.PublicView{
+		File: publicFile(root, file),
+	}
+
+	if file.Type == domain.FileTypeFolder {
+		children, err := p.FolderUsecase.GetFolderChildren(ctx, file.UserID, file.ShaID)
+		if err != nil {
+			return domain.PublicView{}, err
+		}
+
+		view.Children = []domain.File{}
+		for _, v := range children {
+			view.Children = append(view.Children, publicFile(root, v))
+		}
+	} else {
+		note, err := p.NoteUsecase.FindNote(ctx, file.UserID, file.ShaID)
+		if err != nil {
+			return domain.PublicView{}, err
+		}
+
+		render, err := p.NoteRenderUsecase.RenderNote(ctx, file.UserID, file.ShaID)
+		if err != nil {
+			return domain.PublicView{}, err
+		}
+
+		view.Note = note.Note
+		view.HTML = render.HTML
+	}
+
+	view.ViewCount, err = p.PublicLinkRepo.IncrementPublicLinkViews(ctx, link.ID)
+	if err != nil {
+		return domain.PublicView{}, err
+	}
+
+	return view, nil
+}
+
+// openLink finds the link of the signed token and checks its expiry and password
+func (p PublicLinkUseCaseImpl) openLink(ctx context.Context, token string, password string) (domain.PublicLink, error) {
+	raw, ok := helpers.VerifySignedToken(token)
+	if !ok {
+		return domain.PublicLink{}, fmt.Errorf("%w: link not found", domain.ErrNotFound)
+	}
+
+	// revoked links are not found
+	link, err := p.PublicLinkRepo.FindPublicLink(ctx, raw)
+	if err == sql.ErrNoRows {
+		return domain.PublicLink{}, fmt.Errorf("%w: link not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.PublicLink{}, err
+	}
+
+	if link.ExpiresAt.Valid && !link.ExpiresAt.Time.After(time.Now()) {
+		return domain.PublicLink{}, fmt.Errorf("%w: link expired", domain.ErrNotFound)
+	}
+
+	if link.HasPassword {
+		if password == "" {
+			return domain.PublicLink{}, fmt.Errorf("%w: password required", domain.ErrForbidden)
+		}
+
+		match, err := helpers.ArgonVerify(password, link.Password)
+		if err != nil {
+			return domain.PublicLink{}, err
+		}
+
+		if !match {
+			return domain.PublicLink{}, fmt.Errorf("%w: wrong password", domain.ErrForbidden)
+		}
+	}
+
+	return link, nil
+}
+
+// findLinkedFile finds the file when it is under the folder of the link
+func (p PublicLinkUseCaseImpl) findLinkedFile(ctx context.Context, root domain.File, shaID string) (domain.File, error) {
+	if root.Type != domain.FileTypeFolder {
+		return domain.File{}, fmt.Errorf("%w: file not found", domain.ErrNotFound)
+	}
+
+	file, err := p.FileRepo.FindFileByShaID(ctx, shaID)
+	if err == sql.ErrNoRows || (err == nil && file.UserID != root.UserID) {
+		return domain.File{}, fmt.Errorf("%w: file not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	ancestors, err := p.FileRepo.GetFileAncestors(ctx, file.UserID, file.ShaID)
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	for _, v := range ancestors {
+		if v == root.ShaID {
+			return file, nil
+		}
+	}
+
+	return domain.File{}, fmt.Errorf("%w: file not found", domain.ErrNotFound)
+}
+
+// publicFile hides where the file of the link is in the files of its owner,
+// paths start at the linked file
+func publicFile(root domain.File, file domain.File) domain.File {
+	if file.ShaID == root.ShaID {
+		file.FolderShaID = null.String{}
+	}
+
+	if dir := path.Dir(root.Path); dir != "/" {
+		file.Path = strings.TrimPrefix(file.Path, dir)
+	}
+
+	return file
+}
+
+// signLink replaces the token with the signed one given out in the url
+func signLink(link domain.PublicLink) domain.PublicLink {
+	link.Token = helpers.SignToken(link.Token)
+	return link
+}
+
+func NewPublicLinkUseCase(plr domain.PublicLinkRepo, fr domain.FileRepo, su domain.ShareUsecase, fu domain.FolderUsecase, nu domain.NoteUsecase, nru domain.NoteRenderUsecase, ig helpers.IDGenerator) domain.PublicLinkUsecase {
+	return &PublicLinkUseCaseImpl{
+		PublicLinkRepo:    plr,
+		FileRepo:          fr,
+		ShareUsecase:      su,
+		FolderUsecase:     fu,
+		NoteUsecase:       nu,
+		NoteRenderUsecase: nru,
+		IDGenerator:       ig,
+	}
+}
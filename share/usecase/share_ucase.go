@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+// rank of every role, a higher rank allows everything a lower one does
+var roleRanks = map[string]int{
+	domain.ShareRoleViewer:    1,
+	domain.ShareRoleCommenter: 2,
+	domain.ShareRoleEditor:    3,
+	domain.ShareRoleOwner:     4,
+}
+
+type ShareUseCaseImpl struct {
+	ShareRepo domain.ShareRepo
+	FileRepo  domain.FileRepo
+	UserRepo  domain.UserRepo
+}
+
+// ShareFile implements domain.ShareUsecase
+func (s ShareUseCaseImpl) ShareFile(ctx context.Context, userID int, shaID string, username string, role string) (domain.Share, error) {
+	if role == domain.ShareRoleOwner || roleRanks[role] == 0 {
+		return domain.Share{}, fmt.Errorf("%w: role must be viewer, commenter or editor", domain.ErrBadParamInput)
+	}
+
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return domain.Share{}, fmt.Errorf("%w: username cannot be empty", domain.ErrBadParamInput)
+	}
+
+	// only the owner manages the shares
+	file, err := s.Authorize(ctx, userID, shaID, domain.ShareRoleOwner)
+	if err != nil {
+		return domain.Share{}, err
+	}
+
+	user, err := s.UserRepo.FindUserByUsername(ctx, username)
+	if err == sql.ErrNoRows {
+		return domain.Share{}, fmt.Errorf("%w: user not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.Share{}, err
+	}
+
+	if user.ID == userID {
+		return domain.Share{}, fmt.Errorf("%w: cannot share a file with yourself", domain.ErrBadParamInput)
+	}
+
+	// call repository
+	return s.ShareRepo.UpsertShare(ctx, domain.Share{
+		FileShaID: file.ShaID,
+		OwnerID:   file.UserID,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      role,
+	})
+}
+
+// GetFileShares implements domain.ShareUsecase
+func (s ShareUseCaseImpl) GetFileShares(ctx context.Context, userID int, shaID string) ([]domain.Share, error) {
+	file, err := s.Authorize(ctx, userID, shaID, domain.ShareRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// call repository
+	return s.ShareRepo.GetFileShares(ctx, file.ShaID)
+}
+
+// UnshareFile implements domain.ShareUsecase
+func (s ShareUseCaseImpl) UnshareFile(ctx context.Context, userID int, shaID string, sharedUserID int) error {
+	// a user can always leave a share, anybody else has to be the owner
+	role := domain.ShareRoleOwner
+	if sharedUserID == userID {
+		role = domain.ShareRoleViewer
+	}
+
+	file, err := s.Authorize(ctx, userID, shaID, role)
+	if err != nil {
+		return err
+	}
+
+	// call repository
+	err = s.ShareRepo.DeleteShare(ctx, file.ShaID, sharedUserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: share not found", domain.ErrNotFound)
+	}
+
+	return err
+}
+
+// GetSharedFiles implements domain.ShareUsecase
+func (s ShareUseCaseImpl) GetSharedFiles(ctx context.Context, userID int) ([]domain.SharedFile, error) {
+	// call repository
+	return s.ShareRepo.GetSharedFiles(ctx, userID)
+}
+
+// Authorize implements domain.ShareUsecase
+func (s ShareUseCaseImpl) Authorize(ctx context.Context, userID int, shaID string, role string) (domain.File, error) {
+	// check if sha id is not empty
+	if shaID == "" {
+		return domain.File{}, fmt.Errorf("%w: sha_id cannot be empty", domain.ErrBadParamInput)
+	}
+
+	file, err := s.FileRepo.FindFileByShaID(ctx, shaID)
+	if err == sql.ErrNoRows {
+		return domain.File{}, fmt.Errorf("%w: file not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	if file.UserID == userID {
+		return file, nil
+	}
+
+	// a share on any folder above the file also gives access to it
+	shaIDs, err := s.FileRepo.GetFileAncestors(ctx, file.UserID, file.ShaID)
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	shares, err := s.ShareRepo.GetUserShares(ctx, userID, shaIDs)
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	// the files the user has no access to are not found so they don't leak
+	if len(shares) == 0 {
+		return domain.File{}, fmt.Errorf("%w: file not found", domain.ErrNotFound)
+	}
+
+	rank := 0
+	for _, v := range shares {
+		if roleRanks[v.Role] > rank {
+			rank = roleRanks[v.Role]
+		}
+	}
+
+	if rank < roleRanks[role] {
+		return domain.File{}, fmt.Errorf("%w: %s access is required", domain.ErrForbidden, role)
+	}
+
+	return file, nil
+}
+
+func NewShareUseCase(sr domain.ShareRepo, fr domain.FileRepo, ur domain.UserRepo) domain.ShareUsecase {
+	return &ShareUseCaseImpl{
+		ShareRepo: sr,
+		FileRepo:  fr,
+		UserRepo:  ur,
+	}
+}
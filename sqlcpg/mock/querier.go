@@ -2,31 +2,616 @@ package mock
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/ihsanbudiman/notes_app/sqlcpg"
 	"github.com/stretchr/testify/mock"
 )
 
+// QuerierMock has a method for every query of sqlcpg.Querier
 type QuerierMock struct {
 	mock.Mock
 }
 
+var _ sqlcpg.Querier = (*QuerierMock)(nil)
+
+func (m *QuerierMock) AddBlobReference(ctx context.Context, arg sqlcpg.AddBlobReferenceParams) (sqlcpg.Blob, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Blob), args.Error(1)
+}
+
+func (m *QuerierMock) AttachTag(ctx context.Context, arg sqlcpg.AttachTagParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) ClaimDueReminders(ctx context.Context, arg sqlcpg.ClaimDueRemindersParams) ([]sqlcpg.Reminder, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.Reminder), args.Error(1)
+}
+
+func (m *QuerierMock) CountFilesByName(ctx context.Context, arg sqlcpg.CountFilesByNameParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *QuerierMock) CountFolderChildren(ctx context.Context, arg sqlcpg.CountFolderChildrenParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *QuerierMock) CreateAttachment(ctx context.Context, arg sqlcpg.CreateAttachmentParams) (sqlcpg.Attachment, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Attachment), args.Error(1)
+}
+
+func (m *QuerierMock) CreateFile(ctx context.Context, arg sqlcpg.CreateFileParams) (sqlcpg.File, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) CreateFolder(ctx context.Context, arg sqlcpg.CreateFolderParams) (sqlcpg.Folder, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Folder), args.Error(1)
+}
+
+func (m *QuerierMock) CreateNote(ctx context.Context, arg sqlcpg.CreateNoteParams) (sqlcpg.Note, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Note), args.Error(1)
+}
+
+func (m *QuerierMock) CreateNoteLink(ctx context.Context, arg sqlcpg.CreateNoteLinkParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) CreateNoteRevision(ctx context.Context, arg sqlcpg.CreateNoteRevisionParams) (sqlcpg.NoteRevision, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.NoteRevision), args.Error(1)
+}
+
+func (m *QuerierMock) CreateNotification(ctx context.Context, arg sqlcpg.CreateNotificationParams) (sqlcpg.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Notification), args.Error(1)
+}
+
+func (m *QuerierMock) CreatePublicLink(ctx context.Context, arg sqlcpg.CreatePublicLinkParams) (sqlcpg.PublicLink, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.PublicLink), args.Error(1)
+}
+
+func (m *QuerierMock) CreateReminder(ctx context.Context, arg sqlcpg.CreateReminderParams) (sqlcpg.Reminder, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Reminder), args.Error(1)
+}
+
+func (m *QuerierMock) DeleteAttachment(ctx context.Context, shaID string) (string, error) {
+	args := m.Called(ctx, shaID)
+	return args.Get(0).(string), args.Error(1)
+}
+
+func (m *QuerierMock) DeleteBlob(ctx context.Context, checksum string) error {
+	args := m.Called(ctx, checksum)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteFile(ctx context.Context, arg sqlcpg.DeleteFileParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteFileShares(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteFiles(ctx context.Context, arg sqlcpg.DeleteFilesParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteFolders(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteNote(ctx context.Context, fileShaID string) error {
+	args := m.Called(ctx, fileShaID)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteNoteLinks(ctx context.Context, sourceShaID string) error {
+	args := m.Called(ctx, sourceShaID)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteNoteReminders(ctx context.Context, arg sqlcpg.DeleteNoteRemindersParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteNoteRevisions(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteNoteTags(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteNotes(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteNotesLinks(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeletePublicLinks(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteReminders(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteShare(ctx context.Context, arg sqlcpg.DeleteShareParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *QuerierMock) DeleteTag(ctx context.Context, arg sqlcpg.DeleteTagParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteTagNotes(ctx context.Context, tagID int32) error {
+	args := m.Called(ctx, tagID)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DeleteTemplate(ctx context.Context, fileShaID string) (int64, error) {
+	args := m.Called(ctx, fileShaID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *QuerierMock) DeleteTemplates(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) DetachTag(ctx context.Context, arg sqlcpg.DetachTagParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *QuerierMock) FindAttachment(ctx context.Context, shaID string) (sqlcpg.Attachment, error) {
+	args := m.Called(ctx, shaID)
+	return args.Get(0).(sqlcpg.Attachment), args.Error(1)
+}
+
+func (m *QuerierMock) FindFile(ctx context.Context, arg sqlcpg.FindFileParams) (sqlcpg.File, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) FindFileByPath(ctx context.Context, arg sqlcpg.FindFileByPathParams) (sqlcpg.File, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) FindFileByShaID(ctx context.Context, shaID string) (sqlcpg.File, error) {
+	args := m.Called(ctx, shaID)
+	return args.Get(0).(sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) FindFileForUpdate(ctx context.Context, arg sqlcpg.FindFileForUpdateParams) (sqlcpg.File, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) FindFolder(ctx context.Context, arg sqlcpg.FindFolderParams) (sqlcpg.FindFolderRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.FindFolderRow), args.Error(1)
+}
+
+func (m *QuerierMock) FindLinkTargets(ctx context.Context, arg sqlcpg.FindLinkTargetsParams) ([]sqlcpg.FindLinkTargetsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.FindLinkTargetsRow), args.Error(1)
+}
+
+func (m *QuerierMock) FindNote(ctx context.Context, arg sqlcpg.FindNoteParams) (sqlcpg.FindNoteRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.FindNoteRow), args.Error(1)
+}
+
+func (m *QuerierMock) FindNoteForUpdate(ctx context.Context, arg sqlcpg.FindNoteForUpdateParams) (sqlcpg.FindNoteForUpdateRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.FindNoteForUpdateRow), args.Error(1)
+}
+
+func (m *QuerierMock) FindNoteRevision(ctx context.Context, arg sqlcpg.FindNoteRevisionParams) (sqlcpg.NoteRevision, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.NoteRevision), args.Error(1)
+}
+
+func (m *QuerierMock) FindPublicLink(ctx context.Context, token string) (sqlcpg.PublicLink, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(sqlcpg.PublicLink), args.Error(1)
+}
+
+func (m *QuerierMock) FindTag(ctx context.Context, arg sqlcpg.FindTagParams) (sqlcpg.Tag, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Tag), args.Error(1)
+}
+
+func (m *QuerierMock) FindTemplate(ctx context.Context, fileShaID string) (sqlcpg.FindTemplateRow, error) {
+	args := m.Called(ctx, fileShaID)
+	return args.Get(0).(sqlcpg.FindTemplateRow), args.Error(1)
+}
+
+func (m *QuerierMock) FindTrashedFile(ctx context.Context, arg sqlcpg.FindTrashedFileParams) (sqlcpg.File, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.File), args.Error(1)
+}
+
 func (m *QuerierMock) FindUser(ctx context.Context, id int32) (sqlcpg.User, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(sqlcpg.User), args.Error(1)
 }
 
-func (m *QuerierMock) Login(ctx context.Context, params sqlcpg.LoginParams) (sqlcpg.User, error) {
-	args := m.Called(ctx, params)
+func (m *QuerierMock) FindUserByEmail(ctx context.Context, email sql.NullString) (sqlcpg.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(sqlcpg.User), args.Error(1)
+}
+
+func (m *QuerierMock) FindUserByPhoneNumber(ctx context.Context, phoneNumber sql.NullString) (sqlcpg.User, error) {
+	args := m.Called(ctx, phoneNumber)
+	return args.Get(0).(sqlcpg.User), args.Error(1)
+}
+
+func (m *QuerierMock) FindUserByUsername(ctx context.Context, username string) (sqlcpg.User, error) {
+	args := m.Called(ctx, username)
 	return args.Get(0).(sqlcpg.User), args.Error(1)
 }
 
-func (m *QuerierMock) Register(ctx context.Context, user sqlcpg.User) (sqlcpg.User, error) {
-	args := m.Called(ctx, user)
+func (m *QuerierMock) FindUserByUsernameOrEmailOrPhoneNumber(ctx context.Context, arg sqlcpg.FindUserByUsernameOrEmailOrPhoneNumberParams) (sqlcpg.User, error) {
+	args := m.Called(ctx, arg)
 	return args.Get(0).(sqlcpg.User), args.Error(1)
 }
 
+func (m *QuerierMock) FindUserSettings(ctx context.Context, userID int32) (sqlcpg.UserSetting, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(sqlcpg.UserSetting), args.Error(1)
+}
+
+func (m *QuerierMock) GetAttachmentUsage(ctx context.Context, userID int32) (sqlcpg.GetAttachmentUsageRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(sqlcpg.GetAttachmentUsageRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetDeletedFiles(ctx context.Context, userID int32) ([]sqlcpg.File, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) GetExpiredFiles(ctx context.Context, before time.Time) ([]sqlcpg.File, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).([]sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) GetFileAncestors(ctx context.Context, arg sqlcpg.GetFileAncestorsParams) ([]string, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *QuerierMock) GetFileDescendants(ctx context.Context, arg sqlcpg.GetFileDescendantsParams) ([]sqlcpg.GetFileDescendantsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.GetFileDescendantsRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetFileShares(ctx context.Context, fileShaID string) ([]sqlcpg.GetFileSharesRow, error) {
+	args := m.Called(ctx, fileShaID)
+	return args.Get(0).([]sqlcpg.GetFileSharesRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetFileTree(ctx context.Context, arg sqlcpg.GetFileTreeParams) ([]sqlcpg.GetFileTreeRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.GetFileTreeRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetFolderChildren(ctx context.Context, arg sqlcpg.GetFolderChildrenParams) ([]sqlcpg.File, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) GetLinkingNotes(ctx context.Context, shaIds []string) ([]sqlcpg.GetLinkingNotesRow, error) {
+	args := m.Called(ctx, shaIds)
+	return args.Get(0).([]sqlcpg.GetLinkingNotesRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNoteAttachments(ctx context.Context, noteShaID string) ([]sqlcpg.Attachment, error) {
+	args := m.Called(ctx, noteShaID)
+	return args.Get(0).([]sqlcpg.Attachment), args.Error(1)
+}
+
+func (m *QuerierMock) GetNoteBacklinks(ctx context.Context, targetShaID sql.NullString) ([]sqlcpg.GetNoteBacklinksRow, error) {
+	args := m.Called(ctx, targetShaID)
+	return args.Get(0).([]sqlcpg.GetNoteBacklinksRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNoteGraphEdges(ctx context.Context, userID int32) ([]sqlcpg.GetNoteGraphEdgesRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.GetNoteGraphEdgesRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNoteGraphNodes(ctx context.Context, userID int32) ([]sqlcpg.GetNoteGraphNodesRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.GetNoteGraphNodesRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNoteLinks(ctx context.Context, sourceShaID string) ([]sqlcpg.GetNoteLinksRow, error) {
+	args := m.Called(ctx, sourceShaID)
+	return args.Get(0).([]sqlcpg.GetNoteLinksRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNoteReminders(ctx context.Context, arg sqlcpg.GetNoteRemindersParams) ([]sqlcpg.Reminder, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.Reminder), args.Error(1)
+}
+
+func (m *QuerierMock) GetNoteRevisions(ctx context.Context, fileShaID string) ([]sqlcpg.NoteRevision, error) {
+	args := m.Called(ctx, fileShaID)
+	return args.Get(0).([]sqlcpg.NoteRevision), args.Error(1)
+}
+
+func (m *QuerierMock) GetNoteTags(ctx context.Context, arg sqlcpg.GetNoteTagsParams) ([]sqlcpg.Tag, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.Tag), args.Error(1)
+}
+
+func (m *QuerierMock) GetNotes(ctx context.Context, userID int32) ([]sqlcpg.GetNotesRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.GetNotesRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNotesByFolder(ctx context.Context, arg sqlcpg.GetNotesByFolderParams) ([]sqlcpg.GetNotesByFolderRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.GetNotesByFolderRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNotesByTags(ctx context.Context, arg sqlcpg.GetNotesByTagsParams) ([]sqlcpg.GetNotesByTagsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.GetNotesByTagsRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNotesWithOpenTasks(ctx context.Context, userID int32) ([]sqlcpg.GetNotesWithOpenTasksRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.GetNotesWithOpenTasksRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetNotifications(ctx context.Context, arg sqlcpg.GetNotificationsParams) ([]sqlcpg.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.Notification), args.Error(1)
+}
+
+func (m *QuerierMock) GetOrphanAttachments(ctx context.Context, limit int32) ([]sqlcpg.Attachment, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]sqlcpg.Attachment), args.Error(1)
+}
+
+func (m *QuerierMock) GetPublicLinks(ctx context.Context, arg sqlcpg.GetPublicLinksParams) ([]sqlcpg.PublicLink, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.PublicLink), args.Error(1)
+}
+
+func (m *QuerierMock) GetSharedFiles(ctx context.Context, userID int32) ([]sqlcpg.GetSharedFilesRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.GetSharedFilesRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetTags(ctx context.Context, userID int32) ([]sqlcpg.GetTagsRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.GetTagsRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetTemplates(ctx context.Context, userID int32) ([]sqlcpg.GetTemplatesRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.GetTemplatesRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetTrash(ctx context.Context, userID int32) ([]sqlcpg.File, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) GetUnusedBlobs(ctx context.Context, limit int32) ([]sqlcpg.Blob, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]sqlcpg.Blob), args.Error(1)
+}
+
+func (m *QuerierMock) GetUserReminders(ctx context.Context, userID int32) ([]sqlcpg.GetUserRemindersRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]sqlcpg.GetUserRemindersRow), args.Error(1)
+}
+
+func (m *QuerierMock) GetUserShares(ctx context.Context, arg sqlcpg.GetUserSharesParams) ([]sqlcpg.Share, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.Share), args.Error(1)
+}
+
 func (m *QuerierMock) GetUsers(ctx context.Context) ([]sqlcpg.User, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]sqlcpg.User), args.Error(1)
 }
+
+func (m *QuerierMock) IncrementPublicLinkViews(ctx context.Context, id int32) (int32, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int32), args.Error(1)
+}
+
+func (m *QuerierMock) LockFolder(ctx context.Context, arg sqlcpg.LockFolderParams) (string, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(string), args.Error(1)
+}
+
+func (m *QuerierMock) LockUnusedBlob(ctx context.Context, checksum string) (sqlcpg.Blob, error) {
+	args := m.Called(ctx, checksum)
+	return args.Get(0).(sqlcpg.Blob), args.Error(1)
+}
+
+func (m *QuerierMock) LockUser(ctx context.Context, id int32) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) Login(ctx context.Context, arg sqlcpg.LoginParams) (sqlcpg.User, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.User), args.Error(1)
+}
+
+func (m *QuerierMock) MarkBlobStored(ctx context.Context, arg sqlcpg.MarkBlobStoredParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) MarkReminderSent(ctx context.Context, arg sqlcpg.MarkReminderSentParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) MergeTagNotes(ctx context.Context, arg sqlcpg.MergeTagNotesParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) PruneNoteRevisions(ctx context.Context, arg sqlcpg.PruneNoteRevisionsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) ReadNotification(ctx context.Context, arg sqlcpg.ReadNotificationParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *QuerierMock) Register(ctx context.Context, arg sqlcpg.RegisterParams) (sqlcpg.User, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.User), args.Error(1)
+}
+
+func (m *QuerierMock) RemoveBlobReference(ctx context.Context, arg sqlcpg.RemoveBlobReferenceParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) RenameTag(ctx context.Context, arg sqlcpg.RenameTagParams) (sqlcpg.Tag, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Tag), args.Error(1)
+}
+
+func (m *QuerierMock) ResolveNoteLinks(ctx context.Context, arg sqlcpg.ResolveNoteLinksParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) RestoreFiles(ctx context.Context, arg sqlcpg.RestoreFilesParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) RestoreFolders(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) RestoreNotes(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) RetryReminder(ctx context.Context, arg sqlcpg.RetryReminderParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) RevokePublicLink(ctx context.Context, arg sqlcpg.RevokePublicLinkParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *QuerierMock) SearchNotes(ctx context.Context, arg sqlcpg.SearchNotesParams) ([]sqlcpg.SearchNotesRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlcpg.SearchNotesRow), args.Error(1)
+}
+
+func (m *QuerierMock) TouchNote(ctx context.Context, arg sqlcpg.TouchNoteParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) TrashFiles(ctx context.Context, arg sqlcpg.TrashFilesParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) TrashFolders(ctx context.Context, arg sqlcpg.TrashFoldersParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) TrashNotes(ctx context.Context, arg sqlcpg.TrashNotesParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) UnresolveNoteLinks(ctx context.Context, shaIds []string) error {
+	args := m.Called(ctx, shaIds)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) UpdateFile(ctx context.Context, arg sqlcpg.UpdateFileParams) (sqlcpg.File, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.File), args.Error(1)
+}
+
+func (m *QuerierMock) UpdateFilePathPrefix(ctx context.Context, arg sqlcpg.UpdateFilePathPrefixParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) UpdateFolderParentBySha(ctx context.Context, arg sqlcpg.UpdateFolderParentByShaParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *QuerierMock) UpdateNote(ctx context.Context, arg sqlcpg.UpdateNoteParams) (sqlcpg.Note, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Note), args.Error(1)
+}
+
+func (m *QuerierMock) UpsertShare(ctx context.Context, arg sqlcpg.UpsertShareParams) (sqlcpg.Share, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Share), args.Error(1)
+}
+
+func (m *QuerierMock) UpsertTag(ctx context.Context, arg sqlcpg.UpsertTagParams) (sqlcpg.Tag, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Tag), args.Error(1)
+}
+
+func (m *QuerierMock) UpsertTemplate(ctx context.Context, arg sqlcpg.UpsertTemplateParams) (sqlcpg.Template, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.Template), args.Error(1)
+}
+
+func (m *QuerierMock) UpsertUserSettings(ctx context.Context, arg sqlcpg.UpsertUserSettingsParams) (sqlcpg.UserSetting, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlcpg.UserSetting), args.Error(1)
+}
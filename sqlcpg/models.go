@@ -9,6 +9,32 @@ import (
 	"time"
 )
 
+type Attachment struct {
+	ID        int32
+	ShaID     string
+	NoteShaID string
+	// owner of the note
+	UserID   int32
+	Name     string
+	MimeType string
+	Size     int64
+	// hex sha-256 of the content, the blob it is stored in
+	Checksum  string
+	CreatedAt time.Time
+}
+
+type Blob struct {
+	// hex sha-256 of the content, the blob store key is made from it
+	Checksum string
+	Size     int64
+	// number of attachments with the content, 0 means the content can be deleted
+	RefCount int32
+	// null until the content is in the blob store
+	StoredAt  sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 type File struct {
 	ID          int32
 	FolderShaID string
@@ -20,6 +46,7 @@ type File struct {
 	ShaID     string
 	Path      string
 	UserID    int32
+	DeletedAt sql.NullTime
 }
 
 type Folder struct {
@@ -28,6 +55,7 @@ type Folder struct {
 	ParentID  sql.NullInt32
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt sql.NullTime
 }
 
 type Note struct {
@@ -36,6 +64,105 @@ type Note struct {
 	Note      sql.NullString
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt sql.NullTime
+}
+
+type NoteLink struct {
+	ID          int32
+	SourceShaID string
+	// null while no note matches the target
+	TargetShaID sql.NullString
+	// owner of the source note
+	UserID int32
+	// note name, path or sha id written in the link
+	Target    string
+	Heading   string
+	Alias     string
+	Line      int32
+	Snippet   string
+	CreatedAt time.Time
+}
+
+type NoteRevision struct {
+	ID        int32
+	FileShaID string
+	Note      sql.NullString
+	CreatedAt time.Time
+}
+
+type NoteTag struct {
+	FileShaID string
+	TagID     int32
+	CreatedAt time.Time
+}
+
+type Notification struct {
+	ID        int32
+	UserID    int32
+	FileShaID string
+	Title     string
+	Body      string
+	ReadAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type PublicLink struct {
+	ID        int32
+	Token     string
+	FileShaID string
+	UserID    int32
+	Password  sql.NullString
+	ExpiresAt sql.NullTime
+	RevokedAt sql.NullTime
+	ViewCount int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Reminder struct {
+	ID        int32
+	FileShaID string
+	// user the reminder is sent to
+	UserID int32
+	DueAt  time.Time
+	// minutes before due_at the reminder is sent
+	OffsetMinutes int32
+	RemindAt      time.Time
+	SentAt        sql.NullTime
+	// a scheduler is sending the reminder until then, a failed reminder is retried after it
+	ClaimedUntil sql.NullTime
+	Attempts     int32
+	LastError    string
+	// notifiers the reminder was sent through, a failed reminder is retried on the others
+	DeliveredChannels []string
+	CreatedAt         time.Time
+}
+
+type Share struct {
+	ID        int32
+	FileShaID string
+	OwnerID   int32
+	UserID    int32
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Tag struct {
+	ID        int32
+	UserID    int32
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Template struct {
+	FileShaID string
+	// owner of the note
+	UserID      int32
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 type User struct {
@@ -48,3 +175,10 @@ type User struct {
 	UpdatedAt   time.Time
 	Name        string
 }
+
+type UserSetting struct {
+	UserID int32
+	// number of note revisions kept, 0 keeps all
+	NoteRevisionRetention int32
+	UpdatedAt             time.Time
+}
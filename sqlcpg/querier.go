@@ -7,17 +7,130 @@ package sqlcpg
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddBlobReference(ctx context.Context, arg AddBlobReferenceParams) (Blob, error)
+	AttachTag(ctx context.Context, arg AttachTagParams) error
+	ClaimDueReminders(ctx context.Context, arg ClaimDueRemindersParams) ([]Reminder, error)
+	CountFilesByName(ctx context.Context, arg CountFilesByNameParams) (int64, error)
+	CountFolderChildren(ctx context.Context, arg CountFolderChildrenParams) (int64, error)
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error)
+	CreateFile(ctx context.Context, arg CreateFileParams) (File, error)
+	CreateFolder(ctx context.Context, arg CreateFolderParams) (Folder, error)
+	CreateNote(ctx context.Context, arg CreateNoteParams) (Note, error)
+	CreateNoteLink(ctx context.Context, arg CreateNoteLinkParams) error
+	CreateNoteRevision(ctx context.Context, arg CreateNoteRevisionParams) (NoteRevision, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreatePublicLink(ctx context.Context, arg CreatePublicLinkParams) (PublicLink, error)
+	CreateReminder(ctx context.Context, arg CreateReminderParams) (Reminder, error)
+	DeleteAttachment(ctx context.Context, shaID string) (string, error)
+	DeleteBlob(ctx context.Context, checksum string) error
+	DeleteFile(ctx context.Context, arg DeleteFileParams) error
+	DeleteFileShares(ctx context.Context, shaIds []string) error
+	DeleteFiles(ctx context.Context, arg DeleteFilesParams) error
+	DeleteFolders(ctx context.Context, shaIds []string) error
+	DeleteNote(ctx context.Context, fileShaID string) error
+	DeleteNoteLinks(ctx context.Context, sourceShaID string) error
+	DeleteNoteReminders(ctx context.Context, arg DeleteNoteRemindersParams) error
+	DeleteNoteRevisions(ctx context.Context, shaIds []string) error
+	DeleteNoteTags(ctx context.Context, shaIds []string) error
+	DeleteNotes(ctx context.Context, shaIds []string) error
+	DeleteNotesLinks(ctx context.Context, shaIds []string) error
+	DeletePublicLinks(ctx context.Context, shaIds []string) error
+	DeleteReminders(ctx context.Context, shaIds []string) error
+	DeleteShare(ctx context.Context, arg DeleteShareParams) (int64, error)
+	DeleteTag(ctx context.Context, arg DeleteTagParams) error
+	DeleteTagNotes(ctx context.Context, tagID int32) error
+	DeleteTemplate(ctx context.Context, fileShaID string) (int64, error)
+	DeleteTemplates(ctx context.Context, shaIds []string) error
+	DetachTag(ctx context.Context, arg DetachTagParams) (int64, error)
+	FindAttachment(ctx context.Context, shaID string) (Attachment, error)
+	FindFile(ctx context.Context, arg FindFileParams) (File, error)
+	FindFileByPath(ctx context.Context, arg FindFileByPathParams) (File, error)
+	FindFileByShaID(ctx context.Context, shaID string) (File, error)
+	FindFileForUpdate(ctx context.Context, arg FindFileForUpdateParams) (File, error)
+	FindFolder(ctx context.Context, arg FindFolderParams) (FindFolderRow, error)
+	FindLinkTargets(ctx context.Context, arg FindLinkTargetsParams) ([]FindLinkTargetsRow, error)
+	FindNote(ctx context.Context, arg FindNoteParams) (FindNoteRow, error)
+	FindNoteForUpdate(ctx context.Context, arg FindNoteForUpdateParams) (FindNoteForUpdateRow, error)
+	FindNoteRevision(ctx context.Context, arg FindNoteRevisionParams) (NoteRevision, error)
+	FindPublicLink(ctx context.Context, token string) (PublicLink, error)
+	FindTag(ctx context.Context, arg FindTagParams) (Tag, error)
+	FindTemplate(ctx context.Context, fileShaID string) (FindTemplateRow, error)
+	FindTrashedFile(ctx context.Context, arg FindTrashedFileParams) (File, error)
 	FindUser(ctx context.Context, id int32) (User, error)
 	FindUserByEmail(ctx context.Context, email sql.NullString) (User, error)
 	FindUserByPhoneNumber(ctx context.Context, phoneNumber sql.NullString) (User, error)
 	FindUserByUsername(ctx context.Context, username string) (User, error)
 	FindUserByUsernameOrEmailOrPhoneNumber(ctx context.Context, arg FindUserByUsernameOrEmailOrPhoneNumberParams) (User, error)
+	FindUserSettings(ctx context.Context, userID int32) (UserSetting, error)
+	GetAttachmentUsage(ctx context.Context, userID int32) (GetAttachmentUsageRow, error)
+	GetDeletedFiles(ctx context.Context, userID int32) ([]File, error)
+	GetExpiredFiles(ctx context.Context, before time.Time) ([]File, error)
+	GetFileAncestors(ctx context.Context, arg GetFileAncestorsParams) ([]string, error)
+	GetFileDescendants(ctx context.Context, arg GetFileDescendantsParams) ([]GetFileDescendantsRow, error)
+	GetFileShares(ctx context.Context, fileShaID string) ([]GetFileSharesRow, error)
+	GetFileTree(ctx context.Context, arg GetFileTreeParams) ([]GetFileTreeRow, error)
+	GetFolderChildren(ctx context.Context, arg GetFolderChildrenParams) ([]File, error)
+	GetLinkingNotes(ctx context.Context, shaIds []string) ([]GetLinkingNotesRow, error)
+	GetNoteAttachments(ctx context.Context, noteShaID string) ([]Attachment, error)
+	GetNoteBacklinks(ctx context.Context, targetShaID sql.NullString) ([]GetNoteBacklinksRow, error)
+	GetNoteGraphEdges(ctx context.Context, userID int32) ([]GetNoteGraphEdgesRow, error)
+	GetNoteGraphNodes(ctx context.Context, userID int32) ([]GetNoteGraphNodesRow, error)
+	GetNoteLinks(ctx context.Context, sourceShaID string) ([]GetNoteLinksRow, error)
+	GetNoteReminders(ctx context.Context, arg GetNoteRemindersParams) ([]Reminder, error)
+	GetNoteRevisions(ctx context.Context, fileShaID string) ([]NoteRevision, error)
+	GetNoteTags(ctx context.Context, arg GetNoteTagsParams) ([]Tag, error)
+	GetNotes(ctx context.Context, userID int32) ([]GetNotesRow, error)
+	GetNotesByFolder(ctx context.Context, arg GetNotesByFolderParams) ([]GetNotesByFolderRow, error)
+	GetNotesByTags(ctx context.Context, arg GetNotesByTagsParams) ([]GetNotesByTagsRow, error)
+	GetNotesWithOpenTasks(ctx context.Context, userID int32) ([]GetNotesWithOpenTasksRow, error)
+	GetNotifications(ctx context.Context, arg GetNotificationsParams) ([]Notification, error)
+	GetOrphanAttachments(ctx context.Context, limit int32) ([]Attachment, error)
+	GetPublicLinks(ctx context.Context, arg GetPublicLinksParams) ([]PublicLink, error)
+	GetSharedFiles(ctx context.Context, userID int32) ([]GetSharedFilesRow, error)
+	GetTags(ctx context.Context, userID int32) ([]GetTagsRow, error)
+	GetTemplates(ctx context.Context, userID int32) ([]GetTemplatesRow, error)
+	GetTrash(ctx context.Context, userID int32) ([]File, error)
+	GetUnusedBlobs(ctx context.Context, limit int32) ([]Blob, error)
+	GetUserReminders(ctx context.Context, userID int32) ([]GetUserRemindersRow, error)
+	GetUserShares(ctx context.Context, arg GetUserSharesParams) ([]Share, error)
 	GetUsers(ctx context.Context) ([]User, error)
+	IncrementPublicLinkViews(ctx context.Context, id int32) (int32, error)
+	LockFolder(ctx context.Context, arg LockFolderParams) (string, error)
+	LockUnusedBlob(ctx context.Context, checksum string) (Blob, error)
+	LockUser(ctx context.Context, id int32) error
 	Login(ctx context.Context, arg LoginParams) (User, error)
+	MarkBlobStored(ctx context.Context, arg MarkBlobStoredParams) error
+	MarkReminderSent(ctx context.Context, arg MarkReminderSentParams) error
+	MergeTagNotes(ctx context.Context, arg MergeTagNotesParams) error
+	PruneNoteRevisions(ctx context.Context, arg PruneNoteRevisionsParams) error
+	ReadNotification(ctx context.Context, arg ReadNotificationParams) (int64, error)
 	Register(ctx context.Context, arg RegisterParams) (User, error)
+	RemoveBlobReference(ctx context.Context, arg RemoveBlobReferenceParams) error
+	RenameTag(ctx context.Context, arg RenameTagParams) (Tag, error)
+	ResolveNoteLinks(ctx context.Context, arg ResolveNoteLinksParams) error
+	RestoreFiles(ctx context.Context, arg RestoreFilesParams) error
+	RestoreFolders(ctx context.Context, shaIds []string) error
+	RestoreNotes(ctx context.Context, shaIds []string) error
+	RetryReminder(ctx context.Context, arg RetryReminderParams) error
+	RevokePublicLink(ctx context.Context, arg RevokePublicLinkParams) (int64, error)
+	SearchNotes(ctx context.Context, arg SearchNotesParams) ([]SearchNotesRow, error)
+	TouchNote(ctx context.Context, arg TouchNoteParams) error
+	TrashFiles(ctx context.Context, arg TrashFilesParams) error
+	TrashFolders(ctx context.Context, arg TrashFoldersParams) error
+	TrashNotes(ctx context.Context, arg TrashNotesParams) error
+	UnresolveNoteLinks(ctx context.Context, shaIds []string) error
+	UpdateFile(ctx context.Context, arg UpdateFileParams) (File, error)
+	UpdateFilePathPrefix(ctx context.Context, arg UpdateFilePathPrefixParams) error
+	UpdateFolderParentBySha(ctx context.Context, arg UpdateFolderParentByShaParams) error
+	UpdateNote(ctx context.Context, arg UpdateNoteParams) (Note, error)
+	UpsertShare(ctx context.Context, arg UpsertShareParams) (Share, error)
+	UpsertTag(ctx context.Context, arg UpsertTagParams) (Tag, error)
+	UpsertTemplate(ctx context.Context, arg UpsertTemplateParams) (Template, error)
+	UpsertUserSettings(ctx context.Context, arg UpsertUserSettingsParams) (UserSetting, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -9,15 +9,3027 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
+)
+
+const addBlobReference = `-- name: AddBlobReference :one
+INSERT INTO blobs (checksum, size, ref_count, created_at, updated_at)
+VALUES ($1, $2, 1, $3, $3)
+ON CONFLICT (checksum) DO UPDATE SET ref_count = blobs.ref_count + 1, updated_at = EXCLUDED.updated_at
+RETURNING checksum, size, ref_count, stored_at, created_at, updated_at
+`
+
+type AddBlobReferenceParams struct {
+	Checksum  string
+	Size      int64
+	CreatedAt time.Time
+}
+
+func (q *Queries) AddBlobReference(ctx context.Context, arg AddBlobReferenceParams) (Blob, error) {
+	row := q.db.QueryRowContext(ctx, addBlobReference, arg.Checksum, arg.Size, arg.CreatedAt)
+	var i Blob
+	err := row.Scan(
+		&i.Checksum,
+		&i.Size,
+		&i.RefCount,
+		&i.StoredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const attachTag = `-- name: AttachTag :exec
+INSERT INTO note_tags (file_sha_id, tag_id, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+type AttachTagParams struct {
+	FileShaID string
+	TagID     int32
+	CreatedAt time.Time
+}
+
+func (q *Queries) AttachTag(ctx context.Context, arg AttachTagParams) error {
+	_, err := q.db.ExecContext(ctx, attachTag, arg.FileShaID, arg.TagID, arg.CreatedAt)
+	return err
+}
+
+const claimDueReminders = `-- name: ClaimDueReminders :many
+UPDATE reminders SET claimed_until = $1, attempts = attempts + 1
+WHERE id IN (
+    SELECT reminders.id FROM reminders
+    JOIN files ON files.sha_id = reminders.file_sha_id AND files.deleted_at IS NULL
+    WHERE reminders.sent_at IS NULL AND reminders.remind_at <= $2 AND reminders.attempts < $3
+    AND (reminders.claimed_until IS NULL OR reminders.claimed_until <= $2)
+    ORDER BY reminders.remind_at
+    LIMIT $4
+    FOR UPDATE OF reminders SKIP LOCKED
+)
+RETURNING id, file_sha_id, user_id, due_at, offset_minutes, remind_at, sent_at, claimed_until, attempts, last_error, delivered_channels, created_at
+`
+
+type ClaimDueRemindersParams struct {
+	ClaimedUntil sql.NullTime
+	Now          time.Time
+	MaxAttempts  int32
+	MaxCount     int32
+}
+
+func (q *Queries) ClaimDueReminders(ctx context.Context, arg ClaimDueRemindersParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, claimDueReminders,
+		arg.ClaimedUntil,
+		arg.Now,
+		arg.MaxAttempts,
+		arg.MaxCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileShaID,
+			&i.UserID,
+			&i.DueAt,
+			&i.OffsetMinutes,
+			&i.RemindAt,
+			&i.SentAt,
+			&i.ClaimedUntil,
+			&i.Attempts,
+			&i.LastError,
+			pq.Array(&i.DeliveredChannels),
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countFilesByName = `-- name: CountFilesByName :one
+SELECT count(*) FROM files
+WHERE user_id = $1 AND folder_sha_id = $2 AND name = $3 AND sha_id <> $4 AND deleted_at IS NULL
+`
+
+type CountFilesByNameParams struct {
+	UserID      int32
+	FolderShaID string
+	Name        string
+	ShaID       string
+}
+
+func (q *Queries) CountFilesByName(ctx context.Context, arg CountFilesByNameParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFilesByName,
+		arg.UserID,
+		arg.FolderShaID,
+		arg.Name,
+		arg.ShaID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFolderChildren = `-- name: CountFolderChildren :one
+SELECT count(*) FROM files
+WHERE user_id = $1 AND folder_sha_id = $2 AND deleted_at IS NULL
+`
+
+type CountFolderChildrenParams struct {
+	UserID      int32
+	FolderShaID string
+}
+
+func (q *Queries) CountFolderChildren(ctx context.Context, arg CountFolderChildrenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFolderChildren, arg.UserID, arg.FolderShaID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAttachment = `-- name: CreateAttachment :one
+INSERT INTO attachments (sha_id, note_sha_id, user_id, name, mime_type, size, checksum, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, sha_id, note_sha_id, user_id, name, mime_type, size, checksum, created_at
+`
+
+type CreateAttachmentParams struct {
+	ShaID     string
+	NoteShaID string
+	UserID    int32
+	Name      string
+	MimeType  string
+	Size      int64
+	Checksum  string
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error) {
+	row := q.db.QueryRowContext(ctx, createAttachment,
+		arg.ShaID,
+		arg.NoteShaID,
+		arg.UserID,
+		arg.Name,
+		arg.MimeType,
+		arg.Size,
+		arg.Checksum,
+		arg.CreatedAt,
+	)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.ShaID,
+		&i.NoteShaID,
+		&i.UserID,
+		&i.Name,
+		&i.MimeType,
+		&i.Size,
+		&i.Checksum,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createFile = `-- name: CreateFile :one
+INSERT INTO files (folder_sha_id, name, type, sha_id, path, user_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at
+`
+
+type CreateFileParams struct {
+	FolderShaID string
+	Name        string
+	Type        string
+	ShaID       string
+	Path        string
+	UserID      int32
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, createFile,
+		arg.FolderShaID,
+		arg.Name,
+		arg.Type,
+		arg.ShaID,
+		arg.Path,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.FolderShaID,
+		&i.Name,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ShaID,
+		&i.Path,
+		&i.UserID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createFolder = `-- name: CreateFolder :one
+INSERT INTO folders (sha_id, parent_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, sha_id, parent_id, created_at, updated_at, deleted_at
+`
+
+type CreateFolderParams struct {
+	ShaID     string
+	ParentID  sql.NullInt32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) CreateFolder(ctx context.Context, arg CreateFolderParams) (Folder, error) {
+	row := q.db.QueryRowContext(ctx, createFolder,
+		arg.ShaID,
+		arg.ParentID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Folder
+	err := row.Scan(
+		&i.ID,
+		&i.ShaID,
+		&i.ParentID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createNote = `-- name: CreateNote :one
+INSERT INTO notes (file_sha_id, note, created_at, updated_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, file_sha_id, note, created_at, updated_at, deleted_at
+`
+
+type CreateNoteParams struct {
+	FileShaID string
+	Note      sql.NullString
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, createNote,
+		arg.FileShaID,
+		arg.Note,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.FileShaID,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createNoteLink = `-- name: CreateNoteLink :exec
+INSERT INTO note_links (source_sha_id, target_sha_id, user_id, target, heading, alias, line, snippet, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type CreateNoteLinkParams struct {
+	SourceShaID string
+	TargetShaID sql.NullString
+	UserID      int32
+	Target      string
+	Heading     string
+	Alias       string
+	Line        int32
+	Snippet     string
+	CreatedAt   time.Time
+}
+
+func (q *Queries) CreateNoteLink(ctx context.Context, arg CreateNoteLinkParams) error {
+	_, err := q.db.ExecContext(ctx, createNoteLink,
+		arg.SourceShaID,
+		arg.TargetShaID,
+		arg.UserID,
+		arg.Target,
+		arg.Heading,
+		arg.Alias,
+		arg.Line,
+		arg.Snippet,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const createNoteRevision = `-- name: CreateNoteRevision :one
+INSERT INTO note_revisions (file_sha_id, note, created_at)
+VALUES ($1, $2, $3)
+RETURNING id, file_sha_id, note, created_at
+`
+
+type CreateNoteRevisionParams struct {
+	FileShaID string
+	Note      sql.NullString
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateNoteRevision(ctx context.Context, arg CreateNoteRevisionParams) (NoteRevision, error) {
+	row := q.db.QueryRowContext(ctx, createNoteRevision, arg.FileShaID, arg.Note, arg.CreatedAt)
+	var i NoteRevision
+	err := row.Scan(
+		&i.ID,
+		&i.FileShaID,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createNotification = `-- name: CreateNotification :one
+INSERT INTO notifications (user_id, file_sha_id, title, body, created_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, file_sha_id, title, body, read_at, created_at
+`
+
+type CreateNotificationParams struct {
+	UserID    int32
+	FileShaID string
+	Title     string
+	Body      string
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error) {
+	row := q.db.QueryRowContext(ctx, createNotification,
+		arg.UserID,
+		arg.FileShaID,
+		arg.Title,
+		arg.Body,
+		arg.CreatedAt,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileShaID,
+		&i.Title,
+		&i.Body,
+		&i.ReadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPublicLink = `-- name: CreatePublicLink :one
+INSERT INTO public_links (token, file_sha_id, user_id, password, expires_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, token, file_sha_id, user_id, password, expires_at, revoked_at, view_count, created_at, updated_at
+`
+
+type CreatePublicLinkParams struct {
+	Token     string
+	FileShaID string
+	UserID    int32
+	Password  sql.NullString
+	ExpiresAt sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) CreatePublicLink(ctx context.Context, arg CreatePublicLinkParams) (PublicLink, error) {
+	row := q.db.QueryRowContext(ctx, createPublicLink,
+		arg.Token,
+		arg.FileShaID,
+		arg.UserID,
+		arg.Password,
+		arg.ExpiresAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i PublicLink
+	err := row.Scan(
+		&i.ID,
+		&i.Token,
+		&i.FileShaID,
+		&i.UserID,
+		&i.Password,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.ViewCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createReminder = `-- name: CreateReminder :one
+INSERT INTO reminders (file_sha_id, user_id, due_at, offset_minutes, remind_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, file_sha_id, user_id, due_at, offset_minutes, remind_at, sent_at, claimed_until, attempts, last_error, delivered_channels, created_at
+`
+
+type CreateReminderParams struct {
+	FileShaID     string
+	UserID        int32
+	DueAt         time.Time
+	OffsetMinutes int32
+	RemindAt      time.Time
+	CreatedAt     time.Time
+}
+
+func (q *Queries) CreateReminder(ctx context.Context, arg CreateReminderParams) (Reminder, error) {
+	row := q.db.QueryRowContext(ctx, createReminder,
+		arg.FileShaID,
+		arg.UserID,
+		arg.DueAt,
+		arg.OffsetMinutes,
+		arg.RemindAt,
+		arg.CreatedAt,
+	)
+	var i Reminder
+	err := row.Scan(
+		&i.ID,
+		&i.FileShaID,
+		&i.UserID,
+		&i.DueAt,
+		&i.OffsetMinutes,
+		&i.RemindAt,
+		&i.SentAt,
+		&i.ClaimedUntil,
+		&i.Attempts,
+		&i.LastError,
+		pq.Array(&i.DeliveredChannels),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteAttachment = `-- name: DeleteAttachment :one
+DELETE FROM attachments
+WHERE sha_id = $1
+RETURNING checksum
+`
+
+func (q *Queries) DeleteAttachment(ctx context.Context, shaID string) (string, error) {
+	row := q.db.QueryRowContext(ctx, deleteAttachment, shaID)
+	var checksum string
+	err := row.Scan(&checksum)
+	return checksum, err
+}
+
+const deleteBlob = `-- name: DeleteBlob :exec
+DELETE FROM blobs
+WHERE checksum = $1
+`
+
+func (q *Queries) DeleteBlob(ctx context.Context, checksum string) error {
+	_, err := q.db.ExecContext(ctx, deleteBlob, checksum)
+	return err
+}
+
+const deleteFile = `-- name: DeleteFile :exec
+DELETE FROM files
+WHERE sha_id = $1 AND user_id = $2
+`
+
+type DeleteFileParams struct {
+	ShaID  string
+	UserID int32
+}
+
+func (q *Queries) DeleteFile(ctx context.Context, arg DeleteFileParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFile, arg.ShaID, arg.UserID)
+	return err
+}
+
+const deleteFileShares = `-- name: DeleteFileShares :exec
+DELETE FROM shares
+WHERE file_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteFileShares(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deleteFileShares, pq.Array(shaIds))
+	return err
+}
+
+const deleteFiles = `-- name: DeleteFiles :exec
+DELETE FROM files
+WHERE user_id = $1 AND sha_id = ANY($2::varchar[])
+`
+
+type DeleteFilesParams struct {
+	UserID int32
+	ShaIds []string
+}
+
+func (q *Queries) DeleteFiles(ctx context.Context, arg DeleteFilesParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFiles, arg.UserID, pq.Array(arg.ShaIds))
+	return err
+}
+
+const deleteFolders = `-- name: DeleteFolders :exec
+DELETE FROM folders
+WHERE sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteFolders(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deleteFolders, pq.Array(shaIds))
+	return err
+}
+
+const deleteNote = `-- name: DeleteNote :exec
+DELETE FROM notes
+WHERE file_sha_id = $1
+`
+
+func (q *Queries) DeleteNote(ctx context.Context, fileShaID string) error {
+	_, err := q.db.ExecContext(ctx, deleteNote, fileShaID)
+	return err
+}
+
+const deleteNoteLinks = `-- name: DeleteNoteLinks :exec
+DELETE FROM note_links
+WHERE source_sha_id = $1
+`
+
+func (q *Queries) DeleteNoteLinks(ctx context.Context, sourceShaID string) error {
+	_, err := q.db.ExecContext(ctx, deleteNoteLinks, sourceShaID)
+	return err
+}
+
+const deleteNoteReminders = `-- name: DeleteNoteReminders :exec
+DELETE FROM reminders
+WHERE file_sha_id = $1 AND user_id = $2
+`
+
+type DeleteNoteRemindersParams struct {
+	FileShaID string
+	UserID    int32
+}
+
+func (q *Queries) DeleteNoteReminders(ctx context.Context, arg DeleteNoteRemindersParams) error {
+	_, err := q.db.ExecContext(ctx, deleteNoteReminders, arg.FileShaID, arg.UserID)
+	return err
+}
+
+const deleteNoteRevisions = `-- name: DeleteNoteRevisions :exec
+DELETE FROM note_revisions
+WHERE file_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteNoteRevisions(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deleteNoteRevisions, pq.Array(shaIds))
+	return err
+}
+
+const deleteNoteTags = `-- name: DeleteNoteTags :exec
+DELETE FROM note_tags
+WHERE file_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteNoteTags(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deleteNoteTags, pq.Array(shaIds))
+	return err
+}
+
+const deleteNotes = `-- name: DeleteNotes :exec
+DELETE FROM notes
+WHERE file_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteNotes(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deleteNotes, pq.Array(shaIds))
+	return err
+}
+
+const deleteNotesLinks = `-- name: DeleteNotesLinks :exec
+DELETE FROM note_links
+WHERE source_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteNotesLinks(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deleteNotesLinks, pq.Array(shaIds))
+	return err
+}
+
+const deletePublicLinks = `-- name: DeletePublicLinks :exec
+DELETE FROM public_links
+WHERE file_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeletePublicLinks(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deletePublicLinks, pq.Array(shaIds))
+	return err
+}
+
+const deleteReminders = `-- name: DeleteReminders :exec
+DELETE FROM reminders
+WHERE file_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteReminders(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deleteReminders, pq.Array(shaIds))
+	return err
+}
+
+const deleteShare = `-- name: DeleteShare :execrows
+DELETE FROM shares
+WHERE file_sha_id = $1 AND user_id = $2
+`
+
+type DeleteShareParams struct {
+	FileShaID string
+	UserID    int32
+}
+
+func (q *Queries) DeleteShare(ctx context.Context, arg DeleteShareParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteShare, arg.FileShaID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTag = `-- name: DeleteTag :exec
+DELETE FROM tags
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteTagParams struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) DeleteTag(ctx context.Context, arg DeleteTagParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTag, arg.ID, arg.UserID)
+	return err
+}
+
+const deleteTagNotes = `-- name: DeleteTagNotes :exec
+DELETE FROM note_tags
+WHERE tag_id = $1
+`
+
+func (q *Queries) DeleteTagNotes(ctx context.Context, tagID int32) error {
+	_, err := q.db.ExecContext(ctx, deleteTagNotes, tagID)
+	return err
+}
+
+const deleteTemplate = `-- name: DeleteTemplate :execrows
+DELETE FROM templates
+WHERE file_sha_id = $1
+`
+
+func (q *Queries) DeleteTemplate(ctx context.Context, fileShaID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTemplate, fileShaID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTemplates = `-- name: DeleteTemplates :exec
+DELETE FROM templates
+WHERE file_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteTemplates(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplates, pq.Array(shaIds))
+	return err
+}
+
+const detachTag = `-- name: DetachTag :execrows
+DELETE FROM note_tags
+WHERE file_sha_id = $1 AND tag_id = $2
+`
+
+type DetachTagParams struct {
+	FileShaID string
+	TagID     int32
+}
+
+func (q *Queries) DetachTag(ctx context.Context, arg DetachTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, detachTag, arg.FileShaID, arg.TagID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const findAttachment = `-- name: FindAttachment :one
+SELECT id, sha_id, note_sha_id, user_id, name, mime_type, size, checksum, created_at FROM attachments
+WHERE sha_id = $1 LIMIT 1
+`
+
+func (q *Queries) FindAttachment(ctx context.Context, shaID string) (Attachment, error) {
+	row := q.db.QueryRowContext(ctx, findAttachment, shaID)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.ShaID,
+		&i.NoteShaID,
+		&i.UserID,
+		&i.Name,
+		&i.MimeType,
+		&i.Size,
+		&i.Checksum,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findFile = `-- name: FindFile :one
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM files
+WHERE sha_id = $1 AND user_id = $2 AND deleted_at IS NULL LIMIT 1
+`
+
+type FindFileParams struct {
+	ShaID  string
+	UserID int32
+}
+
+func (q *Queries) FindFile(ctx context.Context, arg FindFileParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, findFile, arg.ShaID, arg.UserID)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.FolderShaID,
+		&i.Name,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ShaID,
+		&i.Path,
+		&i.UserID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findFileByPath = `-- name: FindFileByPath :one
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM files
+WHERE user_id = $1 AND path = $2 AND deleted_at IS NULL LIMIT 1
+`
+
+type FindFileByPathParams struct {
+	UserID int32
+	Path   string
+}
+
+func (q *Queries) FindFileByPath(ctx context.Context, arg FindFileByPathParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, findFileByPath, arg.UserID, arg.Path)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.FolderShaID,
+		&i.Name,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ShaID,
+		&i.Path,
+		&i.UserID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findFileByShaID = `-- name: FindFileByShaID :one
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM files
+WHERE sha_id = $1 AND deleted_at IS NULL LIMIT 1
+`
+
+func (q *Queries) FindFileByShaID(ctx context.Context, shaID string) (File, error) {
+	row := q.db.QueryRowContext(ctx, findFileByShaID, shaID)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.FolderShaID,
+		&i.Name,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ShaID,
+		&i.Path,
+		&i.UserID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findFileForUpdate = `-- name: FindFileForUpdate :one
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM files
+WHERE sha_id = $1 AND user_id = $2 AND deleted_at IS NULL LIMIT 1
+FOR UPDATE
+`
+
+type FindFileForUpdateParams struct {
+	ShaID  string
+	UserID int32
+}
+
+func (q *Queries) FindFileForUpdate(ctx context.Context, arg FindFileForUpdateParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, findFileForUpdate, arg.ShaID, arg.UserID)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.FolderShaID,
+		&i.Name,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ShaID,
+		&i.Path,
+		&i.UserID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findFolder = `-- name: FindFolder :one
+SELECT folders.id, folders.parent_id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, files.created_at, files.updated_at
+FROM folders
+JOIN files ON files.sha_id = folders.sha_id
+WHERE files.sha_id = $1 AND files.user_id = $2 AND files.type = 'folder' AND files.deleted_at IS NULL LIMIT 1
+`
+
+type FindFolderParams struct {
+	ShaID  string
+	UserID int32
+}
+
+type FindFolderRow struct {
+	ID          int32
+	ParentID    sql.NullInt32
+	ShaID       string
+	FolderShaID string
+	UserID      int32
+	Name        string
+	Path        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) FindFolder(ctx context.Context, arg FindFolderParams) (FindFolderRow, error) {
+	row := q.db.QueryRowContext(ctx, findFolder, arg.ShaID, arg.UserID)
+	var i FindFolderRow
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.ShaID,
+		&i.FolderShaID,
+		&i.UserID,
+		&i.Name,
+		&i.Path,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findLinkTargets = `-- name: FindLinkTargets :many
+SELECT sha_id, folder_sha_id, path FROM files
+WHERE user_id = $1 AND type = 'note' AND deleted_at IS NULL
+AND (sha_id = $2::text OR right(lower(path), length($2::text) + 1) = '/' || lower($2::text))
+`
+
+type FindLinkTargetsParams struct {
+	UserID int32
+	Target string
+}
+
+type FindLinkTargetsRow struct {
+	ShaID       string
+	FolderShaID string
+	Path        string
+}
+
+func (q *Queries) FindLinkTargets(ctx context.Context, arg FindLinkTargetsParams) ([]FindLinkTargetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, findLinkTargets, arg.UserID, arg.Target)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindLinkTargetsRow
+	for rows.Next() {
+		var i FindLinkTargetsRow
+		if err := rows.Scan(
+			&i.ShaID,
+			&i.FolderShaID,
+			&i.Path,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findNote = `-- name: FindNote :one
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.sha_id = $1 AND files.user_id = $2 AND files.type = 'note' AND files.deleted_at IS NULL LIMIT 1
+`
+
+type FindNoteParams struct {
+	ShaID  string
+	UserID int32
+}
+
+type FindNoteRow struct {
+	ID          int32
+	ShaID       string
+	FolderShaID string
+	UserID      int32
+	Name        string
+	Path        string
+	Note        sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) FindNote(ctx context.Context, arg FindNoteParams) (FindNoteRow, error) {
+	row := q.db.QueryRowContext(ctx, findNote, arg.ShaID, arg.UserID)
+	var i FindNoteRow
+	err := row.Scan(
+		&i.ID,
+		&i.ShaID,
+		&i.FolderShaID,
+		&i.UserID,
+		&i.Name,
+		&i.Path,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findNoteForUpdate = `-- name: FindNoteForUpdate :one
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.sha_id = $1 AND files.user_id = $2 AND files.type = 'note' AND files.deleted_at IS NULL LIMIT 1
+FOR UPDATE OF notes
+`
+
+type FindNoteForUpdateParams struct {
+	ShaID  string
+	UserID int32
+}
+
+type FindNoteForUpdateRow struct {
+	ID          int32
+	ShaID       string
+	FolderShaID string
+	UserID      int32
+	Name        string
+	Path        string
+	Note        sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) FindNoteForUpdate(ctx context.Context, arg FindNoteForUpdateParams) (FindNoteForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, findNoteForUpdate, arg.ShaID, arg.UserID)
+	var i FindNoteForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.ShaID,
+		&i.FolderShaID,
+		&i.UserID,
+		&i.Name,
+		&i.Path,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findNoteRevision = `-- name: FindNoteRevision :one
+SELECT id, file_sha_id, note, created_at FROM note_revisions
+WHERE id = $1 AND file_sha_id = $2 LIMIT 1
+`
+
+type FindNoteRevisionParams struct {
+	ID        int32
+	FileShaID string
+}
+
+func (q *Queries) FindNoteRevision(ctx context.Context, arg FindNoteRevisionParams) (NoteRevision, error) {
+	row := q.db.QueryRowContext(ctx, findNoteRevision, arg.ID, arg.FileShaID)
+	var i NoteRevision
+	err := row.Scan(
+		&i.ID,
+		&i.FileShaID,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findPublicLink = `-- name: FindPublicLink :one
+SELECT id, token, file_sha_id, user_id, password, expires_at, revoked_at, view_count, created_at, updated_at FROM public_links
+WHERE token = $1 AND revoked_at IS NULL LIMIT 1
+`
+
+func (q *Queries) FindPublicLink(ctx context.Context, token string) (PublicLink, error) {
+	row := q.db.QueryRowContext(ctx, findPublicLink, token)
+	var i PublicLink
+	err := row.Scan(
+		&i.ID,
+		&i.Token,
+		&i.FileShaID,
+		&i.UserID,
+		&i.Password,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.ViewCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findTag = `-- name: FindTag :one
+SELECT id, user_id, name, created_at, updated_at FROM tags
+WHERE id = $1 AND user_id = $2 LIMIT 1
+`
+
+type FindTagParams struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) FindTag(ctx context.Context, arg FindTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, findTag, arg.ID, arg.UserID)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findTemplate = `-- name: FindTemplate :one
+SELECT templates.file_sha_id, templates.user_id, templates.description, templates.created_at, templates.updated_at, users.username
+FROM templates
+JOIN users ON users.id = templates.user_id
+WHERE templates.file_sha_id = $1 LIMIT 1
+`
+
+type FindTemplateRow struct {
+	FileShaID   string
+	UserID      int32
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Username    string
+}
+
+func (q *Queries) FindTemplate(ctx context.Context, fileShaID string) (FindTemplateRow, error) {
+	row := q.db.QueryRowContext(ctx, findTemplate, fileShaID)
+	var i FindTemplateRow
+	err := row.Scan(
+		&i.FileShaID,
+		&i.UserID,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Username,
+	)
+	return i, err
+}
+
+const findTrashedFile = `-- name: FindTrashedFile :one
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM files
+WHERE sha_id = $1 AND user_id = $2 AND deleted_at IS NOT NULL LIMIT 1
+`
+
+type FindTrashedFileParams struct {
+	ShaID  string
+	UserID int32
+}
+
+func (q *Queries) FindTrashedFile(ctx context.Context, arg FindTrashedFileParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, findTrashedFile, arg.ShaID, arg.UserID)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.FolderShaID,
+		&i.Name,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ShaID,
+		&i.Path,
+		&i.UserID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const findUser = `-- name: FindUser :one
+SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) FindUser(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
+const findUserByEmail = `-- name: FindUserByEmail :one
+SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) FindUserByEmail(ctx context.Context, email sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
+const findUserByPhoneNumber = `-- name: FindUserByPhoneNumber :one
+SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
+WHERE phone_number = $1 LIMIT 1
+`
+
+func (q *Queries) FindUserByPhoneNumber(ctx context.Context, phoneNumber sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUserByPhoneNumber, phoneNumber)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
+const findUserByUsername = `-- name: FindUserByUsername :one
+SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
+WHERE username = $1 LIMIT 1
+`
+
+func (q *Queries) FindUserByUsername(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
+const findUserByUsernameOrEmailOrPhoneNumber = `-- name: FindUserByUsernameOrEmailOrPhoneNumber :one
+SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
+WHERE username = $1 OR( email = $2 and email IS NOT NULL) OR (phone_number = $3 and phone_number IS NOT NULL) LIMIT 1
+`
+
+type FindUserByUsernameOrEmailOrPhoneNumberParams struct {
+	Username    string
+	Email       sql.NullString
+	PhoneNumber sql.NullString
+}
+
+func (q *Queries) FindUserByUsernameOrEmailOrPhoneNumber(ctx context.Context, arg FindUserByUsernameOrEmailOrPhoneNumberParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUserByUsernameOrEmailOrPhoneNumber, arg.Username, arg.Email, arg.PhoneNumber)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
+const findUserSettings = `-- name: FindUserSettings :one
+SELECT user_id, note_revision_retention, updated_at FROM user_settings
+WHERE user_id = $1 LIMIT 1
+`
+
+func (q *Queries) FindUserSettings(ctx context.Context, userID int32) (UserSetting, error) {
+	row := q.db.QueryRowContext(ctx, findUserSettings, userID)
+	var i UserSetting
+	err := row.Scan(
+		&i.UserID,
+		&i.NoteRevisionRetention,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getAttachmentUsage = `-- name: GetAttachmentUsage :one
+SELECT COUNT(*) AS count, COALESCE(SUM(size), 0)::bigint AS size FROM attachments
+WHERE user_id = $1
+`
+
+type GetAttachmentUsageRow struct {
+	Count int64
+	Size  int64
+}
+
+func (q *Queries) GetAttachmentUsage(ctx context.Context, userID int32) (GetAttachmentUsageRow, error) {
+	row := q.db.QueryRowContext(ctx, getAttachmentUsage, userID)
+	var i GetAttachmentUsageRow
+	err := row.Scan(
+		&i.Count,
+		&i.Size,
+	)
+	return i, err
+}
+
+const getDeletedFiles = `-- name: GetDeletedFiles :many
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM files
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetDeletedFiles(ctx context.Context, userID int32) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, getDeletedFiles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.FolderShaID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ShaID,
+			&i.Path,
+			&i.UserID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredFiles = `-- name: GetExpiredFiles :many
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM files
+WHERE deleted_at < $1::timestamp
+ORDER BY user_id
+`
+
+func (q *Queries) GetExpiredFiles(ctx context.Context, before time.Time) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredFiles, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.FolderShaID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ShaID,
+			&i.Path,
+			&i.UserID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileAncestors = `-- name: GetFileAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT files.sha_id, files.folder_sha_id, ARRAY[files.sha_id]::varchar[] AS visited
+    FROM files
+    WHERE files.sha_id = $1 AND files.user_id = $2
+    UNION ALL
+    SELECT files.sha_id, files.folder_sha_id, ancestors.visited || files.sha_id
+    FROM files
+    JOIN ancestors ON files.sha_id = ancestors.folder_sha_id
+    WHERE files.user_id = $2 AND NOT files.sha_id = ANY(ancestors.visited)
+)
+SELECT sha_id FROM ancestors
+`
+
+type GetFileAncestorsParams struct {
+	ShaID  string
+	UserID int32
+}
+
+func (q *Queries) GetFileAncestors(ctx context.Context, arg GetFileAncestorsParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getFileAncestors, arg.ShaID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var sha_id string
+		if err := rows.Scan(&sha_id); err != nil {
+			return nil, err
+		}
+		items = append(items, sha_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileDescendants = `-- name: GetFileDescendants :many
+WITH RECURSIVE tree AS (
+    SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, files.deleted_at, ARRAY[files.sha_id]::varchar[] AS visited
+    FROM files
+    WHERE files.folder_sha_id = $1 AND files.user_id = $2
+    UNION ALL
+    SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, files.deleted_at, tree.visited || files.sha_id
+    FROM files
+    JOIN tree ON files.folder_sha_id = tree.sha_id
+    WHERE files.user_id = $2 AND NOT files.sha_id = ANY(tree.visited)
+)
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM tree
+ORDER BY path
+`
+
+type GetFileDescendantsParams struct {
+	FolderShaID string
+	UserID      int32
+	DeletedAt   sql.NullTime
+}
+
+type GetFileDescendantsRow struct {
+	ID          int32
+	FolderShaID string
+	Name        string
+	Type        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ShaID       string
+	Path        string
+	UserID      int32
+	DeletedAt   sql.NullTime
+}
+
+func (q *Queries) GetFileDescendants(ctx context.Context, arg GetFileDescendantsParams) ([]GetFileDescendantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFileDescendants, arg.FolderShaID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFileDescendantsRow
+	for rows.Next() {
+		var i GetFileDescendantsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FolderShaID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ShaID,
+			&i.Path,
+			&i.UserID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileShares = `-- name: GetFileShares :many
+SELECT shares.id, shares.file_sha_id, shares.owner_id, shares.user_id, shares.role, shares.created_at, shares.updated_at, users.username
+FROM shares
+JOIN users ON users.id = shares.user_id
+WHERE shares.file_sha_id = $1
+ORDER BY users.username
+`
+
+type GetFileSharesRow struct {
+	ID        int32
+	FileShaID string
+	OwnerID   int32
+	UserID    int32
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Username  string
+}
+
+func (q *Queries) GetFileShares(ctx context.Context, fileShaID string) ([]GetFileSharesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFileShares, fileShaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFileSharesRow
+	for rows.Next() {
+		var i GetFileSharesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileShaID,
+			&i.OwnerID,
+			&i.UserID,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileTree = `-- name: GetFileTree :many
+WITH RECURSIVE tree AS (
+    SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, 1 AS depth, ARRAY[files.sha_id]::varchar[] AS visited
+    FROM files
+    WHERE files.folder_sha_id = $1 AND files.user_id = $2 AND files.deleted_at IS NULL
+    UNION ALL
+    SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, tree.depth + 1, tree.visited || files.sha_id
+    FROM files
+    JOIN tree ON files.folder_sha_id = tree.sha_id
+    WHERE files.user_id = $2 AND files.deleted_at IS NULL AND tree.depth < $3::int AND NOT files.sha_id = ANY(tree.visited)
+)
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, depth FROM tree
+ORDER BY depth, type, name
+`
+
+type GetFileTreeParams struct {
+	FolderShaID string
+	UserID      int32
+	MaxDepth    int32
+}
+
+type GetFileTreeRow struct {
+	ID          int32
+	FolderShaID string
+	Name        string
+	Type        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ShaID       string
+	Path        string
+	UserID      int32
+	Depth       int32
+}
+
+func (q *Queries) GetFileTree(ctx context.Context, arg GetFileTreeParams) ([]GetFileTreeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFileTree, arg.FolderShaID, arg.UserID, arg.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFileTreeRow
+	for rows.Next() {
+		var i GetFileTreeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FolderShaID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ShaID,
+			&i.Path,
+			&i.UserID,
+			&i.Depth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFolderChildren = `-- name: GetFolderChildren :many
+SELECT id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at FROM files
+WHERE user_id = $1 AND folder_sha_id = $2 AND deleted_at IS NULL
+ORDER BY type, name
+`
+
+type GetFolderChildrenParams struct {
+	UserID      int32
+	FolderShaID string
+}
+
+func (q *Queries) GetFolderChildren(ctx context.Context, arg GetFolderChildrenParams) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, getFolderChildren, arg.UserID, arg.FolderShaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.FolderShaID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ShaID,
+			&i.Path,
+			&i.UserID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLinkingNotes = `-- name: GetLinkingNotes :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE notes.file_sha_id IN (SELECT source_sha_id FROM note_links WHERE target_sha_id = ANY($1::varchar[]))
+ORDER BY files.sha_id
+FOR UPDATE OF notes
+`
+
+type GetLinkingNotesRow struct {
+	ID          int32
+	ShaID       string
+	FolderShaID string
+	UserID      int32
+	Name        string
+	Path        string
+	Note        sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) GetLinkingNotes(ctx context.Context, shaIds []string) ([]GetLinkingNotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getLinkingNotes, pq.Array(shaIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLinkingNotesRow
+	for rows.Next() {
+		var i GetLinkingNotesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShaID,
+			&i.FolderShaID,
+			&i.UserID,
+			&i.Name,
+			&i.Path,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoteAttachments = `-- name: GetNoteAttachments :many
+SELECT id, sha_id, note_sha_id, user_id, name, mime_type, size, checksum, created_at FROM attachments
+WHERE note_sha_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetNoteAttachments(ctx context.Context, noteShaID string) ([]Attachment, error) {
+	rows, err := q.db.QueryContext(ctx, getNoteAttachments, noteShaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShaID,
+			&i.NoteShaID,
+			&i.UserID,
+			&i.Name,
+			&i.MimeType,
+			&i.Size,
+			&i.Checksum,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoteBacklinks = `-- name: GetNoteBacklinks :many
+SELECT note_links.id, note_links.source_sha_id, note_links.target_sha_id, note_links.user_id, note_links.target, note_links.heading, note_links.alias, note_links.line, note_links.snippet, note_links.created_at, files.name AS note_name, files.path AS note_path FROM note_links
+JOIN files ON files.sha_id = note_links.source_sha_id AND files.deleted_at IS NULL
+WHERE note_links.target_sha_id = $1
+ORDER BY files.path, note_links.line, note_links.id
+`
+
+type GetNoteBacklinksRow struct {
+	ID          int32
+	SourceShaID string
+	TargetShaID sql.NullString
+	UserID      int32
+	Target      string
+	Heading     string
+	Alias       string
+	Line        int32
+	Snippet     string
+	CreatedAt   time.Time
+	NoteName    string
+	NotePath    string
+}
+
+func (q *Queries) GetNoteBacklinks(ctx context.Context, targetShaID sql.NullString) ([]GetNoteBacklinksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNoteBacklinks, targetShaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNoteBacklinksRow
+	for rows.Next() {
+		var i GetNoteBacklinksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceShaID,
+			&i.TargetShaID,
+			&i.UserID,
+			&i.Target,
+			&i.Heading,
+			&i.Alias,
+			&i.Line,
+			&i.Snippet,
+			&i.CreatedAt,
+			&i.NoteName,
+			&i.NotePath,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoteGraphEdges = `-- name: GetNoteGraphEdges :many
+SELECT note_links.source_sha_id, note_links.target_sha_id, COUNT(*) AS count FROM note_links
+JOIN files sources ON sources.sha_id = note_links.source_sha_id AND sources.deleted_at IS NULL
+JOIN files targets ON targets.sha_id = note_links.target_sha_id AND targets.deleted_at IS NULL
+WHERE note_links.user_id = $1
+GROUP BY note_links.source_sha_id, note_links.target_sha_id
+ORDER BY note_links.source_sha_id, note_links.target_sha_id
+`
+
+type GetNoteGraphEdgesRow struct {
+	SourceShaID string
+	TargetShaID sql.NullString
+	Count       int64
+}
+
+func (q *Queries) GetNoteGraphEdges(ctx context.Context, userID int32) ([]GetNoteGraphEdgesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNoteGraphEdges, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNoteGraphEdgesRow
+	for rows.Next() {
+		var i GetNoteGraphEdgesRow
+		if err := rows.Scan(
+			&i.SourceShaID,
+			&i.TargetShaID,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoteGraphNodes = `-- name: GetNoteGraphNodes :many
+SELECT sha_id, name, path FROM files
+WHERE user_id = $1 AND type = 'note' AND deleted_at IS NULL
+ORDER BY path
+`
+
+type GetNoteGraphNodesRow struct {
+	ShaID string
+	Name  string
+	Path  string
+}
+
+func (q *Queries) GetNoteGraphNodes(ctx context.Context, userID int32) ([]GetNoteGraphNodesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNoteGraphNodes, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNoteGraphNodesRow
+	for rows.Next() {
+		var i GetNoteGraphNodesRow
+		if err := rows.Scan(
+			&i.ShaID,
+			&i.Name,
+			&i.Path,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoteLinks = `-- name: GetNoteLinks :many
+SELECT note_links.id, note_links.source_sha_id, note_links.target_sha_id, note_links.user_id, note_links.target, note_links.heading, note_links.alias, note_links.line, note_links.snippet, note_links.created_at, files.name AS note_name, files.path AS note_path FROM note_links
+LEFT JOIN files ON files.sha_id = note_links.target_sha_id AND files.deleted_at IS NULL
+WHERE note_links.source_sha_id = $1
+ORDER BY note_links.line, note_links.id
+`
+
+type GetNoteLinksRow struct {
+	ID          int32
+	SourceShaID string
+	TargetShaID sql.NullString
+	UserID      int32
+	Target      string
+	Heading     string
+	Alias       string
+	Line        int32
+	Snippet     string
+	CreatedAt   time.Time
+	NoteName    sql.NullString
+	NotePath    sql.NullString
+}
+
+func (q *Queries) GetNoteLinks(ctx context.Context, sourceShaID string) ([]GetNoteLinksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNoteLinks, sourceShaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNoteLinksRow
+	for rows.Next() {
+		var i GetNoteLinksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceShaID,
+			&i.TargetShaID,
+			&i.UserID,
+			&i.Target,
+			&i.Heading,
+			&i.Alias,
+			&i.Line,
+			&i.Snippet,
+			&i.CreatedAt,
+			&i.NoteName,
+			&i.NotePath,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoteReminders = `-- name: GetNoteReminders :many
+SELECT id, file_sha_id, user_id, due_at, offset_minutes, remind_at, sent_at, claimed_until, attempts, last_error, delivered_channels, created_at FROM reminders
+WHERE file_sha_id = $1 AND user_id = $2
+ORDER BY remind_at, id
+`
+
+type GetNoteRemindersParams struct {
+	FileShaID string
+	UserID    int32
+}
+
+func (q *Queries) GetNoteReminders(ctx context.Context, arg GetNoteRemindersParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, getNoteReminders, arg.FileShaID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileShaID,
+			&i.UserID,
+			&i.DueAt,
+			&i.OffsetMinutes,
+			&i.RemindAt,
+			&i.SentAt,
+			&i.ClaimedUntil,
+			&i.Attempts,
+			&i.LastError,
+			pq.Array(&i.DeliveredChannels),
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoteRevisions = `-- name: GetNoteRevisions :many
+SELECT id, file_sha_id, note, created_at FROM note_revisions
+WHERE file_sha_id = $1
+ORDER BY id DESC
+`
+
+func (q *Queries) GetNoteRevisions(ctx context.Context, fileShaID string) ([]NoteRevision, error) {
+	rows, err := q.db.QueryContext(ctx, getNoteRevisions, fileShaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NoteRevision
+	for rows.Next() {
+		var i NoteRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileShaID,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoteTags = `-- name: GetNoteTags :many
+SELECT tags.id, tags.user_id, tags.name, tags.created_at, tags.updated_at
+FROM tags
+JOIN note_tags ON note_tags.tag_id = tags.id
+WHERE note_tags.file_sha_id = $1 AND tags.user_id = $2
+ORDER BY tags.name
+`
+
+type GetNoteTagsParams struct {
+	FileShaID string
+	UserID    int32
+}
+
+func (q *Queries) GetNoteTags(ctx context.Context, arg GetNoteTagsParams) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, getNoteTags, arg.FileShaID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotes = `-- name: GetNotes :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = $1 AND files.type = 'note' AND files.deleted_at IS NULL
+ORDER BY files.path
+`
+
+type GetNotesRow struct {
+	ID          int32
+	ShaID       string
+	FolderShaID string
+	UserID      int32
+	Name        string
+	Path        string
+	Note        sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) GetNotes(ctx context.Context, userID int32) ([]GetNotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNotes, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNotesRow
+	for rows.Next() {
+		var i GetNotesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShaID,
+			&i.FolderShaID,
+			&i.UserID,
+			&i.Name,
+			&i.Path,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesByFolder = `-- name: GetNotesByFolder :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = $1 AND files.folder_sha_id = $2 AND files.type = 'note' AND files.deleted_at IS NULL
+ORDER BY files.name
+`
+
+type GetNotesByFolderParams struct {
+	UserID      int32
+	FolderShaID string
+}
+
+type GetNotesByFolderRow struct {
+	ID          int32
+	ShaID       string
+	FolderShaID string
+	UserID      int32
+	Name        string
+	Path        string
+	Note        sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) GetNotesByFolder(ctx context.Context, arg GetNotesByFolderParams) ([]GetNotesByFolderRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesByFolder, arg.UserID, arg.FolderShaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNotesByFolderRow
+	for rows.Next() {
+		var i GetNotesByFolderRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShaID,
+			&i.FolderShaID,
+			&i.UserID,
+			&i.Name,
+			&i.Path,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesByTags = `-- name: GetNotesByTags :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = $1 AND files.type = 'note' AND files.deleted_at IS NULL
+    AND ($2::varchar = '' OR files.folder_sha_id = $2::varchar)
+    AND files.sha_id IN (
+        SELECT note_tags.file_sha_id
+        FROM note_tags
+        JOIN tags ON tags.id = note_tags.tag_id
+        WHERE tags.user_id = $1 AND tags.name = ANY($3::varchar[])
+        GROUP BY note_tags.file_sha_id
+        HAVING count(DISTINCT tags.id) >= $4::int
+    )
+ORDER BY files.path
+`
+
+type GetNotesByTagsParams struct {
+	UserID      int32
+	FolderShaID string
+	TagNames    []string
+	MinMatches  int32
+}
+
+type GetNotesByTagsRow struct {
+	ID          int32
+	ShaID       string
+	FolderShaID string
+	UserID      int32
+	Name        string
+	Path        string
+	Note        sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) GetNotesByTags(ctx context.Context, arg GetNotesByTagsParams) ([]GetNotesByTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesByTags,
+		arg.UserID,
+		arg.FolderShaID,
+		pq.Array(arg.TagNames),
+		arg.MinMatches,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNotesByTagsRow
+	for rows.Next() {
+		var i GetNotesByTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShaID,
+			&i.FolderShaID,
+			&i.UserID,
+			&i.Name,
+			&i.Path,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotesWithOpenTasks = `-- name: GetNotesWithOpenTasks :many
+SELECT notes.id, files.sha_id, files.folder_sha_id, files.user_id, files.name, files.path, notes.note, notes.created_at, notes.updated_at
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = $1 AND files.type = 'note' AND files.deleted_at IS NULL AND notes.note LIKE '%[ ]%'
+ORDER BY files.path
+`
+
+type GetNotesWithOpenTasksRow struct {
+	ID          int32
+	ShaID       string
+	FolderShaID string
+	UserID      int32
+	Name        string
+	Path        string
+	Note        sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) GetNotesWithOpenTasks(ctx context.Context, userID int32) ([]GetNotesWithOpenTasksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNotesWithOpenTasks, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNotesWithOpenTasksRow
+	for rows.Next() {
+		var i GetNotesWithOpenTasksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShaID,
+			&i.FolderShaID,
+			&i.UserID,
+			&i.Name,
+			&i.Path,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotifications = `-- name: GetNotifications :many
+SELECT id, user_id, file_sha_id, title, body, read_at, created_at FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+`
+
+type GetNotificationsParams struct {
+	UserID int32
+	Limit  int32
+}
+
+func (q *Queries) GetNotifications(ctx context.Context, arg GetNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.QueryContext(ctx, getNotifications, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.FileShaID,
+			&i.Title,
+			&i.Body,
+			&i.ReadAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrphanAttachments = `-- name: GetOrphanAttachments :many
+SELECT id, sha_id, note_sha_id, user_id, name, mime_type, size, checksum, created_at FROM attachments
+WHERE NOT EXISTS (SELECT 1 FROM files WHERE files.sha_id = attachments.note_sha_id)
+ORDER BY id
+LIMIT $1
+`
+
+func (q *Queries) GetOrphanAttachments(ctx context.Context, limit int32) ([]Attachment, error) {
+	rows, err := q.db.QueryContext(ctx, getOrphanAttachments, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShaID,
+			&i.NoteShaID,
+			&i.UserID,
+			&i.Name,
+			&i.MimeType,
+			&i.Size,
+			&i.Checksum,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublicLinks = `-- name: GetPublicLinks :many
+SELECT id, token, file_sha_id, user_id, password, expires_at, revoked_at, view_count, created_at, updated_at FROM public_links
+WHERE file_sha_id = $1 AND user_id = $2
+ORDER BY created_at DESC
+`
+
+type GetPublicLinksParams struct {
+	FileShaID string
+	UserID    int32
+}
+
+func (q *Queries) GetPublicLinks(ctx context.Context, arg GetPublicLinksParams) ([]PublicLink, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicLinks, arg.FileShaID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PublicLink
+	for rows.Next() {
+		var i PublicLink
+		if err := rows.Scan(
+			&i.ID,
+			&i.Token,
+			&i.FileShaID,
+			&i.UserID,
+			&i.Password,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.ViewCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSharedFiles = `-- name: GetSharedFiles :many
+SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, files.deleted_at, shares.role, users.username
+FROM shares
+JOIN files ON files.sha_id = shares.file_sha_id
+JOIN users ON users.id = files.user_id
+WHERE shares.user_id = $1 AND files.deleted_at IS NULL
+ORDER BY files.type, files.name
+`
+
+type GetSharedFilesRow struct {
+	ID          int32
+	FolderShaID string
+	Name        string
+	Type        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ShaID       string
+	Path        string
+	UserID      int32
+	DeletedAt   sql.NullTime
+	Role        string
+	Username    string
+}
+
+func (q *Queries) GetSharedFiles(ctx context.Context, userID int32) ([]GetSharedFilesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSharedFiles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSharedFilesRow
+	for rows.Next() {
+		var i GetSharedFilesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FolderShaID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ShaID,
+			&i.Path,
+			&i.UserID,
+			&i.DeletedAt,
+			&i.Role,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTags = `-- name: GetTags :many
+SELECT tags.id, tags.user_id, tags.name, tags.created_at, tags.updated_at, count(files.sha_id) AS usage_count
+FROM tags
+LEFT JOIN note_tags ON note_tags.tag_id = tags.id
+LEFT JOIN files ON files.sha_id = note_tags.file_sha_id AND files.deleted_at IS NULL
+WHERE tags.user_id = $1
+GROUP BY tags.id
+ORDER BY tags.name
+`
+
+type GetTagsRow struct {
+	ID         int32
+	UserID     int32
+	Name       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UsageCount int64
+}
+
+func (q *Queries) GetTags(ctx context.Context, userID int32) ([]GetTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTags, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagsRow
+	for rows.Next() {
+		var i GetTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UsageCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplates = `-- name: GetTemplates :many
+SELECT templates.file_sha_id, templates.user_id, templates.description, templates.created_at, templates.updated_at, files.folder_sha_id, files.name, files.path, users.username
+FROM templates
+JOIN files ON files.sha_id = templates.file_sha_id AND files.deleted_at IS NULL
+JOIN users ON users.id = templates.user_id
+WHERE templates.user_id = $1 OR EXISTS (
+    SELECT 1 FROM shares
+    JOIN files shared ON shared.sha_id = shares.file_sha_id AND shared.deleted_at IS NULL
+    WHERE shares.user_id = $1 AND shared.user_id = files.user_id
+    AND (shared.sha_id = files.sha_id OR (shared.type = 'folder' AND left(files.path, length(shared.path) + 1) = shared.path || '/'))
 )
+ORDER BY files.name, files.path
+`
+
+type GetTemplatesRow struct {
+	FileShaID   string
+	UserID      int32
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	FolderShaID string
+	Name        string
+	Path        string
+	Username    string
+}
+
+func (q *Queries) GetTemplates(ctx context.Context, userID int32) ([]GetTemplatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplates, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplatesRow
+	for rows.Next() {
+		var i GetTemplatesRow
+		if err := rows.Scan(
+			&i.FileShaID,
+			&i.UserID,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FolderShaID,
+			&i.Name,
+			&i.Path,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
-const findUser = `-- name: FindUser :one
+const getTrash = `-- name: GetTrash :many
+SELECT files.id, files.folder_sha_id, files.name, files.type, files.created_at, files.updated_at, files.sha_id, files.path, files.user_id, files.deleted_at
+FROM files
+LEFT JOIN files AS parent ON parent.sha_id = files.folder_sha_id
+WHERE files.user_id = $1 AND files.deleted_at IS NOT NULL
+    AND (parent.deleted_at IS NULL OR parent.deleted_at <> files.deleted_at)
+ORDER BY files.deleted_at DESC, files.path
+`
+
+func (q *Queries) GetTrash(ctx context.Context, userID int32) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, getTrash, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.FolderShaID,
+			&i.Name,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ShaID,
+			&i.Path,
+			&i.UserID,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnusedBlobs = `-- name: GetUnusedBlobs :many
+SELECT checksum, size, ref_count, stored_at, created_at, updated_at FROM blobs
+WHERE ref_count = 0
+ORDER BY updated_at
+LIMIT $1
+`
+
+func (q *Queries) GetUnusedBlobs(ctx context.Context, limit int32) ([]Blob, error) {
+	rows, err := q.db.QueryContext(ctx, getUnusedBlobs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Blob
+	for rows.Next() {
+		var i Blob
+		if err := rows.Scan(
+			&i.Checksum,
+			&i.Size,
+			&i.RefCount,
+			&i.StoredAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserReminders = `-- name: GetUserReminders :many
+SELECT reminders.id, reminders.file_sha_id, reminders.user_id, reminders.due_at, reminders.offset_minutes, reminders.remind_at, reminders.sent_at, reminders.claimed_until, reminders.attempts, reminders.last_error, reminders.delivered_channels, reminders.created_at, files.name, files.path FROM reminders
+JOIN files ON files.sha_id = reminders.file_sha_id AND files.deleted_at IS NULL
+WHERE reminders.user_id = $1 AND reminders.sent_at IS NULL
+ORDER BY reminders.remind_at, reminders.id
+`
+
+type GetUserRemindersRow struct {
+	ID                int32
+	FileShaID         string
+	UserID            int32
+	DueAt             time.Time
+	OffsetMinutes     int32
+	RemindAt          time.Time
+	SentAt            sql.NullTime
+	ClaimedUntil      sql.NullTime
+	Attempts          int32
+	LastError         string
+	DeliveredChannels []string
+	CreatedAt         time.Time
+	Name              string
+	Path              string
+}
+
+func (q *Queries) GetUserReminders(ctx context.Context, userID int32) ([]GetUserRemindersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserReminders, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserRemindersRow
+	for rows.Next() {
+		var i GetUserRemindersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileShaID,
+			&i.UserID,
+			&i.DueAt,
+			&i.OffsetMinutes,
+			&i.RemindAt,
+			&i.SentAt,
+			&i.ClaimedUntil,
+			&i.Attempts,
+			&i.LastError,
+			pq.Array(&i.DeliveredChannels),
+			&i.CreatedAt,
+			&i.Name,
+			&i.Path,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserShares = `-- name: GetUserShares :many
+SELECT id, file_sha_id, owner_id, user_id, role, created_at, updated_at FROM shares
+WHERE user_id = $1 AND file_sha_id = ANY($2::varchar[])
+`
+
+type GetUserSharesParams struct {
+	UserID int32
+	ShaIds []string
+}
+
+func (q *Queries) GetUserShares(ctx context.Context, arg GetUserSharesParams) ([]Share, error) {
+	rows, err := q.db.QueryContext(ctx, getUserShares, arg.UserID, pq.Array(arg.ShaIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Share
+	for rows.Next() {
+		var i Share
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileShaID,
+			&i.OwnerID,
+			&i.UserID,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsers = `-- name: GetUsers :many
 SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
-WHERE id = $1 LIMIT 1
 `
 
-func (q *Queries) FindUser(ctx context.Context, id int32) (User, error) {
-	row := q.db.QueryRowContext(ctx, findUser, id)
+func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.PhoneNumber,
+			&i.Password,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementPublicLinkViews = `-- name: IncrementPublicLinkViews :one
+UPDATE public_links SET view_count = view_count + 1
+WHERE id = $1
+RETURNING view_count
+`
+
+func (q *Queries) IncrementPublicLinkViews(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementPublicLinkViews, id)
+	var view_count int32
+	err := row.Scan(&view_count)
+	return view_count, err
+}
+
+const lockFolder = `-- name: LockFolder :one
+SELECT sha_id FROM files
+WHERE sha_id = $1 AND user_id = $2 AND type = 'folder' AND deleted_at IS NULL
+FOR SHARE
+`
+
+type LockFolderParams struct {
+	ShaID  string
+	UserID int32
+}
+
+func (q *Queries) LockFolder(ctx context.Context, arg LockFolderParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, lockFolder, arg.ShaID, arg.UserID)
+	var sha_id string
+	err := row.Scan(&sha_id)
+	return sha_id, err
+}
+
+const lockUnusedBlob = `-- name: LockUnusedBlob :one
+SELECT checksum, size, ref_count, stored_at, created_at, updated_at FROM blobs
+WHERE checksum = $1 AND ref_count = 0
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) LockUnusedBlob(ctx context.Context, checksum string) (Blob, error) {
+	row := q.db.QueryRowContext(ctx, lockUnusedBlob, checksum)
+	var i Blob
+	err := row.Scan(
+		&i.Checksum,
+		&i.Size,
+		&i.RefCount,
+		&i.StoredAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const lockUser = `-- name: LockUser :exec
+SELECT id FROM users
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockUser(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, lockUser, id)
+	return err
+}
+
+const login = `-- name: Login :one
+SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
+WHERE username = $1 AND password = $2 LIMIT 1
+`
+
+type LoginParams struct {
+	Username string
+	Password string
+}
+
+func (q *Queries) Login(ctx context.Context, arg LoginParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, login, arg.Username, arg.Password)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
+const markBlobStored = `-- name: MarkBlobStored :exec
+UPDATE blobs SET stored_at = $2
+WHERE checksum = $1
+`
+
+type MarkBlobStoredParams struct {
+	Checksum string
+	StoredAt sql.NullTime
+}
+
+func (q *Queries) MarkBlobStored(ctx context.Context, arg MarkBlobStoredParams) error {
+	_, err := q.db.ExecContext(ctx, markBlobStored, arg.Checksum, arg.StoredAt)
+	return err
+}
+
+const markReminderSent = `-- name: MarkReminderSent :exec
+UPDATE reminders SET sent_at = $2, claimed_until = NULL, last_error = ''
+WHERE id = $1
+`
+
+type MarkReminderSentParams struct {
+	ID     int32
+	SentAt sql.NullTime
+}
+
+func (q *Queries) MarkReminderSent(ctx context.Context, arg MarkReminderSentParams) error {
+	_, err := q.db.ExecContext(ctx, markReminderSent, arg.ID, arg.SentAt)
+	return err
+}
+
+const mergeTagNotes = `-- name: MergeTagNotes :exec
+INSERT INTO note_tags (file_sha_id, tag_id, created_at)
+SELECT note_tags.file_sha_id, $1::int, $2::timestamp
+FROM note_tags
+WHERE note_tags.tag_id = $3
+ON CONFLICT DO NOTHING
+`
+
+type MergeTagNotesParams struct {
+	IntoTagID int32
+	CreatedAt time.Time
+	FromTagID int32
+}
+
+func (q *Queries) MergeTagNotes(ctx context.Context, arg MergeTagNotesParams) error {
+	_, err := q.db.ExecContext(ctx, mergeTagNotes, arg.IntoTagID, arg.CreatedAt, arg.FromTagID)
+	return err
+}
+
+const pruneNoteRevisions = `-- name: PruneNoteRevisions :exec
+DELETE FROM note_revisions
+WHERE note_revisions.file_sha_id = $1 AND note_revisions.id NOT IN (
+    SELECT kept.id FROM note_revisions AS kept
+    WHERE kept.file_sha_id = $1
+    ORDER BY kept.id DESC
+    LIMIT $2::int
+)
+`
+
+type PruneNoteRevisionsParams struct {
+	FileShaID string
+	Keep      int32
+}
+
+func (q *Queries) PruneNoteRevisions(ctx context.Context, arg PruneNoteRevisionsParams) error {
+	_, err := q.db.ExecContext(ctx, pruneNoteRevisions, arg.FileShaID, arg.Keep)
+	return err
+}
+
+const readNotification = `-- name: ReadNotification :execrows
+UPDATE notifications SET read_at = COALESCE(read_at, $3)
+WHERE id = $1 AND user_id = $2
+`
+
+type ReadNotificationParams struct {
+	ID     int32
+	UserID int32
+	ReadAt sql.NullTime
+}
+
+func (q *Queries) ReadNotification(ctx context.Context, arg ReadNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, readNotification, arg.ID, arg.UserID, arg.ReadAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const register = `-- name: Register :one
+INSERT INTO users (username, email, phone_number, password, created_at, updated_at, name)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, username, email, phone_number, password, created_at, updated_at, name
+`
+
+type RegisterParams struct {
+	Username    string
+	Email       sql.NullString
+	PhoneNumber sql.NullString
+	Password    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+}
+
+func (q *Queries) Register(ctx context.Context, arg RegisterParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, register,
+		arg.Username,
+		arg.Email,
+		arg.PhoneNumber,
+		arg.Password,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -32,118 +3044,218 @@ func (q *Queries) FindUser(ctx context.Context, id int32) (User, error) {
 	return i, err
 }
 
-const findUserByEmail = `-- name: FindUserByEmail :one
-SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
-WHERE email = $1 LIMIT 1
+const removeBlobReference = `-- name: RemoveBlobReference :exec
+UPDATE blobs SET ref_count = ref_count - 1, updated_at = $2
+WHERE checksum = $1
 `
 
-func (q *Queries) FindUserByEmail(ctx context.Context, email sql.NullString) (User, error) {
-	row := q.db.QueryRowContext(ctx, findUserByEmail, email)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Username,
-		&i.Email,
-		&i.PhoneNumber,
-		&i.Password,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-	)
-	return i, err
+type RemoveBlobReferenceParams struct {
+	Checksum  string
+	UpdatedAt time.Time
 }
 
-const findUserByPhoneNumber = `-- name: FindUserByPhoneNumber :one
-SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
-WHERE phone_number = $1 LIMIT 1
+func (q *Queries) RemoveBlobReference(ctx context.Context, arg RemoveBlobReferenceParams) error {
+	_, err := q.db.ExecContext(ctx, removeBlobReference, arg.Checksum, arg.UpdatedAt)
+	return err
+}
+
+const renameTag = `-- name: RenameTag :one
+UPDATE tags SET name = $3, updated_at = $4
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, name, created_at, updated_at
 `
 
-func (q *Queries) FindUserByPhoneNumber(ctx context.Context, phoneNumber sql.NullString) (User, error) {
-	row := q.db.QueryRowContext(ctx, findUserByPhoneNumber, phoneNumber)
-	var i User
+type RenameTagParams struct {
+	ID        int32
+	UserID    int32
+	Name      string
+	UpdatedAt time.Time
+}
+
+func (q *Queries) RenameTag(ctx context.Context, arg RenameTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, renameTag,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.UpdatedAt,
+	)
+	var i Tag
 	err := row.Scan(
 		&i.ID,
-		&i.Username,
-		&i.Email,
-		&i.PhoneNumber,
-		&i.Password,
+		&i.UserID,
+		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Name,
 	)
 	return i, err
 }
 
-const findUserByUsername = `-- name: FindUserByUsername :one
-SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
-WHERE username = $1 LIMIT 1
+const resolveNoteLinks = `-- name: ResolveNoteLinks :exec
+UPDATE note_links SET target_sha_id = $1
+WHERE user_id = $2 AND target_sha_id IS NULL
+AND right(lower($3::text), length(target) + 1) = '/' || lower(target)
 `
 
-func (q *Queries) FindUserByUsername(ctx context.Context, username string) (User, error) {
-	row := q.db.QueryRowContext(ctx, findUserByUsername, username)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Username,
-		&i.Email,
-		&i.PhoneNumber,
-		&i.Password,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-	)
-	return i, err
+type ResolveNoteLinksParams struct {
+	TargetShaID sql.NullString
+	UserID      int32
+	Path        string
 }
 
-const findUserByUsernameOrEmailOrPhoneNumber = `-- name: FindUserByUsernameOrEmailOrPhoneNumber :one
-SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
-WHERE username = $1 OR( email = $2 and email IS NOT NULL) OR (phone_number = $3 and phone_number IS NOT NULL) LIMIT 1
+func (q *Queries) ResolveNoteLinks(ctx context.Context, arg ResolveNoteLinksParams) error {
+	_, err := q.db.ExecContext(ctx, resolveNoteLinks, arg.TargetShaID, arg.UserID, arg.Path)
+	return err
+}
+
+const restoreFiles = `-- name: RestoreFiles :exec
+UPDATE files SET deleted_at = NULL
+WHERE user_id = $1 AND sha_id = ANY($2::varchar[])
 `
 
-type FindUserByUsernameOrEmailOrPhoneNumberParams struct {
-	Username    string
-	Email       sql.NullString
-	PhoneNumber sql.NullString
+type RestoreFilesParams struct {
+	UserID int32
+	ShaIds []string
 }
 
-func (q *Queries) FindUserByUsernameOrEmailOrPhoneNumber(ctx context.Context, arg FindUserByUsernameOrEmailOrPhoneNumberParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, findUserByUsernameOrEmailOrPhoneNumber, arg.Username, arg.Email, arg.PhoneNumber)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Username,
-		&i.Email,
-		&i.PhoneNumber,
-		&i.Password,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
+func (q *Queries) RestoreFiles(ctx context.Context, arg RestoreFilesParams) error {
+	_, err := q.db.ExecContext(ctx, restoreFiles, arg.UserID, pq.Array(arg.ShaIds))
+	return err
+}
+
+const restoreFolders = `-- name: RestoreFolders :exec
+UPDATE folders SET deleted_at = NULL
+WHERE sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) RestoreFolders(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, restoreFolders, pq.Array(shaIds))
+	return err
+}
+
+const restoreNotes = `-- name: RestoreNotes :exec
+UPDATE notes SET deleted_at = NULL
+WHERE file_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) RestoreNotes(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, restoreNotes, pq.Array(shaIds))
+	return err
+}
+
+const retryReminder = `-- name: RetryReminder :exec
+UPDATE reminders SET claimed_until = $2, last_error = $3, delivered_channels = $4
+WHERE id = $1
+`
+
+type RetryReminderParams struct {
+	ID                int32
+	ClaimedUntil      sql.NullTime
+	LastError         string
+	DeliveredChannels []string
+}
+
+func (q *Queries) RetryReminder(ctx context.Context, arg RetryReminderParams) error {
+	_, err := q.db.ExecContext(ctx, retryReminder,
+		arg.ID,
+		arg.ClaimedUntil,
+		arg.LastError,
+		pq.Array(arg.DeliveredChannels),
 	)
-	return i, err
+	return err
 }
 
-const getUsers = `-- name: GetUsers :many
-SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
+const revokePublicLink = `-- name: RevokePublicLink :execrows
+UPDATE public_links SET revoked_at = $3, updated_at = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
 `
 
-func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getUsers)
+type RevokePublicLinkParams struct {
+	ID        int32
+	UserID    int32
+	RevokedAt sql.NullTime
+}
+
+func (q *Queries) RevokePublicLink(ctx context.Context, arg RevokePublicLinkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokePublicLink, arg.ID, arg.UserID, arg.RevokedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchNotes = `-- name: SearchNotes :many
+WITH RECURSIVE subtree AS (
+    SELECT files.sha_id, ARRAY[files.sha_id]::varchar[] AS visited
+    FROM files
+    WHERE files.sha_id = $1 AND files.user_id = $2 AND files.type = 'folder'
+    UNION ALL
+    SELECT files.sha_id, subtree.visited || files.sha_id
+    FROM files
+    JOIN subtree ON files.folder_sha_id = subtree.sha_id
+    WHERE files.user_id = $2 AND files.type = 'folder' AND NOT files.sha_id = ANY(subtree.visited)
+)
+SELECT files.sha_id, files.folder_sha_id, files.name, files.path, notes.updated_at,
+    ts_headline('english', files.name, websearch_to_tsquery('english', $3), E'StartSel=\x02, StopSel=\x03, HighlightAll=true')::text AS name_highlight,
+    ts_headline('english', COALESCE(notes.note, ''), websearch_to_tsquery('english', $3), E'StartSel=\x02, StopSel=\x03, MaxFragments=2')::text AS snippet,
+    ts_rank(
+        setweight(to_tsvector('english', files.name), 'A') || setweight(to_tsvector('english', COALESCE(notes.note, '')), 'B'),
+        websearch_to_tsquery('english', $3)
+    )::real AS rank
+FROM notes
+JOIN files ON files.sha_id = notes.file_sha_id
+WHERE files.user_id = $2 AND files.type = 'note' AND files.deleted_at IS NULL
+    AND ($1::varchar = '' OR files.folder_sha_id IN (SELECT subtree.sha_id FROM subtree))
+    AND (
+        to_tsvector('english', files.name) @@ websearch_to_tsquery('english', $3)
+        OR to_tsvector('english', COALESCE(notes.note, '')) @@ websearch_to_tsquery('english', $3)
+    )
+ORDER BY rank DESC, notes.updated_at DESC
+LIMIT $4::int OFFSET $5::int
+`
+
+type SearchNotesParams struct {
+	FolderShaID  string
+	UserID       int32
+	Query        string
+	ResultLimit  int32
+	ResultOffset int32
+}
+
+type SearchNotesRow struct {
+	ShaID         string
+	FolderShaID   string
+	Name          string
+	Path          string
+	UpdatedAt     time.Time
+	NameHighlight string
+	Snippet       string
+	Rank          float32
+}
+
+func (q *Queries) SearchNotes(ctx context.Context, arg SearchNotesParams) ([]SearchNotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchNotes,
+		arg.FolderShaID,
+		arg.UserID,
+		arg.Query,
+		arg.ResultLimit,
+		arg.ResultOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []SearchNotesRow
 	for rows.Next() {
-		var i User
+		var i SearchNotesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Username,
-			&i.Email,
-			&i.PhoneNumber,
-			&i.Password,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.ShaID,
+			&i.FolderShaID,
 			&i.Name,
+			&i.Path,
+			&i.UpdatedAt,
+			&i.NameHighlight,
+			&i.Snippet,
+			&i.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -158,68 +3270,307 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
-const login = `-- name: Login :one
-SELECT id, username, email, phone_number, password, created_at, updated_at, name FROM users
-WHERE username = $1 AND password = $2 LIMIT 1
+const touchNote = `-- name: TouchNote :exec
+UPDATE notes SET updated_at = $2
+WHERE file_sha_id = $1
 `
 
-type LoginParams struct {
-	Username string
-	Password string
+type TouchNoteParams struct {
+	FileShaID string
+	UpdatedAt time.Time
 }
 
-func (q *Queries) Login(ctx context.Context, arg LoginParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, login, arg.Username, arg.Password)
-	var i User
+func (q *Queries) TouchNote(ctx context.Context, arg TouchNoteParams) error {
+	_, err := q.db.ExecContext(ctx, touchNote, arg.FileShaID, arg.UpdatedAt)
+	return err
+}
+
+const trashFiles = `-- name: TrashFiles :exec
+UPDATE files SET deleted_at = $1::timestamp
+WHERE user_id = $2 AND sha_id = ANY($3::varchar[]) AND deleted_at IS NULL
+`
+
+type TrashFilesParams struct {
+	DeletedAt time.Time
+	UserID    int32
+	ShaIds    []string
+}
+
+func (q *Queries) TrashFiles(ctx context.Context, arg TrashFilesParams) error {
+	_, err := q.db.ExecContext(ctx, trashFiles, arg.DeletedAt, arg.UserID, pq.Array(arg.ShaIds))
+	return err
+}
+
+const trashFolders = `-- name: TrashFolders :exec
+UPDATE folders SET deleted_at = $1::timestamp
+WHERE sha_id = ANY($2::varchar[]) AND deleted_at IS NULL
+`
+
+type TrashFoldersParams struct {
+	DeletedAt time.Time
+	ShaIds    []string
+}
+
+func (q *Queries) TrashFolders(ctx context.Context, arg TrashFoldersParams) error {
+	_, err := q.db.ExecContext(ctx, trashFolders, arg.DeletedAt, pq.Array(arg.ShaIds))
+	return err
+}
+
+const trashNotes = `-- name: TrashNotes :exec
+UPDATE notes SET deleted_at = $1::timestamp
+WHERE file_sha_id = ANY($2::varchar[]) AND deleted_at IS NULL
+`
+
+type TrashNotesParams struct {
+	DeletedAt time.Time
+	ShaIds    []string
+}
+
+func (q *Queries) TrashNotes(ctx context.Context, arg TrashNotesParams) error {
+	_, err := q.db.ExecContext(ctx, trashNotes, arg.DeletedAt, pq.Array(arg.ShaIds))
+	return err
+}
+
+const unresolveNoteLinks = `-- name: UnresolveNoteLinks :exec
+UPDATE note_links SET target_sha_id = NULL
+WHERE target_sha_id = ANY($1::varchar[])
+`
+
+func (q *Queries) UnresolveNoteLinks(ctx context.Context, shaIds []string) error {
+	_, err := q.db.ExecContext(ctx, unresolveNoteLinks, pq.Array(shaIds))
+	return err
+}
+
+const updateFile = `-- name: UpdateFile :one
+UPDATE files SET folder_sha_id = $3, name = $4, path = $5, updated_at = $6
+WHERE sha_id = $1 AND user_id = $2
+RETURNING id, folder_sha_id, name, type, created_at, updated_at, sha_id, path, user_id, deleted_at
+`
+
+type UpdateFileParams struct {
+	ShaID       string
+	UserID      int32
+	FolderShaID string
+	Name        string
+	Path        string
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) UpdateFile(ctx context.Context, arg UpdateFileParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, updateFile,
+		arg.ShaID,
+		arg.UserID,
+		arg.FolderShaID,
+		arg.Name,
+		arg.Path,
+		arg.UpdatedAt,
+	)
+	var i File
 	err := row.Scan(
 		&i.ID,
-		&i.Username,
-		&i.Email,
-		&i.PhoneNumber,
-		&i.Password,
+		&i.FolderShaID,
+		&i.Name,
+		&i.Type,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Name,
+		&i.ShaID,
+		&i.Path,
+		&i.UserID,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const register = `-- name: Register :one
-INSERT INTO users (username, email, phone_number, password, created_at, updated_at, name)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, username, email, phone_number, password, created_at, updated_at, name
+const updateFilePathPrefix = `-- name: UpdateFilePathPrefix :exec
+UPDATE files SET path = $1::text || substr(path, length($2::text) + 1), updated_at = $3
+WHERE user_id = $4 AND sha_id = ANY($5::varchar[])
 `
 
-type RegisterParams struct {
-	Username    string
-	Email       sql.NullString
-	PhoneNumber sql.NullString
-	Password    string
-	CreatedAt   time.Time
+type UpdateFilePathPrefixParams struct {
+	NewPath   string
+	OldPath   string
+	UpdatedAt time.Time
+	UserID    int32
+	ShaIds    []string
+}
+
+func (q *Queries) UpdateFilePathPrefix(ctx context.Context, arg UpdateFilePathPrefixParams) error {
+	_, err := q.db.ExecContext(ctx, updateFilePathPrefix,
+		arg.NewPath,
+		arg.OldPath,
+		arg.UpdatedAt,
+		arg.UserID,
+		pq.Array(arg.ShaIds),
+	)
+	return err
+}
+
+const updateFolderParentBySha = `-- name: UpdateFolderParentBySha :exec
+UPDATE folders SET parent_id = (SELECT parent.id FROM folders AS parent WHERE parent.sha_id = $1), updated_at = $2
+WHERE folders.sha_id = $3
+`
+
+type UpdateFolderParentByShaParams struct {
+	ParentShaID string
 	UpdatedAt   time.Time
-	Name        string
+	ShaID       string
 }
 
-func (q *Queries) Register(ctx context.Context, arg RegisterParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, register,
-		arg.Username,
-		arg.Email,
-		arg.PhoneNumber,
-		arg.Password,
+func (q *Queries) UpdateFolderParentBySha(ctx context.Context, arg UpdateFolderParentByShaParams) error {
+	_, err := q.db.ExecContext(ctx, updateFolderParentBySha, arg.ParentShaID, arg.UpdatedAt, arg.ShaID)
+	return err
+}
+
+const updateNote = `-- name: UpdateNote :one
+UPDATE notes SET note = $2, updated_at = $3
+WHERE file_sha_id = $1
+RETURNING id, file_sha_id, note, created_at, updated_at, deleted_at
+`
+
+type UpdateNoteParams struct {
+	FileShaID string
+	Note      sql.NullString
+	UpdatedAt time.Time
+}
+
+func (q *Queries) UpdateNote(ctx context.Context, arg UpdateNoteParams) (Note, error) {
+	row := q.db.QueryRowContext(ctx, updateNote, arg.FileShaID, arg.Note, arg.UpdatedAt)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.FileShaID,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const upsertShare = `-- name: UpsertShare :one
+INSERT INTO shares (file_sha_id, owner_id, user_id, role, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (file_sha_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at
+RETURNING id, file_sha_id, owner_id, user_id, role, created_at, updated_at
+`
+
+type UpsertShareParams struct {
+	FileShaID string
+	OwnerID   int32
+	UserID    int32
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) UpsertShare(ctx context.Context, arg UpsertShareParams) (Share, error) {
+	row := q.db.QueryRowContext(ctx, upsertShare,
+		arg.FileShaID,
+		arg.OwnerID,
+		arg.UserID,
+		arg.Role,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.Name,
 	)
-	var i User
+	var i Share
 	err := row.Scan(
 		&i.ID,
-		&i.Username,
-		&i.Email,
-		&i.PhoneNumber,
-		&i.Password,
+		&i.FileShaID,
+		&i.OwnerID,
+		&i.UserID,
+		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTag = `-- name: UpsertTag :one
+INSERT INTO tags (user_id, name, created_at, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, name) DO UPDATE SET updated_at = tags.updated_at
+RETURNING id, user_id, name, created_at, updated_at
+`
+
+type UpsertTagParams struct {
+	UserID    int32
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) UpsertTag(ctx context.Context, arg UpsertTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, upsertTag,
+		arg.UserID,
+		arg.Name,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
 		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTemplate = `-- name: UpsertTemplate :one
+INSERT INTO templates (file_sha_id, user_id, description, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (file_sha_id) DO UPDATE SET description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+RETURNING file_sha_id, user_id, description, created_at, updated_at
+`
+
+type UpsertTemplateParams struct {
+	FileShaID   string
+	UserID      int32
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) UpsertTemplate(ctx context.Context, arg UpsertTemplateParams) (Template, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplate,
+		arg.FileShaID,
+		arg.UserID,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Template
+	err := row.Scan(
+		&i.FileShaID,
+		&i.UserID,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserSettings = `-- name: UpsertUserSettings :one
+INSERT INTO user_settings (user_id, note_revision_retention, updated_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET note_revision_retention = EXCLUDED.note_revision_retention, updated_at = EXCLUDED.updated_at
+RETURNING user_id, note_revision_retention, updated_at
+`
+
+type UpsertUserSettingsParams struct {
+	UserID                int32
+	NoteRevisionRetention int32
+	UpdatedAt             time.Time
+}
+
+func (q *Queries) UpsertUserSettings(ctx context.Context, arg UpsertUserSettingsParams) (UserSetting, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserSettings, arg.UserID, arg.NoteRevisionRetention, arg.UpdatedAt)
+	var i UserSetting
+	err := row.Scan(
+		&i.UserID,
+		&i.NoteRevisionRetention,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
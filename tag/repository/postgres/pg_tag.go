@@ -0,0 +1,195 @@
+package tag_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+)
+
+type postgresTagRepo struct {
+	DB     *sql.DB
+	Source sqlcpg.Querier
+}
+
+// UpsertTag implements domain.TagRepo
+func (p postgresTagRepo) UpsertTag(ctx context.Context, userID int, name string) (domain.Tag, error) {
+	now := time.Now()
+
+	data, err := p.Source.UpsertTag(ctx, sqlcpg.UpsertTagParams{
+		UserID:    int32(userID),
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return toDomainTag(data), nil
+}
+
+// FindTag implements domain.TagRepo
+func (p postgresTagRepo) FindTag(ctx context.Context, userID int, id int) (domain.Tag, error) {
+	data, err := p.Source.FindTag(ctx, sqlcpg.FindTagParams{
+		ID:     int32(id),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return toDomainTag(data), nil
+}
+
+// GetTags implements domain.TagRepo
+func (p postgresTagRepo) GetTags(ctx context.Context, userID int) ([]domain.Tag, error) {
+	data, err := p.Source.GetTags(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []domain.Tag{}
+	for _, v := range data {
+		tag := toDomainTag(sqlcpg.Tag{
+			ID:        v.ID,
+			UserID:    v.UserID,
+			Name:      v.Name,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		})
+		tag.UsageCount = int(v.UsageCount)
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// GetNoteTags implements domain.TagRepo
+func (p postgresTagRepo) GetNoteTags(ctx context.Context, userID int, shaID string) ([]domain.Tag, error) {
+	data, err := p.Source.GetNoteTags(ctx, sqlcpg.GetNoteTagsParams{
+		FileShaID: shaID,
+		UserID:    int32(userID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []domain.Tag{}
+	for _, v := range data {
+		tags = append(tags, toDomainTag(v))
+	}
+
+	return tags, nil
+}
+
+// AttachTag implements domain.TagRepo
+func (p postgresTagRepo) AttachTag(ctx context.Context, shaID string, tagID int) error {
+	return p.Source.AttachTag(ctx, sqlcpg.AttachTagParams{
+		FileShaID: shaID,
+		TagID:     int32(tagID),
+		CreatedAt: time.Now(),
+	})
+}
+
+// DetachTag implements domain.TagRepo
+func (p postgresTagRepo) DetachTag(ctx context.Context, shaID string, tagID int) error {
+	rows, err := p.Source.DetachTag(ctx, sqlcpg.DetachTagParams{
+		FileShaID: shaID,
+		TagID:     int32(tagID),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RenameTag implements domain.TagRepo
+func (p postgresTagRepo) RenameTag(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	data, err := p.Source.RenameTag(ctx, sqlcpg.RenameTagParams{
+		ID:        int32(tag.ID),
+		UserID:    int32(tag.UserID),
+		Name:      tag.Name,
+		UpdatedAt: time.Now(),
+	})
+	if helpers.IsUniqueViolation(err, "tags_user_id_name") {
+		return domain.Tag{}, fmt.Errorf("%w: tag name already used, merge the tags instead", domain.ErrConflict)
+	}
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return toDomainTag(data), nil
+}
+
+// MergeTag implements domain.TagRepo
+func (p postgresTagRepo) MergeTag(ctx context.Context, userID int, fromID int, intoID int) error {
+	return helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		// notes already tagged with both keep a single row
+		err := q.MergeTagNotes(ctx, sqlcpg.MergeTagNotesParams{
+			IntoTagID: int32(intoID),
+			CreatedAt: time.Now(),
+			FromTagID: int32(fromID),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteTagNotes(ctx, int32(fromID))
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteTag(ctx, sqlcpg.DeleteTagParams{
+			ID:     int32(fromID),
+			UserID: int32(userID),
+		})
+	})
+}
+
+// DeleteTag implements domain.TagRepo
+func (p postgresTagRepo) DeleteTag(ctx context.Context, userID int, id int) error {
+	return helpers.RunInTx(ctx, p.DB, func(tx *sql.Tx) error {
+		q := sqlcpg.New(tx)
+
+		err := q.DeleteTagNotes(ctx, int32(id))
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteTag(ctx, sqlcpg.DeleteTagParams{
+			ID:     int32(id),
+			UserID: int32(userID),
+		})
+	})
+}
+
+func toDomainTag(data sqlcpg.Tag) domain.Tag {
+	return domain.Tag{
+		ID:        int(data.ID),
+		UserID:    int(data.UserID),
+		Name:      data.Name,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+}
+
+func NewPostgresTagRepo(db *sql.DB, source sqlcpg.Querier) domain.TagRepo {
+	return &postgresTagRepo{
+		DB:     db,
+		Source: source,
+	}
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+)
+
+const maxTagNameLength = 100
+
+type TagUseCaseImpl struct {
+	TagRepo     domain.TagRepo
+	NoteUsecase domain.NoteUsecase
+}
+
+// GetTags implements domain.TagUsecase
+func (t TagUseCaseImpl) GetTags(ctx context.Context, userID int) ([]domain.Tag, error) {
+	// call repository
+	return t.TagRepo.GetTags(ctx, userID)
+}
+
+// GetNoteTags implements domain.TagUsecase
+func (t TagUseCaseImpl) GetNoteTags(ctx context.Context, userID int, shaID string) ([]domain.Tag, error) {
+	// make sure the note belongs to the user
+	_, err := t.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return nil, err
+	}
+
+	// call repository
+	return t.TagRepo.GetNoteTags(ctx, userID, shaID)
+}
+
+// AttachTag implements domain.TagUsecase
+func (t TagUseCaseImpl) AttachTag(ctx context.Context, userID int, shaID string, name string) (domain.Tag, error) {
+	name, err := validateTagName(name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	_, err = t.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	// the tag is created on first use
+	tag, err := t.TagRepo.UpsertTag(ctx, userID, name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	err = t.TagRepo.AttachTag(ctx, shaID, tag.ID)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// DetachTag implements domain.TagUsecase
+func (t TagUseCaseImpl) DetachTag(ctx context.Context, userID int, shaID string, tagID int) error {
+	_, err := t.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.findTag(ctx, userID, tagID)
+	if err != nil {
+		return err
+	}
+
+	// call repository
+	err = t.TagRepo.DetachTag(ctx, shaID, tagID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: note is not tagged with the tag", domain.ErrNotFound)
+	}
+
+	return err
+}
+
+// RenameTag implements domain.TagUsecase
+func (t TagUseCaseImpl) RenameTag(ctx context.Context, userID int, id int, name string) (domain.Tag, error) {
+	name, err := validateTagName(name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	tag, err := t.findTag(ctx, userID, id)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	if tag.Name == name {
+		return tag, nil
+	}
+
+	// renaming onto an existing tag is a merge, let the client ask for it
+	tag.Name = name
+	return t.TagRepo.RenameTag(ctx, tag)
+}
+
+// MergeTag implements domain.TagUsecase
+func (t TagUseCaseImpl) MergeTag(ctx context.Context, userID int, fromID int, intoID int) (domain.Tag, error) {
+	if fromID == intoID {
+		return domain.Tag{}, fmt.Errorf("%w: cannot merge a tag into itself", domain.ErrBadParamInput)
+	}
+
+	_, err := t.findTag(ctx, userID, fromID)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	_, err = t.findTag(ctx, userID, intoID)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	// call repository
+	err = t.TagRepo.MergeTag(ctx, userID, fromID, intoID)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return t.findTag(ctx, userID, intoID)
+}
+
+// DeleteTag implements domain.TagUsecase
+func (t TagUseCaseImpl) DeleteTag(ctx context.Context, userID int, id int) error {
+	_, err := t.findTag(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	// call repository
+	return t.TagRepo.DeleteTag(ctx, userID, id)
+}
+
+func (t TagUseCaseImpl) findTag(ctx context.Context, userID int, id int) (domain.Tag, error) {
+	tag, err := t.TagRepo.FindTag(ctx, userID, id)
+	if err == sql.ErrNoRows {
+		return domain.Tag{}, fmt.Errorf("%w: tag not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// validateTagName trims the name and checks it fits the tags table
+func validateTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: tag name cannot be empty", domain.ErrBadParamInput)
+	}
+
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", fmt.Errorf("%w: tag name cannot be longer than %d characters", domain.ErrBadParamInput, maxTagNameLength)
+	}
+
+	// tags are passed around comma separated when filtering notes
+	if strings.Contains(name, ",") {
+		return "", fmt.Errorf("%w: tag name cannot contain a comma", domain.ErrBadParamInput)
+	}
+
+	return name, nil
+}
+
+func NewTagUseCase(tr domain.TagRepo, nu domain.NoteUsecase) domain.TagUsecase {
+	return &TagUseCaseImpl{
+		TagRepo:     tr,
+		NoteUsecase: nu,
+	}
+}
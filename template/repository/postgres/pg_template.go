@@ -0,0 +1,107 @@
+package template_repo_pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/sqlcpg"
+	"gopkg.in/guregu/null.v4"
+)
+
+type postgresTemplateRepo struct {
+	Source sqlcpg.Querier
+}
+
+// UpsertTemplate implements domain.TemplateRepo
+func (p postgresTemplateRepo) UpsertTemplate(ctx context.Context, template domain.Template) (domain.Template, error) {
+	now := time.Now()
+
+	data, err := p.Source.UpsertTemplate(ctx, sqlcpg.UpsertTemplateParams{
+		FileShaID:   template.ShaID,
+		UserID:      int32(template.UserID),
+		Description: template.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return domain.Template{}, err
+	}
+
+	return toDomainTemplate(data), nil
+}
+
+// FindTemplate implements domain.TemplateRepo
+func (p postgresTemplateRepo) FindTemplate(ctx context.Context, shaID string) (domain.Template, error) {
+	data, err := p.Source.FindTemplate(ctx, shaID)
+	if err != nil {
+		return domain.Template{}, err
+	}
+
+	template := toDomainTemplate(sqlcpg.Template{
+		FileShaID:   data.FileShaID,
+		UserID:      data.UserID,
+		Description: data.Description,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	})
+	template.Owner = data.Username
+
+	return template, nil
+}
+
+// GetTemplates implements domain.TemplateRepo
+func (p postgresTemplateRepo) GetTemplates(ctx context.Context, userID int) ([]domain.Template, error) {
+	data, err := p.Source.GetTemplates(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := []domain.Template{}
+	for _, v := range data {
+		template := toDomainTemplate(sqlcpg.Template{
+			FileShaID:   v.FileShaID,
+			UserID:      v.UserID,
+			Description: v.Description,
+			CreatedAt:   v.CreatedAt,
+			UpdatedAt:   v.UpdatedAt,
+		})
+		template.FolderShaID = null.NewString(v.FolderShaID, v.FolderShaID != "")
+		template.Owner = v.Username
+		template.Name = v.Name
+		template.Path = v.Path
+
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// DeleteTemplate implements domain.TemplateRepo
+func (p postgresTemplateRepo) DeleteTemplate(ctx context.Context, shaID string) error {
+	rows, err := p.Source.DeleteTemplate(ctx, shaID)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func toDomainTemplate(data sqlcpg.Template) domain.Template {
+	return domain.Template{
+		ShaID:       data.FileShaID,
+		UserID:      int(data.UserID),
+		Description: data.Description,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func NewPostgresTemplateRepo(source sqlcpg.Querier) domain.TemplateRepo {
+	return &postgresTemplateRepo{source}
+}
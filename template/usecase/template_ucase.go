@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	// the time zones are embedded, the image has no zoneinfo
+	_ "time/tzdata"
+
+	"github.com/ihsanbudiman/notes_app/domain"
+	"github.com/ihsanbudiman/notes_app/helpers"
+	"gopkg.in/guregu/null.v4"
+)
+
+// placeholders filled by the server, every other placeholder is a prompt
+const (
+	placeholderDate     = "date"
+	placeholderTime     = "time"
+	placeholderUserName = "user.name"
+	placeholderFolder   = "folder"
+)
+
+var builtinPlaceholders = map[string]bool{
+	placeholderDate:     true,
+	placeholderTime:     true,
+	placeholderUserName: true,
+	placeholderFolder:   true,
+}
+
+type TemplateUseCaseImpl struct {
+	TemplateRepo  domain.TemplateRepo
+	NoteUsecase   domain.NoteUsecase
+	FolderUsecase domain.FolderUsecase
+	UserUsecase   domain.UserUsecase
+	ShareUsecase  domain.ShareUsecase
+}
+
+// GetTemplates implements domain.TemplateUsecase
+func (t TemplateUseCaseImpl) GetTemplates(ctx context.Context, userID int) ([]domain.Template, error) {
+	// call repository
+	return t.TemplateRepo.GetTemplates(ctx, userID)
+}
+
+// FindTemplate implements domain.TemplateUsecase
+func (t TemplateUseCaseImpl) FindTemplate(ctx context.Context, userID int, shaID string) (domain.Template, error) {
+	// the note usecase checks the user can see the note
+	note, err := t.NoteUsecase.FindNote(ctx, userID, shaID)
+	if err != nil {
+		return domain.Template{}, err
+	}
+
+	// call repository
+	template, err := t.TemplateRepo.FindTemplate(ctx, note.ShaID)
+	if err == sql.ErrNoRows {
+		return domain.Template{}, fmt.Errorf("%w: template not found", domain.ErrNotFound)
+	}
+
+	if err != nil {
+		return domain.Template{}, err
+	}
+
+	template.FolderShaID = note.FolderShaID
+	template.Name = note.Name
+	template.Path = note.Path
+	template.Note = note.Note
+
+	template.Prompts = []string{}
+	for _, name := range helpers.TemplatePlaceholders(note.Note.String) {
+		if !builtinPlaceholders[name] {
+			template.Prompts = append(template.Prompts, name)
+		}
+	}
+
+	return template, nil
+}
+
+// SetTemplate implements domain.TemplateUsecase
+func (t TemplateUseCaseImpl) SetTemplate(ctx context.Context, userID int, shaID string, description string) (domain.Template, error) {
+	file, err := t.ShareUsecase.Authorize(ctx, userID, shaID, domain.ShareRoleOwner)
+	if err != nil {
+		return domain.Template{}, err
+	}
+
+	if file.Type != domain.FileTypeNote {
+		return domain.Template{}, fmt.Errorf("%w: only a note can be a template", domain.ErrBadParamInput)
+	}
+
+	// call repository
+	_, err = t.TemplateRepo.UpsertTemplate(ctx, domain.Template{
+		ShaID:       file.ShaID,
+		UserID:      file.UserID,
+		Description: strings.TrimSpace(description),
+	})
+	if err != nil {
+		return domain.Template{}, err
+	}
+
+	return t.FindTemplate(ctx, userID, file.ShaID)
+}
+
+// UnsetTemplate implements domain.TemplateUsecase
+func (t TemplateUseCaseImpl) UnsetTemplate(ctx context.Context, userID int, shaID string) error {
+	file, err := t.ShareUsecase.Authorize(ctx, userID, shaID, domain.ShareRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	// call repository
+	err = t.TemplateRepo.DeleteTemplate(ctx, file.ShaID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: template not found", domain.ErrNotFound)
+	}
+
+	return err
+}
+
+// CreateNoteFromTemplate implements domain.TemplateUsecase
+func (t TemplateUseCaseImpl) CreateNoteFromTemplate(ctx context.Context, userID int, shaID string, params domain.TemplateParams) (domain.Note, error) {
+	template, err := t.FindTemplate(ctx, userID, shaID)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	values, err := t.placeholderValues(ctx, userID, params)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	// the note is named after the template unless given
+	name := params.Name
+	if name == "" {
+		name = template.Name
+	}
+
+	name, missing := helpers.ExpandTemplate(name, values)
+	content, missingInContent := helpers.ExpandTemplate(template.Note.String, values)
+
+	for _, v := range missingInContent {
+		if !containsString(missing, v) {
+			missing = append(missing, v)
+		}
+	}
+
+	if len(missing) > 0 {
+		return domain.Note{}, fmt.Errorf("%w: missing values of %s", domain.ErrBadParamInput, strings.Join(missing, ", "))
+	}
+
+	// the note usecase checks the user can write in the folder
+	return t.NoteUsecase.CreateNote(ctx, userID, domain.Note{
+		FolderShaID: null.NewString(params.FolderShaID, params.FolderShaID != ""),
+		Name:        strings.TrimSpace(name),
+		Note:        null.StringFrom(content),
+	})
+}
+
+// placeholderValues returns the values of the placeholders filled by the
+// server with the values given by the caller over them
+func (t TemplateUseCaseImpl) placeholderValues(ctx context.Context, userID int, params domain.TemplateParams) (map[string]string, error) {
+	location := time.UTC
+	if params.TimeZone != "" {
+		var err error
+		location, err = time.LoadLocation(params.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unknown time_zone %s", domain.ErrBadParamInput, params.TimeZone)
+		}
+	}
+
+	user, err := t.UserUsecase.FindUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// empty folder means root
+	folderName := ""
+	if params.FolderShaID != "" {
+		folder, err := t.FolderUsecase.FindFolder(ctx, userID, params.FolderShaID)
+		if err != nil {
+			return nil, err
+		}
+
+		folderName = folder.Name
+	}
+
+	now := time.Now().In(location)
+	values := map[string]string{
+		placeholderDate:     now.Format("2006-01-02"),
+		placeholderTime:     now.Format("15:04"),
+		placeholderUserName: user.Name,
+		placeholderFolder:   folderName,
+	}
+
+	for name, value := range params.Values {
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewTemplateUseCase(tr domain.TemplateRepo, nu domain.NoteUsecase, fu domain.FolderUsecase, uu domain.UserUsecase, su domain.ShareUsecase) domain.TemplateUsecase {
+	return &TemplateUseCaseImpl{
+		TemplateRepo:  tr,
+		NoteUsecase:   nu,
+		FolderUsecase: fu,
+		UserUsecase:   uu,
+		ShareUsecase:  su,
+	}
+}
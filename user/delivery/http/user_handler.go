@@ -27,6 +27,8 @@ func NewUserHandler(r *chi.Mux, u domain.UserUsecase) {
 			r.Post("/register", helpers.RecoverWrap(handler.Register))
 			r.Post("/login", helpers.RecoverWrap(handler.Login))
 			r.With(middleware.MyMiddleware).Get("/", helpers.RecoverWrap(handler.FindUser))
+			r.With(middleware.MyMiddleware).Get("/settings", helpers.RecoverWrap(handler.GetUserSettings))
+			r.With(middleware.MyMiddleware).Put("/settings", helpers.RecoverWrap(handler.UpdateUserSettings))
 		})
 	})
 
@@ -139,3 +141,66 @@ func (u UserHandler) FindUser(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 
 }
+
+func (u UserHandler) GetUserSettings(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// call usecase
+	settings, err := u.UserUsecase.GetUserSettings(r.Context(), credentials.ID)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "settings found",
+		Data: map[string]interface{}{
+			"settings": settings,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (u UserHandler) UpdateUserSettings(w http.ResponseWriter, r *http.Request) {
+	credentials, err := helpers.GetCredentials(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// get request body
+	var settings domain.UserSettings
+
+	err = json.NewDecoder(r.Body).Decode(&settings)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// call usecase
+	settings, err = u.UserUsecase.UpdateUserSettings(r.Context(), credentials.ID, settings)
+	if err != nil {
+		http.Error(w, err.Error(), helpers.GetStatusCode(err))
+		return
+	}
+
+	response := helpers.HttpResponse{
+		Message: "settings updated",
+		Data: map[string]interface{}{
+			"settings": settings,
+		},
+	}
+
+	// return response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -211,6 +211,40 @@ func (p postgresUserRepo) Register(ctx context.Context, user domain.User) (domai
 	}, nil
 }
 
+// FindUserSettings implements domain.UserRepo
+func (p postgresUserRepo) FindUserSettings(ctx context.Context, userID int) (domain.UserSettings, error) {
+	data, err := p.Source.FindUserSettings(ctx, int32(userID))
+
+	if err != nil {
+		return domain.UserSettings{}, err
+	}
+
+	return domain.UserSettings{
+		UserID:                int(data.UserID),
+		NoteRevisionRetention: int(data.NoteRevisionRetention),
+		UpdatedAt:             data.UpdatedAt,
+	}, nil
+}
+
+// UpsertUserSettings implements domain.UserRepo
+func (p postgresUserRepo) UpsertUserSettings(ctx context.Context, settings domain.UserSettings) (domain.UserSettings, error) {
+	data, err := p.Source.UpsertUserSettings(ctx, sqlcpg.UpsertUserSettingsParams{
+		UserID:                int32(settings.UserID),
+		NoteRevisionRetention: int32(settings.NoteRevisionRetention),
+		UpdatedAt:             time.Now(),
+	})
+
+	if err != nil {
+		return domain.UserSettings{}, err
+	}
+
+	return domain.UserSettings{
+		UserID:                int(data.UserID),
+		NoteRevisionRetention: int(data.NoteRevisionRetention),
+		UpdatedAt:             data.UpdatedAt,
+	}, nil
+}
+
 func NewPostgresUserRepo(source sqlcpg.Querier) domain.UserRepo {
 	return &postgresUserRepo{source}
 }
@@ -185,6 +185,39 @@ func (u UserUseCaseImpl) Register(ctx context.Context, user domain.User) (domain
 	return user, nil
 }
 
+// GetUserSettings implements domain.UserUsecase
+func (u UserUseCaseImpl) GetUserSettings(ctx context.Context, userID int) (domain.UserSettings, error) {
+	// call repository
+	settings, err := u.UserRepo.FindUserSettings(ctx, userID)
+
+	// the user never changed the settings
+	if err == sql.ErrNoRows {
+		return domain.UserSettings{
+			UserID:                userID,
+			NoteRevisionRetention: domain.DefaultNoteRevisionRetention,
+		}, nil
+	}
+
+	if err != nil {
+		return domain.UserSettings{}, err
+	}
+
+	return settings, nil
+}
+
+// UpdateUserSettings implements domain.UserUsecase
+func (u UserUseCaseImpl) UpdateUserSettings(ctx context.Context, userID int, settings domain.UserSettings) (domain.UserSettings, error) {
+	// check if retention is not negative
+	if settings.NoteRevisionRetention < 0 {
+		return domain.UserSettings{}, fmt.Errorf("%w: note_revision_retention cannot be negative", domain.ErrBadParamInput)
+	}
+
+	settings.UserID = userID
+
+	// call repository
+	return u.UserRepo.UpsertUserSettings(ctx, settings)
+}
+
 func NewUserUseCase(ur domain.UserRepo) domain.UserUsecase {
 	return &UserUseCaseImpl{
 		UserRepo: ur,